@@ -49,13 +49,8 @@ func InitDB() {
 	sqlDB.SetMaxIdleConns(5)            // Maksimal 5 koneksi idle
 	sqlDB.SetConnMaxLifetime(time.Hour) // Maksimal 1 jam lifetime
 
-	// Auto migrate model User (akan kita buat di bab selanjutnya)
-	err = DB.AutoMigrate(
-		&models.User{},
-		&models.Category{},
-		&models.Product{},
-		&models.Cart{},
-	)
+	// Auto migrate seluruh model
+	err = AutoMigrate(DB)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
@@ -63,3 +58,30 @@ func InitDB() {
 	log.Println("Database migrated successfully")
 
 }
+
+// AutoMigrate membuat atau memperbarui tabel untuk seluruh model
+func AutoMigrate(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&models.User{},
+		&models.Category{},
+		&models.Product{},
+		&models.ProductVariant{},
+		&models.ProductImage{},
+		&models.Cart{},
+		&models.Transaction{},
+		&models.TransactionDetail{},
+		&models.TransactionStatusHistory{},
+		&models.IdempotencyKey{},
+		&models.PaymentNotification{},
+		&models.ProcessedPaymentEvent{},
+		&models.StockReservation{},
+		&models.StockMovement{},
+		&models.SlugRedirect{},
+		&models.PriceHistory{},
+		&models.Voucher{},
+		&models.VoucherRedemption{},
+		&models.Promotion{},
+		&models.PromotionItem{},
+		&models.PromotionTier{},
+	)
+}
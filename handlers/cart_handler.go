@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"tokogo/requests"
@@ -138,6 +139,7 @@ func (h *CartHandler) GetCart(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param product_id path int true "Product ID"
+// @Param variant_id query int false "Variant ID"
 // @Param cart body requests.UpdateCartItemRequest true "Updated quantity"
 // @Success 200 {object} responses.CartItemResponse
 // @Failure 400 {object} map[string]string
@@ -174,6 +176,16 @@ func (h *CartHandler) UpdateCartItem(c *gin.Context) {
 		return
 	}
 
+	// Variant dipilih lewat query parameter variant_id
+	variantID, err := parseVariantIDQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid variant ID",
+		})
+		return
+	}
+
 	var req requests.UpdateCartItemRequest
 
 	// Bind and validate request
@@ -195,7 +207,7 @@ func (h *CartHandler) UpdateCartItem(c *gin.Context) {
 	}
 
 	// Call service to update cart item
-	response, err := h.cartService.UpdateCartItem(userID, uint(productID), req)
+	response, err := h.cartService.UpdateCartItem(userID, uint(productID), variantID, req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
 			Error:   "update_cart_failed",
@@ -216,6 +228,7 @@ func (h *CartHandler) UpdateCartItem(c *gin.Context) {
 // @Tags Cart
 // @Produce json
 // @Param product_id path int true "Product ID"
+// @Param variant_id query int false "Variant ID"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
@@ -251,8 +264,18 @@ func (h *CartHandler) RemoveFromCart(c *gin.Context) {
 		return
 	}
 
+	// Variant dipilih lewat query parameter variant_id
+	variantID, err := parseVariantIDQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid variant ID",
+		})
+		return
+	}
+
 	// Call service to remove from cart
-	err = h.cartService.RemoveFromCart(userID, uint(productID))
+	err = h.cartService.RemoveFromCart(userID, uint(productID), variantID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
 			Error:   "remove_from_cart_failed",
@@ -354,3 +377,19 @@ func (h *CartHandler) GetCartItemCount(c *gin.Context) {
 		Data:    gin.H{"count": count},
 	})
 }
+
+// parseVariantIDQuery membaca query parameter variant_id yang opsional
+func parseVariantIDQuery(c *gin.Context) (*uint, error) {
+	value := c.Query("variant_id")
+	if value == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return nil, errors.New("invalid variant ID")
+	}
+
+	variantID := uint(id)
+	return &variantID, nil
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 	"tokogo/requests"
 	"tokogo/responses"
@@ -104,10 +106,15 @@ func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
 		limit = 10
 	}
 
-	// Panggil service untuk get all categories
-	categoriesResponse, err := h.categoryService.GetAllCategories(page, limit)
+	// Panggil service untuk get all categories, mode cursor aktif jika query parameter cursor dikirim
+	var categoriesResponse *responses.CategoryListResponse
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		categoriesResponse, err = h.categoryService.GetAllCategoriesByCursor(cursor, limit)
+	} else {
+		categoriesResponse, err = h.categoryService.GetAllCategories(page, limit)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+		c.JSON(listErrorStatus(err), responses.ErrorResponse{
 			Error:   "get_failed",
 			Message: err.Error(),
 		})
@@ -120,6 +127,46 @@ func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
 	})
 }
 
+// GetCategoryTree handler untuk mengambil seluruh category dalam bentuk tree
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.categoryService.GetCategoryTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Error:   "get_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Category tree retrieved successfully",
+		Data:    tree,
+	})
+}
+
+// GetCategoryBySlug handler untuk mengambil category berdasarkan slug.
+// Slug lama dari category yang sudah di-rename diarahkan ke slug terbaru.
+func (h *CategoryHandler) GetCategoryBySlug(c *gin.Context) {
+	categoryResponse, redirectSlug, err := h.categoryService.GetCategoryBySlugPublic(c.Param("slug"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, responses.ErrorResponse{
+			Error:   "category_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if redirectSlug != "" {
+		c.Redirect(http.StatusMovedPermanently, "/api/v1/public/categories/"+url.PathEscape(redirectSlug))
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Category retrieved successfully",
+		Data:    categoryResponse,
+	})
+}
+
 // UpdateCategory handler untuk mengupdate category
 func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 	// Ambil ID dari URL parameter
@@ -182,9 +229,31 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
+	// Opsional: pindahkan product ke category lain sebelum category dihapus
+	var reassignTo *uint
+	if reassignStr := c.Query("reassign_to"); reassignStr != "" {
+		targetID, err := strconv.ParseUint(reassignStr, 10, 32)
+		if err != nil || targetID == 0 {
+			c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Invalid reassign_to category ID",
+			})
+			return
+		}
+		target := uint(targetID)
+		reassignTo = &target
+	}
+
 	// Panggil service untuk delete category
-	err = h.categoryService.DeleteCategory(uint(id))
+	err = h.categoryService.DeleteCategory(uint(id), reassignTo)
 	if err != nil {
+		if errors.Is(err, services.ErrCategoryInUse) {
+			c.JSON(http.StatusConflict, responses.ErrorResponse{
+				Error:   "category_in_use",
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
 			Error:   "delete_failed",
 			Message: err.Error(),
@@ -197,3 +266,32 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 		Data:    nil,
 	})
 }
+
+// RestoreCategory handler untuk mengembalikan category yang sudah dihapus
+func (h *CategoryHandler) RestoreCategory(c *gin.Context) {
+	// Ambil ID dari URL parameter
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid category ID",
+		})
+		return
+	}
+
+	// Panggil service untuk restore category
+	categoryResponse, err := h.categoryService.RestoreCategory(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "restore_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Category restored successfully",
+		Data:    categoryResponse,
+	})
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"tokogo/helpers"
 	"tokogo/requests"
 	"tokogo/responses"
 	"tokogo/services"
@@ -156,12 +157,13 @@ func (h *CheckoutHandler) ProcessCheckout(c *gin.Context) {
 
 // ConfirmPayment godoc
 // @Summary Confirm payment
-// @Description Confirm payment for a transaction
+// @Description Upload a bank transfer receipt and queue the transaction for admin verification
 // @Tags Checkout
-// @Accept json
+// @Accept multipart/form-data
 // @Produce json
 // @Param transaction_id path int true "Transaction ID"
-// @Param payment body requests.ConfirmPaymentRequest true "Payment confirmation data"
+// @Param payment_proof formData file true "Bank transfer receipt image"
+// @Param notes formData string false "Notes"
 // @Success 200 {object} responses.CheckoutResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
@@ -199,8 +201,8 @@ func (h *CheckoutHandler) ConfirmPayment(c *gin.Context) {
 
 	var req requests.ConfirmPaymentRequest
 
-	// Bind and validate request
-	if err := c.ShouldBindJSON(&req); err != nil {
+	// Bind form data
+	if err := c.ShouldBind(&req); err != nil {
 		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
 			Error:   "validation_error",
 			Message: err.Error(),
@@ -217,8 +219,27 @@ func (h *CheckoutHandler) ConfirmPayment(c *gin.Context) {
 		return
 	}
 
+	// Handle payment proof upload, stored privately and shared through signed URLs
+	file, err := c.FormFile("payment_proof")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: "payment_proof image is required",
+		})
+		return
+	}
+
+	proofPath, err := helpers.UploadFile(file, "./private/payment_proofs")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "upload_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	// Call service to confirm payment
-	response, err := h.checkoutService.ConfirmPayment(userID, uint(transactionID), req)
+	response, err := h.checkoutService.ConfirmPayment(userID, uint(transactionID), req, proofPath)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
 			Error:   "confirm_payment_failed",
@@ -228,7 +249,85 @@ func (h *CheckoutHandler) ConfirmPayment(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, responses.SuccessResponse{
-		Message: "Payment confirmed successfully",
+		Message: "Payment proof uploaded successfully, awaiting verification",
+		Data:    response,
+	})
+}
+
+// CancelTransaction godoc
+// @Summary Cancel transaction
+// @Description Cancel an unpaid transaction (pending or awaiting verification) and restore product stock. Paid transactions cannot be cancelled and must be refunded by admin
+// @Tags Checkout
+// @Accept json
+// @Produce json
+// @Param transaction_id path int true "Transaction ID"
+// @Param cancel body requests.CancelTransactionRequest true "Cancellation data"
+// @Success 200 {object} responses.CheckoutResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/checkout/transactions/{transaction_id}/cancel [post]
+func (h *CheckoutHandler) CancelTransaction(c *gin.Context) {
+	// Get user ID from JWT token
+	userIDInterface, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, responses.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID not found",
+		})
+		return
+	}
+
+	userID, ok := userIDInterface.(uint)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, responses.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	// Get transaction ID from URL parameter
+	transactionID, err := strconv.ParseUint(c.Param("transaction_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid transaction ID",
+		})
+		return
+	}
+
+	var req requests.CancelTransactionRequest
+
+	// Bind and validate request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Validate using method Validate()
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Call service to cancel transaction
+	response, err := h.checkoutService.CancelTransaction(userID, uint(transactionID), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "cancel_transaction_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Transaction cancelled successfully",
 		Data:    response,
 	})
 }
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"tokogo/helpers"
+	"tokogo/responses"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FileHandler struct {
+	storage *helpers.LocalStorage
+}
+
+// NewFileHandler membuat instance baru FileHandler untuk menyajikan signed URL dari local storage
+func NewFileHandler(storage *helpers.LocalStorage) *FileHandler {
+	return &FileHandler{
+		storage: storage,
+	}
+}
+
+// ServeSignedFile handler untuk menyajikan file private yang diakses lewat signed URL
+func (h *FileHandler) ServeSignedFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	filePath, err := h.storage.Verify(key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		c.JSON(http.StatusForbidden, responses.ErrorResponse{
+			Error:   "forbidden",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.File(filePath)
+}
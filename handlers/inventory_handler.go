@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"tokogo/requests"
+	"tokogo/responses"
+	"tokogo/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryHandler struct {
+	inventoryService *services.InventoryService
+}
+
+// NewInventoryHandler membuat instance baru InventoryHandler
+func NewInventoryHandler() *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: services.NewInventoryService(),
+	}
+}
+
+// GetStockMovements handler untuk mengambil riwayat pergerakan stok product
+func (h *InventoryHandler) GetStockMovements(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid product ID",
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	movements, err := h.inventoryService.GetStockMovements(uint(id), page, limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, responses.ErrorResponse{
+			Error:   "get_stock_movements_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Stock movements retrieved successfully",
+		Data:    movements,
+	})
+}
+
+// ReconcileStock handler untuk membandingkan stok product dengan ledger
+func (h *InventoryHandler) ReconcileStock(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid product ID",
+		})
+		return
+	}
+
+	reconciliation, err := h.inventoryService.ReconcileStock(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, responses.ErrorResponse{
+			Error:   "reconcile_stock_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Stock reconciled successfully",
+		Data:    reconciliation,
+	})
+}
+
+// AdjustStock handler untuk penyesuaian stok manual (restock, return, koreksi)
+func (h *InventoryHandler) AdjustStock(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid product ID",
+		})
+		return
+	}
+
+	var req requests.AdjustStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	reconciliation, err := h.inventoryService.AdjustProductStock(uint(id), c.GetUint("user_id"), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "adjust_stock_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Stock adjusted successfully",
+		Data:    reconciliation,
+	})
+}
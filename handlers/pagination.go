@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"tokogo/services"
+)
+
+// listErrorStatus mengembalikan 400 untuk cursor yang tidak valid dan 500 untuk error lain
+func listErrorStatus(err error) int {
+	if errors.Is(err, services.ErrInvalidCursor) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
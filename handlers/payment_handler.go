@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"tokogo/responses"
+	"tokogo/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentSignatureHeader adalah header yang berisi signature HMAC dari payment gateway
+const PaymentSignatureHeader = "X-Signature"
+
+// maxWebhookBodySize adalah ukuran maksimal body notifikasi payment gateway (64KB)
+const maxWebhookBodySize = 64 * 1024
+
+type PaymentHandler struct {
+	paymentService *services.PaymentService
+}
+
+// NewPaymentHandler membuat instance baru PaymentHandler
+func NewPaymentHandler() *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: services.NewPaymentService(),
+	}
+}
+
+// HandleWebhook godoc
+// @Summary Payment gateway webhook
+// @Description Receive signed payment status notifications from the payment gateway. Payments received for cancelled, failed or expired orders are flagged for refund
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param X-Signature header string true "HMAC-SHA256 signature of the raw body"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /api/v1/payments/webhook [post]
+func (h *PaymentHandler) HandleWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, responses.ErrorResponse{
+				Error:   "payload_too_large",
+				Message: "Request body is too large",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Failed to read request body",
+		})
+		return
+	}
+
+	err = h.paymentService.HandleNotification(payload, c.GetHeader(PaymentSignatureHeader))
+	if errors.Is(err, services.ErrLatePayment) {
+		// Notifikasi sudah dicatat, gateway tidak perlu mengirim ulang
+		c.JSON(http.StatusOK, responses.SuccessResponse{
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSignature) {
+			c.JSON(http.StatusUnauthorized, responses.ErrorResponse{
+				Error:   "invalid_signature",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "payment_notification_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Payment notification processed successfully",
+	})
+}
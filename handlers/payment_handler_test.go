@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandleWebhookRejectsOversizedBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Body yang terlalu besar ditolak sebelum mencapai service
+	router := gin.New()
+	router.POST("/api/v1/payments/webhook", (&PaymentHandler{}).HandleWebhook)
+
+	recorder := httptest.NewRecorder()
+	body := bytes.Repeat([]byte("a"), maxWebhookBodySize+1)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payments/webhook", bytes.NewReader(body))
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", recorder.Code)
+	}
+}
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"net/http"
+	"net/url"
 	"strconv"
 	"tokogo/helpers"
+	"tokogo/models"
 	"tokogo/requests"
 	"tokogo/responses"
 	"tokogo/services"
@@ -45,11 +47,11 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	}
 
 	// Handle file upload
-	var imagePath string
+	var upload *helpers.ProcessedImage
 	if file, err := c.FormFile("image"); err == nil {
-		// File was uploaded
+		// File was uploaded, resize into thumbnail/medium/large renditions
 		uploadDir := "./uploads/products"
-		uploadedPath, err := helpers.UploadFile(file, uploadDir)
+		processed, err := helpers.UploadImage(file, uploadDir, helpers.ProductImageRenditions)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, responses.ErrorResponse{
 				Error:   "upload_failed",
@@ -57,11 +59,11 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 			})
 			return
 		}
-		imagePath = uploadedPath
+		upload = processed
 	}
 
 	// Panggil service untuk create product
-	productResponse, err := h.productService.CreateProduct(req, imagePath)
+	productResponse, err := h.productService.CreateProduct(req, upload, c.GetUint("user_id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
 			Error:   "create_failed",
@@ -83,7 +85,10 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param cursor query string false "Opaque next_cursor token; send empty to start cursor pagination"
+// @Param status query string false "Publish status" Enums(draft, published, hidden)
 // @Success 200 {object} responses.ProductListResponse
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
 // @Router /admin/products [get]
@@ -91,7 +96,46 @@ func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	products, err := h.productService.GetAllProducts(page, limit)
+	status := c.Query("status")
+	switch status {
+	case "", models.ProductStatusDraft, models.ProductStatusPublished, models.ProductStatusHidden:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, must be one of draft, published, hidden"})
+		return
+	}
+
+	var products *responses.ProductListResponse
+	var err error
+	// Mode cursor aktif jika query parameter cursor dikirim, kosong untuk halaman pertama
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		products, err = h.productService.GetAllProductsByCursor(cursor, limit, status)
+	} else {
+		products, err = h.productService.GetAllProducts(page, limit, status)
+	}
+	if err != nil {
+		c.JSON(listErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+// GetLowStockProducts godoc
+// @Summary Get low stock products
+// @Description Get products whose stock is at or below their low stock threshold (Admin only)
+// @Tags Products
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} responses.ProductListResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/products/low-stock [get]
+func (h *ProductHandler) GetLowStockProducts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	products, err := h.productService.GetLowStockProducts(page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -155,7 +199,13 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := h.productService.UpdateProduct(uint(id), req)
+	// Validasi menggunakan method Validate()
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := h.productService.UpdateProduct(uint(id), req, c.GetUint("user_id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -165,8 +215,8 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 }
 
 // DeleteProduct godoc
-// @Summary Delete product
-// @Description Delete a product by ID (Admin only)
+// @Summary Archive product
+// @Description Archive (soft delete) a product by ID. Archived products are hidden from public listings and removed from carts but stay visible in past orders (Admin only)
 // @Tags Products
 // @Produce json
 // @Param id path int true "Product ID"
@@ -189,7 +239,91 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Product archived successfully"})
+}
+
+// GetArchivedProducts godoc
+// @Summary Get archived products
+// @Description Get archived products with pagination, most recently archived first (Admin only)
+// @Tags Products
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} responses.ProductListResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/products/archived [get]
+func (h *ProductHandler) GetArchivedProducts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	products, err := h.productService.GetArchivedProducts(page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, products)
+}
+
+// RestoreProduct godoc
+// @Summary Restore archived product
+// @Description Restore an archived product so it is listed and purchasable again (Admin only)
+// @Tags Products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} responses.ProductResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /admin/products/{id}/restore [post]
+func (h *ProductHandler) RestoreProduct(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	product, err := h.productService.RestoreProduct(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// GetPriceHistory godoc
+// @Summary Get product price history
+// @Description Get the append-only history of price changes for a product, newest first (Admin only)
+// @Tags Products
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} responses.PriceHistoryListResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/products/{id}/price-history [get]
+func (h *ProductHandler) GetPriceHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	histories, err := h.productService.GetPriceHistory(uint(id), page, limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, histories)
 }
 
 // GetProductsByCategory godoc
@@ -200,6 +334,7 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 // @Param category_id path int true "Category ID"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param include_descendants query bool false "Include products from all sub categories"
 // @Success 200 {object} responses.ProductListResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
@@ -215,7 +350,9 @@ func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	products, err := h.productService.GetProductsByCategory(uint(categoryID), page, limit)
+	includeDescendants, _ := strconv.ParseBool(c.DefaultQuery("include_descendants", "false"))
+
+	products, err := h.productService.GetProductsByCategory(uint(categoryID), page, limit, includeDescendants)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -231,15 +368,36 @@ func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param q query string false "Search keyword (name and description)"
+// @Param min_price query number false "Minimum selling price"
+// @Param max_price query number false "Maximum selling price"
+// @Param in_stock query bool false "Only products that are in stock"
+// @Param sort query string false "Sort order" Enums(newest, price_asc, price_desc, best_selling, name_asc, name_desc)
+// @Param include_descendants query bool false "Include products from all sub categories"
+// @Param category_ids query string false "Comma separated category IDs"
+// @Param cursor query string false "Opaque next_cursor token; send empty to start cursor pagination (ignored when q is set)"
 // @Success 200 {object} responses.PublicProductListResponse
+// @Failure 400 {object} map[string]string
 // @Router /api/v1/public/products [get]
 func (h *ProductHandler) GetAllProductsPublic(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	products, err := h.productService.GetAllProductsPublic(page, limit)
+	listQuery, ok := bindProductListQuery(c)
+	if !ok {
+		return
+	}
+
+	var products *responses.PublicProductListResponse
+	var err error
+	// Mode cursor hanya untuk listing, pencarian q tetap memakai page/limit
+	if cursor, ok := c.GetQuery("cursor"); ok && c.Query("q") == "" {
+		products, err = h.productService.GetAllProductsPublicByCursor(cursor, limit, listQuery)
+	} else {
+		products, err = h.productService.GetAllProductsPublic(page, limit, c.Query("q"), listQuery)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(listErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -272,6 +430,31 @@ func (h *ProductHandler) GetProductByIDPublic(c *gin.Context) {
 	c.JSON(http.StatusOK, product)
 }
 
+// GetProductBySlugPublic godoc
+// @Summary Get product by slug (Public)
+// @Description Get a specific product by slug. Old slugs of renamed products redirect to the current slug (Public access - no authentication required)
+// @Tags Public Products
+// @Produce json
+// @Param slug path string true "Product slug"
+// @Success 200 {object} responses.PublicProductResponse
+// @Success 301 {string} string "Redirect to the current slug"
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/public/products/slug/{slug} [get]
+func (h *ProductHandler) GetProductBySlugPublic(c *gin.Context) {
+	product, redirectSlug, err := h.productService.GetProductBySlugPublic(c.Param("slug"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	if redirectSlug != "" {
+		c.Redirect(http.StatusMovedPermanently, "/api/v1/public/products/slug/"+url.PathEscape(redirectSlug))
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
 // GetProductsByCategoryPublic godoc
 // @Summary Get products by category (Public)
 // @Description Get products by category ID with pagination (Public access - no authentication required)
@@ -280,6 +463,11 @@ func (h *ProductHandler) GetProductByIDPublic(c *gin.Context) {
 // @Param category_id path int true "Category ID"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param min_price query number false "Minimum selling price"
+// @Param max_price query number false "Maximum selling price"
+// @Param in_stock query bool false "Only products that are in stock"
+// @Param sort query string false "Sort order" Enums(newest, price_asc, price_desc, best_selling, name_asc, name_desc)
+// @Param include_descendants query bool false "Include products from all sub categories"
 // @Success 200 {object} responses.PublicProductListResponse
 // @Failure 400 {object} map[string]string
 // @Router /api/v1/public/products/categories/{category_id} [get]
@@ -293,7 +481,12 @@ func (h *ProductHandler) GetProductsByCategoryPublic(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	products, err := h.productService.GetProductsByCategoryPublic(uint(categoryID), page, limit)
+	listQuery, ok := bindProductListQuery(c)
+	if !ok {
+		return
+	}
+
+	products, err := h.productService.GetProductsByCategoryPublic(uint(categoryID), page, limit, listQuery)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -301,3 +494,17 @@ func (h *ProductHandler) GetProductsByCategoryPublic(c *gin.Context) {
 
 	c.JSON(http.StatusOK, products)
 }
+
+// bindProductListQuery membaca dan memvalidasi query parameter filter listing product
+func bindProductListQuery(c *gin.Context) (requests.ProductListQuery, bool) {
+	var listQuery requests.ProductListQuery
+	if err := c.ShouldBindQuery(&listQuery); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return listQuery, false
+	}
+	if err := listQuery.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return listQuery, false
+	}
+	return listQuery, true
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// updateProduct memanggil UpdateProduct tanpa service sehingga hanya request yang gagal validasi
+// yang boleh dipakai: request yang lolos validasi akan mencapai service dan panic
+func updateProduct(t *testing.T, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.PUT("/admin/products/:id", (&ProductHandler{}).UpdateProduct)
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/admin/products/1", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestUpdateProductValidatesPublishStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{
+			name:    "unknown status",
+			body:    `{"name":"Kemeja","purchase_price":50000,"selling_price":80000,"category_id":1,"status":"archived"}`,
+			message: "oneof",
+		},
+		{
+			name:    "unpublish before publish",
+			body:    `{"name":"Kemeja","purchase_price":50000,"selling_price":80000,"category_id":1,"publish_at":"2026-01-02T00:00:00Z","unpublish_at":"2026-01-01T00:00:00Z"}`,
+			message: "unpublish_at must be after publish_at",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := updateProduct(t, tt.body)
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", recorder.Code)
+			}
+			if !strings.Contains(recorder.Body.String(), tt.message) {
+				t.Errorf("body = %s, want message containing %q", recorder.Body.String(), tt.message)
+			}
+		})
+	}
+}
+
+func TestUpdateProductValidatesSalePrice(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{
+			name:    "sale price above selling price",
+			body:    `{"name":"Kemeja","purchase_price":50000,"selling_price":80000,"category_id":1,"sale_price":90000}`,
+			message: "sale_price must be less than selling price",
+		},
+		{
+			name:    "compare at price below selling price",
+			body:    `{"name":"Kemeja","purchase_price":50000,"selling_price":80000,"category_id":1,"compare_at_price":70000}`,
+			message: "compare_at_price must be greater than selling price",
+		},
+		{
+			name:    "sale ends before it starts",
+			body:    `{"name":"Kemeja","purchase_price":50000,"selling_price":80000,"category_id":1,"sale_price":70000,"sale_starts_at":"2026-01-02T00:00:00Z","sale_ends_at":"2026-01-01T00:00:00Z"}`,
+			message: "sale_ends_at must be after sale_starts_at",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := updateProduct(t, tt.body)
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", recorder.Code)
+			}
+			if !strings.Contains(recorder.Body.String(), tt.message) {
+				t.Errorf("body = %s, want message containing %q", recorder.Body.String(), tt.message)
+			}
+		})
+	}
+}
+
+func TestUpdateProductRejectsNegativeLowStockThreshold(t *testing.T) {
+	recorder := updateProduct(t, `{"name":"Kemeja","purchase_price":50000,"selling_price":80000,"category_id":1,"low_stock_threshold":-1}`)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", recorder.Code)
+	}
+	if !strings.Contains(recorder.Body.String(), "LowStockThreshold") {
+		t.Errorf("body = %s, want low stock threshold error", recorder.Body.String())
+	}
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"tokogo/helpers"
+	"tokogo/requests"
+	"tokogo/responses"
+	"tokogo/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImagesPerUpload adalah jumlah maksimal gambar dalam satu request upload galeri
+const maxImagesPerUpload = 10
+
+type ProductImageHandler struct {
+	imageService *services.ProductImageService
+}
+
+// NewProductImageHandler membuat instance baru ProductImageHandler
+func NewProductImageHandler() *ProductImageHandler {
+	return &ProductImageHandler{
+		imageService: services.NewProductImageService(),
+	}
+}
+
+// GetImages handler untuk mengambil galeri product
+func (h *ProductImageHandler) GetImages(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid product ID",
+		})
+		return
+	}
+
+	images, err := h.imageService.GetImages(uint(productID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, responses.ErrorResponse{
+			Error:   "get_images_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Product images retrieved successfully",
+		Data:    images,
+	})
+}
+
+// UploadImages handler untuk menambahkan satu atau beberapa gambar (field "images") ke galeri product
+func (h *ProductImageHandler) UploadImages(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid product ID",
+		})
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["images"]) == 0 {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: "At least one image is required",
+		})
+		return
+	}
+
+	files := form.File["images"]
+	if len(files) > maxImagesPerUpload {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Too many images. Maximum " + strconv.Itoa(maxImagesPerUpload) + " per upload",
+		})
+		return
+	}
+
+	// Upload dan resize semua file dulu, hapus lagi bila ada yang gagal
+	var uploads []helpers.ProcessedImage
+	for _, file := range files {
+		processed, err := helpers.UploadImage(file, "./uploads/products", helpers.ProductImageRenditions)
+		if err != nil {
+			for _, upload := range uploads {
+				helpers.DeleteFiles(upload.Paths()...)
+			}
+			c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+				Error:   "upload_failed",
+				Message: err.Error(),
+			})
+			return
+		}
+		uploads = append(uploads, *processed)
+	}
+
+	images, err := h.imageService.AddImages(uint(productID), uploads)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "upload_images_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.SuccessResponse{
+		Message: "Product images uploaded successfully",
+		Data:    images,
+	})
+}
+
+// ReorderImages handler untuk mengatur ulang urutan galeri product
+func (h *ProductImageHandler) ReorderImages(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid product ID",
+		})
+		return
+	}
+
+	var req requests.ReorderProductImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	images, err := h.imageService.ReorderImages(uint(productID), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "reorder_images_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Product images reordered successfully",
+		Data:    images,
+	})
+}
+
+// SetPrimaryImage handler untuk memilih gambar utama product
+func (h *ProductImageHandler) SetPrimaryImage(c *gin.Context) {
+	productID, imageID, ok := parseImageParams(c)
+	if !ok {
+		return
+	}
+
+	images, err := h.imageService.SetPrimaryImage(productID, imageID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "set_primary_image_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Primary image updated successfully",
+		Data:    images,
+	})
+}
+
+// DeleteImage handler untuk menghapus gambar dari galeri product
+func (h *ProductImageHandler) DeleteImage(c *gin.Context) {
+	productID, imageID, ok := parseImageParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.imageService.DeleteImage(productID, imageID); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "delete_image_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Product image deleted successfully",
+	})
+}
+
+// parseImageParams membaca path parameter id dan image_id, menulis response error bila tidak valid
+func parseImageParams(c *gin.Context) (uint, uint, bool) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid product ID",
+		})
+		return 0, 0, false
+	}
+
+	imageID, err := strconv.ParseUint(c.Param("image_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid image ID",
+		})
+		return 0, 0, false
+	}
+
+	return uint(productID), uint(imageID), true
+}
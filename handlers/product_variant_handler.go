@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"tokogo/requests"
+	"tokogo/responses"
+	"tokogo/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProductVariantHandler struct {
+	variantService *services.ProductVariantService
+}
+
+// NewProductVariantHandler membuat instance baru ProductVariantHandler
+func NewProductVariantHandler() *ProductVariantHandler {
+	return &ProductVariantHandler{
+		variantService: services.NewProductVariantService(),
+	}
+}
+
+// GetVariants handler untuk mengambil semua variant product
+func (h *ProductVariantHandler) GetVariants(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid product ID",
+		})
+		return
+	}
+
+	variants, err := h.variantService.GetVariants(uint(productID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, responses.ErrorResponse{
+			Error:   "get_variants_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Product variants retrieved successfully",
+		Data:    variants,
+	})
+}
+
+// CreateVariant handler untuk membuat variant product
+func (h *ProductVariantHandler) CreateVariant(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid product ID",
+		})
+		return
+	}
+
+	var req requests.CreateProductVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	variant, err := h.variantService.CreateVariant(uint(productID), req, c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "create_variant_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.SuccessResponse{
+		Message: "Product variant created successfully",
+		Data:    variant,
+	})
+}
+
+// UpdateVariant handler untuk mengupdate variant product
+func (h *ProductVariantHandler) UpdateVariant(c *gin.Context) {
+	productID, variantID, ok := parseVariantParams(c)
+	if !ok {
+		return
+	}
+
+	var req requests.UpdateProductVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	variant, err := h.variantService.UpdateVariant(productID, variantID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "update_variant_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Product variant updated successfully",
+		Data:    variant,
+	})
+}
+
+// DeleteVariant handler untuk menghapus variant product
+func (h *ProductVariantHandler) DeleteVariant(c *gin.Context) {
+	productID, variantID, ok := parseVariantParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.variantService.DeleteVariant(productID, variantID); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "delete_variant_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Product variant deleted successfully",
+	})
+}
+
+// parseVariantParams membaca path parameter id dan variant_id, menulis response error bila tidak valid
+func parseVariantParams(c *gin.Context) (uint, uint, bool) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid product ID",
+		})
+		return 0, 0, false
+	}
+
+	variantID, err := strconv.ParseUint(c.Param("variant_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid variant ID",
+		})
+		return 0, 0, false
+	}
+
+	return uint(productID), uint(variantID), true
+}
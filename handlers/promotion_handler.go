@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"tokogo/requests"
+	"tokogo/responses"
+	"tokogo/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromotionHandler struct {
+	promotionService *services.PromotionService
+}
+
+// NewPromotionHandler membuat instance baru PromotionHandler
+func NewPromotionHandler() *PromotionHandler {
+	return &PromotionHandler{
+		promotionService: services.NewPromotionService(),
+	}
+}
+
+// CreatePromotion handler untuk membuat promosi baru
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	var req requests.CreatePromotionRequest
+
+	// Bind dan validasi request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Validasi menggunakan method Validate()
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Panggil service untuk create promosi
+	promotionResponse, err := h.promotionService.CreatePromotion(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "create_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.SuccessResponse{
+		Message: "Promotion created successfully",
+		Data:    promotionResponse,
+	})
+}
+
+// GetAllPromotions handler untuk mengambil semua promosi
+func (h *PromotionHandler) GetAllPromotions(c *gin.Context) {
+	// Ambil query parameters untuk pagination
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	promotionsResponse, err := h.promotionService.GetAllPromotions(page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Error:   "get_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Promotions retrieved successfully",
+		Data:    promotionsResponse,
+	})
+}
+
+// GetPromotionByID handler untuk mengambil promosi berdasarkan ID
+func (h *PromotionHandler) GetPromotionByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid promotion ID",
+		})
+		return
+	}
+
+	promotionResponse, err := h.promotionService.GetPromotionByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, responses.ErrorResponse{
+			Error:   "promotion_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Promotion retrieved successfully",
+		Data:    promotionResponse,
+	})
+}
+
+// UpdatePromotion handler untuk mengupdate promosi
+func (h *PromotionHandler) UpdatePromotion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid promotion ID",
+		})
+		return
+	}
+
+	var req requests.UpdatePromotionRequest
+
+	// Bind dan validasi request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Validasi menggunakan method Validate()
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Panggil service untuk update promosi
+	promotionResponse, err := h.promotionService.UpdatePromotion(uint(id), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "update_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Promotion updated successfully",
+		Data:    promotionResponse,
+	})
+}
+
+// DeletePromotion handler untuk menghapus promosi
+func (h *PromotionHandler) DeletePromotion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid promotion ID",
+		})
+		return
+	}
+
+	if err := h.promotionService.DeletePromotion(uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "delete_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Promotion deleted successfully",
+		Data:    nil,
+	})
+}
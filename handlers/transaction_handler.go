@@ -46,10 +46,15 @@ func (h *TransactionHandler) GetAllTransactions(c *gin.Context) {
 		return
 	}
 
-	// Get transactions
-	result, err := h.transactionService.GetAllTransactions(page, limit, status)
+	// Get transactions, mode cursor aktif jika query parameter cursor dikirim
+	var result *responses.TransactionListResponse
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		result, err = h.transactionService.GetAllTransactionsByCursor(cursor, limit, status)
+	} else {
+		result, err = h.transactionService.GetAllTransactions(page, limit, status)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+		c.JSON(listErrorStatus(err), responses.ErrorResponse{
 			Error:   "get_transactions_failed",
 			Message: err.Error(),
 		})
@@ -115,7 +120,7 @@ func (h *TransactionHandler) UpdateTransactionStatus(c *gin.Context) {
 	}
 
 	// Update transaction status
-	transaction, err := h.transactionService.UpdateTransactionStatus(uint(id), req)
+	transaction, err := h.transactionService.UpdateTransactionStatus(uint(id), c.GetUint("user_id"), req)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
 			Error:   "update_transaction_failed",
@@ -129,3 +134,158 @@ func (h *TransactionHandler) UpdateTransactionStatus(c *gin.Context) {
 		Data:    transaction,
 	})
 }
+
+// CancelTransaction handler untuk membatalkan transaksi atas nama customer
+func (h *TransactionHandler) CancelTransaction(c *gin.Context) {
+	// Parse transaction ID
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid transaction ID",
+		})
+		return
+	}
+
+	// Parse request body
+	var req requests.CancelTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Cancel transaction
+	transaction, err := h.transactionService.CancelTransaction(uint(id), c.GetUint("user_id"), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "cancel_transaction_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Transaction cancelled successfully",
+		Data:    transaction,
+	})
+}
+
+// GetPaymentVerificationQueue handler untuk mengambil antrian verifikasi bukti pembayaran
+func (h *TransactionHandler) GetPaymentVerificationQueue(c *gin.Context) {
+	// Parse query parameters
+	pageStr := c.DefaultQuery("page", "1")
+	limitStr := c.DefaultQuery("limit", "10")
+
+	page, err := strconv.Atoi(pageStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid page parameter",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid limit parameter",
+		})
+		return
+	}
+
+	// Get queue
+	result, err := h.transactionService.GetPaymentVerificationQueue(page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Error:   "get_transactions_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Payment verification queue retrieved successfully",
+		Data:    result,
+	})
+}
+
+// ApprovePaymentProof handler untuk menyetujui bukti pembayaran
+func (h *TransactionHandler) ApprovePaymentProof(c *gin.Context) {
+	// Parse transaction ID
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid transaction ID",
+		})
+		return
+	}
+
+	// Approve payment proof
+	transaction, err := h.transactionService.ApprovePaymentProof(uint(id), c.GetUint("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "approve_payment_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Payment proof approved successfully",
+		Data:    transaction,
+	})
+}
+
+// RejectPaymentProof handler untuk menolak bukti pembayaran
+func (h *TransactionHandler) RejectPaymentProof(c *gin.Context) {
+	// Parse transaction ID
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid transaction ID",
+		})
+		return
+	}
+
+	// Parse request body
+	var req requests.RejectPaymentProofRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Reject payment proof
+	transaction, err := h.transactionService.RejectPaymentProof(uint(id), c.GetUint("user_id"), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "reject_payment_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Payment proof rejected successfully",
+		Data:    transaction,
+	})
+}
@@ -104,10 +104,15 @@ func (h *UserManagementHandler) GetAllUsers(c *gin.Context) {
 		limit = 10
 	}
 
-	// Panggil service untuk get all users
-	usersResponse, err := h.userService.GetAllUsers(page, limit)
+	// Panggil service untuk get all users, mode cursor aktif jika query parameter cursor dikirim
+	var usersResponse *responses.UserListResponse
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		usersResponse, err = h.userService.GetAllUsersByCursor(cursor, limit)
+	} else {
+		usersResponse, err = h.userService.GetAllUsers(page, limit)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+		c.JSON(listErrorStatus(err), responses.ErrorResponse{
 			Error:   "get_users_failed",
 			Message: err.Error(),
 		})
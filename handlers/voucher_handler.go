@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"tokogo/requests"
+	"tokogo/responses"
+	"tokogo/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VoucherHandler struct {
+	voucherService *services.VoucherService
+}
+
+// NewVoucherHandler membuat instance baru VoucherHandler
+func NewVoucherHandler() *VoucherHandler {
+	return &VoucherHandler{
+		voucherService: services.NewVoucherService(),
+	}
+}
+
+// CreateVoucher handler untuk membuat voucher baru
+func (h *VoucherHandler) CreateVoucher(c *gin.Context) {
+	var req requests.CreateVoucherRequest
+
+	// Bind dan validasi request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Validasi menggunakan method Validate()
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Panggil service untuk create voucher
+	voucherResponse, err := h.voucherService.CreateVoucher(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "create_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, responses.SuccessResponse{
+		Message: "Voucher created successfully",
+		Data:    voucherResponse,
+	})
+}
+
+// GetAllVouchers handler untuk mengambil semua voucher
+func (h *VoucherHandler) GetAllVouchers(c *gin.Context) {
+	// Ambil query parameters untuk pagination
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	vouchersResponse, err := h.voucherService.GetAllVouchers(page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+			Error:   "get_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Vouchers retrieved successfully",
+		Data:    vouchersResponse,
+	})
+}
+
+// GetVoucherByID handler untuk mengambil voucher berdasarkan ID
+func (h *VoucherHandler) GetVoucherByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid voucher ID",
+		})
+		return
+	}
+
+	voucherResponse, err := h.voucherService.GetVoucherByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, responses.ErrorResponse{
+			Error:   "voucher_not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Voucher retrieved successfully",
+		Data:    voucherResponse,
+	})
+}
+
+// UpdateVoucher handler untuk mengupdate voucher
+func (h *VoucherHandler) UpdateVoucher(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid voucher ID",
+		})
+		return
+	}
+
+	var req requests.UpdateVoucherRequest
+
+	// Bind dan validasi request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Validasi menggunakan method Validate()
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Panggil service untuk update voucher
+	voucherResponse, err := h.voucherService.UpdateVoucher(uint(id), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "update_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Voucher updated successfully",
+		Data:    voucherResponse,
+	})
+}
+
+// DeleteVoucher handler untuk menghapus voucher
+func (h *VoucherHandler) DeleteVoucher(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid voucher ID",
+		})
+		return
+	}
+
+	if err := h.voucherService.DeleteVoucher(uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+			Error:   "delete_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, responses.SuccessResponse{
+		Message: "Voucher deleted successfully",
+		Data:    nil,
+	})
+}
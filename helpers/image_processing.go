@@ -0,0 +1,199 @@
+package helpers
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// MaxUploadSize adalah ukuran maksimal file gambar yang diupload (5MB)
+	MaxUploadSize = 5 * 1024 * 1024
+	// maxImagePixels membatasi resolusi gambar agar decoding tidak menghabiskan memory
+	maxImagePixels = 40 * 1000 * 1000
+	// jpegQuality adalah kualitas encoding ulang untuk gambar JPEG
+	jpegQuality = 85
+)
+
+// ImageRendition menjelaskan satu ukuran turunan gambar, lebar dan tinggi tidak melebihi MaxSize
+type ImageRendition struct {
+	Name    string
+	MaxSize int
+}
+
+// ProductImageRenditions adalah ukuran turunan yang dibuat untuk setiap gambar product
+var ProductImageRenditions = []ImageRendition{
+	{Name: "thumbnail", MaxSize: 200},
+	{Name: "medium", MaxSize: 600},
+	{Name: "large", MaxSize: 1200},
+}
+
+// ProcessedImage berisi storage key gambar asli (sudah di-encode ulang) dan setiap rendition-nya
+type ProcessedImage struct {
+	Original   string
+	Renditions map[string]string
+}
+
+// Paths mengembalikan semua key file milik gambar, berguna untuk menghapusnya sekaligus
+func (p ProcessedImage) Paths() []string {
+	paths := []string{p.Original}
+	for _, path := range p.Renditions {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// allowedImageFormats memetakan content type hasil sniffing ke format image yang didukung
+var allowedImageFormats = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// UploadImage memvalidasi file berdasarkan isinya, meng-encode ulang gambar asli
+// (menghapus metadata seperti EXIF) dan membuat rendition sesuai daftar renditions.
+// Rendition tidak pernah lebih besar dari gambar asli.
+func UploadImage(file *multipart.FileHeader, uploadDir string, renditions []ImageRendition) (*ProcessedImage, error) {
+	img, format, err := decodeUploadedImage(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// GIF disimpan sebagai PNG karena hanya frame pertama yang diproses
+	ext := ".jpg"
+	if format != "jpeg" {
+		ext = ".png"
+	}
+	base := fmt.Sprintf("%d_%s", time.Now().Unix(), generateRandomString(10))
+
+	processed := &ProcessedImage{
+		Original:   storageKey(uploadDir, base+ext),
+		Renditions: make(map[string]string, len(renditions)),
+	}
+
+	if err := writeImage(processed.Original, img, ext); err != nil {
+		return nil, err
+	}
+
+	for _, rendition := range renditions {
+		path := storageKey(uploadDir, base+"_"+rendition.Name+ext)
+		if err := writeImage(path, resizeToFit(img, rendition.MaxSize), ext); err != nil {
+			DeleteFiles(processed.Paths()...)
+			return nil, err
+		}
+		processed.Renditions[rendition.Name] = path
+	}
+
+	return processed, nil
+}
+
+// DeleteFiles menghapus beberapa file sekaligus dan mengembalikan error pertama yang terjadi
+func DeleteFiles(filePaths ...string) error {
+	var firstErr error
+	for _, filePath := range filePaths {
+		if err := DeleteFile(filePath); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// decodeUploadedImage membaca file upload, menentukan tipenya dari isi file (bukan
+// header Content-Type dari client) dan men-decode gambarnya
+func decodeUploadedImage(file *multipart.FileHeader) (image.Image, string, error) {
+	if file.Size > MaxUploadSize {
+		return nil, "", fmt.Errorf("file size too large. Maximum size: 5MB")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open uploaded file: %v", err)
+	}
+	defer src.Close()
+
+	// Baca satu byte lebih dari batas untuk mendeteksi file yang ukurannya dipalsukan
+	data, err := io.ReadAll(io.LimitReader(src, MaxUploadSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read uploaded file: %v", err)
+	}
+	if len(data) > MaxUploadSize {
+		return nil, "", fmt.Errorf("file size too large. Maximum size: 5MB")
+	}
+
+	contentType := http.DetectContentType(data)
+	format, ok := allowedImageFormats[contentType]
+	if !ok {
+		return nil, "", fmt.Errorf("file type not allowed. Allowed types: [image/jpeg image/png image/gif]")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid image file: %v", err)
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, "", fmt.Errorf("image resolution too large")
+	}
+
+	var img image.Image
+	switch format {
+	case "jpeg":
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	case "png":
+		img, err = png.Decode(bytes.NewReader(data))
+	case "gif":
+		img, err = gif.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid image file: %v", err)
+	}
+
+	return img, format, nil
+}
+
+// resizeToFit memperkecil gambar secara proporsional agar sisi terpanjangnya tidak melebihi maxSize
+func resizeToFit(img image.Image, maxSize int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSize && height <= maxSize {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*maxSize/width)
+		width = maxSize
+	} else {
+		width = max(1, width*maxSize/height)
+		height = maxSize
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+// writeImage meng-encode gambar sesuai ekstensi (.jpg atau .png) dan menyimpannya ke storage
+func writeImage(key string, img image.Image, ext string) error {
+	var buf bytes.Buffer
+	var err error
+	contentType := "image/png"
+	if ext == ".jpg" {
+		contentType = "image/jpeg"
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode image: %v", err)
+	}
+
+	return DefaultStorage().Put(key, buf.Bytes(), contentType)
+}
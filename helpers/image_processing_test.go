@@ -0,0 +1,253 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"os"
+	"strings"
+	"testing"
+)
+
+// useTestStorage memasang LocalStorage di direktori sementara sebagai default storage selama test
+func useTestStorage(t *testing.T) *LocalStorage {
+	t.Helper()
+
+	defaultStorageOnce.Do(func() {})
+	previous, previousErr := defaultStorage, defaultStorageErr
+	storage := NewLocalStorage(t.TempDir(), "", "test-secret")
+	defaultStorage, defaultStorageErr = storage, nil
+	t.Cleanup(func() {
+		defaultStorage, defaultStorageErr = previous, previousErr
+	})
+	return storage
+}
+
+// uploadedFile membentuk FileHeader seperti hasil parsing form multipart dari client
+func uploadedFile(t *testing.T, filename string, data []byte) *multipart.FileHeader {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("image", filename)
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	part.Write(data)
+	writer.Close()
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(int64(body.Len()))
+	if err != nil {
+		t.Fatalf("read form: %v", err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["image"][0]
+}
+
+// testImage membuat gambar berwarna dengan ukuran tertentu
+func testImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func encodeTestImage(t *testing.T, format string, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "png":
+		err = png.Encode(&buf, img)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatalf("encode %s: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+// withExif menyisipkan segmen APP1 EXIF tepat setelah marker SOI sebuah JPEG
+func withExif(jpegData []byte) []byte {
+	payload := append([]byte("Exif\x00\x00"), []byte("GPS -6.2088,106.8456")...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+
+	data := append([]byte{}, jpegData[:2]...)
+	data = append(data, segment...)
+	data = append(data, payload...)
+	return append(data, jpegData[2:]...)
+}
+
+// pngHeader membuat awal file PNG yang hanya berisi chunk IHDR dengan dimensi tertentu
+func pngHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], width)
+	binary.BigEndian.PutUint32(ihdr[4:], height)
+	ihdr[8] = 8 // bit depth
+	ihdr[9] = 2 // truecolor
+
+	chunk := append([]byte("IHDR"), ihdr...)
+	var data bytes.Buffer
+	data.WriteString("\x89PNG\r\n\x1a\n")
+	binary.Write(&data, binary.BigEndian, uint32(len(ihdr)))
+	data.Write(chunk)
+	binary.Write(&data, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+	return data.Bytes()
+}
+
+func TestDecodeUploadedImageValidatesContent(t *testing.T) {
+	validPNG := encodeTestImage(t, "png", testImage(40, 20))
+
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+		wantErr  string
+		format   string
+	}{
+		{name: "script disguised as jpeg", filename: "photo.jpg", data: []byte("<?php system($_GET['cmd']); ?>"), wantErr: "file type not allowed"},
+		{name: "html disguised as png", filename: "photo.png", data: []byte("<html><script>alert(1)</script></html>"), wantErr: "file type not allowed"},
+		{name: "truncated png", filename: "photo.png", data: validPNG[:len(validPNG)/2], wantErr: "invalid image file"},
+		{name: "oversized dimensions", filename: "huge.png", data: pngHeader(20000, 20000), wantErr: "image resolution too large"},
+		{name: "png with wrong extension", filename: "photo.jpg", data: validPNG, format: "png"},
+		{name: "jpeg", filename: "photo.jpg", data: encodeTestImage(t, "jpeg", testImage(40, 20)), format: "jpeg"},
+		{name: "gif", filename: "photo.gif", data: encodeTestImage(t, "gif", testImage(40, 20)), format: "gif"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, format, err := decodeUploadedImage(uploadedFile(t, tt.filename, tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if format != tt.format {
+				t.Errorf("format = %s, want %s", format, tt.format)
+			}
+			if img.Bounds().Dx() != 40 || img.Bounds().Dy() != 20 {
+				t.Errorf("size = %v, want 40x20", img.Bounds().Size())
+			}
+		})
+	}
+}
+
+func TestResizeToFit(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		maxSize       int
+		wantW, wantH  int
+	}{
+		{name: "landscape", width: 800, height: 400, maxSize: 200, wantW: 200, wantH: 100},
+		{name: "portrait", width: 300, height: 900, maxSize: 600, wantW: 200, wantH: 600},
+		{name: "square", width: 500, height: 500, maxSize: 200, wantW: 200, wantH: 200},
+		// Gambar kecil tidak pernah diperbesar
+		{name: "smaller than max", width: 120, height: 80, maxSize: 200, wantW: 120, wantH: 80},
+		{name: "very thin", width: 3000, height: 2, maxSize: 200, wantW: 200, wantH: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resized := resizeToFit(testImage(tt.width, tt.height), tt.maxSize)
+			if got := resized.Bounds().Size(); got.X != tt.wantW || got.Y != tt.wantH {
+				t.Errorf("size = %dx%d, want %dx%d", got.X, got.Y, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestUploadImageReencodesAndResizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		data    func(t *testing.T) []byte
+		wantExt string
+	}{
+		{name: "jpeg with exif", format: "jpeg", wantExt: ".jpg", data: func(t *testing.T) []byte {
+			return withExif(encodeTestImage(t, "jpeg", testImage(900, 450)))
+		}},
+		{name: "png", format: "png", wantExt: ".png", data: func(t *testing.T) []byte {
+			return encodeTestImage(t, "png", testImage(900, 450))
+		}},
+		// GIF disimpan sebagai PNG
+		{name: "gif", format: "gif", wantExt: ".png", data: func(t *testing.T) []byte {
+			return encodeTestImage(t, "gif", testImage(900, 450))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := useTestStorage(t)
+			data := tt.data(t)
+
+			processed, err := UploadImage(uploadedFile(t, "upload"+tt.wantExt, data), "./uploads/products", ProductImageRenditions)
+			if err != nil {
+				t.Fatalf("upload: %v", err)
+			}
+			if !strings.HasPrefix(processed.Original, "uploads/products/") || !strings.HasSuffix(processed.Original, tt.wantExt) {
+				t.Errorf("original key = %s, want uploads/products/*%s", processed.Original, tt.wantExt)
+			}
+
+			original := readStoredImage(t, storage, processed.Original)
+			if bytes.Contains(original, []byte("Exif")) {
+				t.Error("stored original still contains EXIF metadata")
+			}
+			img, _, err := image.Decode(bytes.NewReader(original))
+			if err != nil {
+				t.Fatalf("decode stored original: %v", err)
+			}
+			if img.Bounds().Dx() != 900 || img.Bounds().Dy() != 450 {
+				t.Errorf("original size = %v, want 900x450", img.Bounds().Size())
+			}
+
+			wantSizes := map[string]image.Point{"thumbnail": {200, 100}, "medium": {600, 300}, "large": {900, 450}}
+			for name, want := range wantSizes {
+				key, ok := processed.Renditions[name]
+				if !ok {
+					t.Errorf("missing %s rendition", name)
+					continue
+				}
+				config, _, err := image.DecodeConfig(bytes.NewReader(readStoredImage(t, storage, key)))
+				if err != nil {
+					t.Fatalf("decode %s rendition: %v", name, err)
+				}
+				if config.Width != want.X || config.Height != want.Y {
+					t.Errorf("%s size = %dx%d, want %dx%d", name, config.Width, config.Height, want.X, want.Y)
+				}
+			}
+		})
+	}
+}
+
+func readStoredImage(t *testing.T, storage *LocalStorage, key string) []byte {
+	t.Helper()
+
+	path, err := storage.Path(key)
+	if err != nil {
+		t.Fatalf("path for %s: %v", key, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", key, err)
+	}
+	return data
+}
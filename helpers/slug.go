@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSlugLength menyisakan ruang untuk suffix -N di kolom VARCHAR(255)
+const maxSlugLength = 200
+
+// slugTransliterations berisi huruf yang tidak terurai menjadi huruf ASCII lewat normalisasi NFKD
+var slugTransliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe",
+	'ø': "o", 'Ø': "o", 'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d",
+	'ł': "l", 'Ł': "l", 'þ': "th", 'Þ': "th", 'ı': "i",
+	'&': " and ", '@': " at ",
+}
+
+// Slugify mengubah teks menjadi slug ASCII huruf kecil yang dipisah tanda hubung.
+// Huruf beraksen ditransliterasi (é menjadi e), karakter lain diperlakukan sebagai pemisah.
+func Slugify(text string) string {
+	var builder strings.Builder
+	pendingDash := false
+
+	for _, r := range norm.NFKD.String(text) {
+		if replacement, ok := slugTransliterations[r]; ok {
+			for _, rr := range replacement {
+				pendingDash = writeSlugRune(&builder, rr, pendingDash)
+			}
+			continue
+		}
+		// Tanda diakritik hasil NFKD dibuang
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		pendingDash = writeSlugRune(&builder, r, pendingDash)
+	}
+
+	slug := builder.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
+
+// writeSlugRune menulis huruf/angka ASCII dan menggabungkan karakter lain menjadi satu tanda hubung
+func writeSlugRune(builder *strings.Builder, r rune, pendingDash bool) bool {
+	r = unicode.ToLower(r)
+	if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+		if pendingDash && builder.Len() > 0 {
+			builder.WriteByte('-')
+		}
+		builder.WriteRune(r)
+		return false
+	}
+	return true
+}
+
+// IsSlugVariant mengecek apakah slug sama dengan base atau base dengan suffix angka (base-2),
+// sehingga slug tidak perlu diganti jika nama berubah tanpa mengubah hasil slugify-nya
+func IsSlugVariant(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(slug, base+"-")
+	if !ok {
+		return false
+	}
+	n, err := strconv.Atoi(suffix)
+	return err == nil && n >= 2
+}
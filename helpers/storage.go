@@ -0,0 +1,133 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+	"tokogo/config"
+)
+
+// Storage adalah kontrak penyimpanan file upload (local disk atau S3-compatible).
+// Key adalah path relatif dengan pemisah "/", misalnya "uploads/products/abc.jpg".
+type Storage interface {
+	// Put menyimpan data dengan key tertentu, menimpa object yang sudah ada
+	Put(key string, data []byte, contentType string) error
+	// Delete menghapus object, key yang tidak ada tidak dianggap error
+	Delete(key string) error
+	// PublicURL mengembalikan URL publik object
+	PublicURL(key string) string
+	// SignedURL mengembalikan URL sementara untuk object private yang berlaku selama expiry
+	SignedURL(key string, expiry time.Duration) (string, error)
+}
+
+// signedURLExpiry adalah masa berlaku default URL sementara untuk file private
+const signedURLExpiry = 15 * time.Minute
+
+// PrivateKeyPrefix adalah awalan key untuk file private yang hanya bisa diakses lewat signed URL
+const PrivateKeyPrefix = "private/"
+
+var (
+	defaultStorage     Storage
+	defaultStorageErr  error
+	defaultStorageOnce sync.Once
+)
+
+// InitStorage memilih storage melalui STORAGE_DRIVER (local atau s3) dan memastikan konfigurasinya
+// lengkap. Dipanggil saat aplikasi start agar konfigurasi yang kurang langsung menghentikan aplikasi.
+func InitStorage() error {
+	defaultStorageOnce.Do(func() {
+		defaultStorage, defaultStorageErr = newStorageFromEnv()
+	})
+	return defaultStorageErr
+}
+
+// DefaultStorage mengembalikan storage yang sudah diinisialisasi oleh InitStorage
+func DefaultStorage() Storage {
+	if err := InitStorage(); err != nil {
+		panic(fmt.Sprintf("storage is not configured: %v", err))
+	}
+	return defaultStorage
+}
+
+func newStorageFromEnv() (Storage, error) {
+	switch StorageDriver() {
+	case "s3":
+		cfg := S3Config{
+			Endpoint:        config.GetEnv("S3_ENDPOINT", "http://localhost:9000"),
+			Region:          config.GetEnv("S3_REGION", "us-east-1"),
+			Bucket:          config.GetEnv("S3_BUCKET", "tokogo"),
+			AccessKeyID:     config.GetEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: config.GetEnv("S3_SECRET_ACCESS_KEY", ""),
+			PublicURL:       config.GetEnv("S3_PUBLIC_URL", ""),
+			PathStyle:       config.GetEnv("S3_USE_PATH_STYLE", "true") == "true",
+		}
+		if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			return nil, errors.New("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY must be set")
+		}
+		return NewS3Storage(cfg), nil
+	case "local":
+		// Secret tidak punya default karena siapa pun yang tahu secret bisa membuat signed URL sendiri
+		secret := config.GetEnv("STORAGE_SIGNING_SECRET", "")
+		if secret == "" {
+			return nil, errors.New("STORAGE_SIGNING_SECRET must be set")
+		}
+		return NewLocalStorage(
+			config.GetEnv("STORAGE_LOCAL_ROOT", "./storage"),
+			config.GetEnv("STORAGE_PUBLIC_BASE_URL", ""),
+			secret,
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q", StorageDriver())
+	}
+}
+
+// StorageDriver mengembalikan nama driver storage yang dikonfigurasi
+func StorageDriver() string {
+	return config.GetEnv("STORAGE_DRIVER", "local")
+}
+
+// PublicURL mengembalikan URL publik untuk key yang tersimpan di database
+func PublicURL(key string) string {
+	if key == "" {
+		return ""
+	}
+	return DefaultStorage().PublicURL(key)
+}
+
+// SignedURL mengembalikan URL sementara untuk key private yang tersimpan di database.
+// String kosong dikembalikan bila URL gagal dibuat.
+func SignedURL(key string) string {
+	if key == "" {
+		return ""
+	}
+	url, err := DefaultStorage().SignedURL(key, signedURLExpiry)
+	if err != nil {
+		fmt.Printf("Warning: Failed to sign URL for %s: %v\n", key, err)
+		return ""
+	}
+	return url
+}
+
+// storageKey membentuk key dari direktori upload dan nama file, misalnya
+// ("./uploads/products", "a.jpg") menjadi "uploads/products/a.jpg"
+func storageKey(dir, filename string) string {
+	return strings.TrimPrefix(path.Join(path.Clean(dir), filename), "./")
+}
+
+// cleanKey menormalisasi key dan menolak key yang keluar dari root storage
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + strings.ReplaceAll(key, "\\", "/"))
+	cleaned = strings.TrimPrefix(cleaned, "/")
+	if cleaned == "" || cleaned == "." || strings.Contains(key, "..") {
+		return "", errors.New("invalid storage key")
+	}
+	return cleaned, nil
+}
+
+// isPrivateKey mengecek apakah key berada di bawah PrivateKeyPrefix
+func isPrivateKey(key string) bool {
+	return strings.HasPrefix(key, PrivateKeyPrefix)
+}
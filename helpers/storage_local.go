@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage menyimpan file di filesystem lokal. Signed URL diarahkan ke
+// endpoint /files yang memverifikasi signature sebelum menyajikan file.
+type LocalStorage struct {
+	root          string
+	publicBaseURL string
+	secret        []byte
+}
+
+// NewLocalStorage membuat instance baru LocalStorage
+func NewLocalStorage(root, publicBaseURL, secret string) *LocalStorage {
+	return &LocalStorage{
+		root:          root,
+		publicBaseURL: strings.TrimSuffix(publicBaseURL, "/"),
+		secret:        []byte(secret),
+	}
+}
+
+func (s *LocalStorage) Put(key string, data []byte, contentType string) error {
+	fullPath, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return fmt.Errorf("failed to create upload directory: %v", err)
+	}
+
+	if err := os.WriteFile(fullPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	fullPath, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %v", err)
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) PublicURL(key string) string {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return ""
+	}
+	return s.publicBaseURL + "/" + cleaned
+}
+
+// SignedURL hanya dibuat untuk key di bawah PrivateKeyPrefix, satu-satunya direktori yang disajikan /files
+func (s *LocalStorage) SignedURL(key string, expiry time.Duration) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	if !isPrivateKey(cleaned) {
+		return "", errors.New("only private keys can be signed")
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(cleaned, expires))
+
+	return s.publicBaseURL + "/files/" + cleaned + "?" + query.Encode(), nil
+}
+
+// Verify memeriksa signature dan masa berlaku signed URL, lalu mengembalikan path file di disk.
+// Hanya key di bawah PrivateKeyPrefix yang bisa disajikan walaupun signature-nya valid.
+func (s *LocalStorage) Verify(key, expires, signature string) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	if !isPrivateKey(cleaned) {
+		return "", errors.New("file is not available")
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return "", errors.New("signed url has expired")
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(cleaned, expires))) {
+		return "", errors.New("invalid signature")
+	}
+
+	return s.Path(cleaned)
+}
+
+// Path mengembalikan lokasi file di disk untuk key
+func (s *LocalStorage) Path(key string) (string, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+func (s *LocalStorage) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
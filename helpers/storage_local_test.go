@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStorageServesOnlyPrivateKeys(t *testing.T) {
+	storage := NewLocalStorage(t.TempDir(), "", "test-secret")
+
+	if err := storage.Put("private/payment_proofs/a.jpg", []byte("proof"), "image/jpeg"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	signed, err := storage.SignedURL("private/payment_proofs/a.jpg", time.Minute)
+	if err != nil {
+		t.Fatalf("signed url: %v", err)
+	}
+	parsed, _ := url.Parse(signed)
+	key := strings.TrimPrefix(parsed.Path, "/files/")
+
+	filePath, err := storage.Verify(key, parsed.Query().Get("expires"), parsed.Query().Get("signature"))
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if data, _ := os.ReadFile(filePath); string(data) != "proof" {
+		t.Errorf("served file = %q", data)
+	}
+
+	if _, err := storage.SignedURL(".env", time.Minute); err == nil {
+		t.Error("signed url created for a key outside private/")
+	}
+
+	// Signature valid tetap ditolak untuk key di luar private/
+	expires := parsed.Query().Get("expires")
+	for _, key := range []string{".env", "uploads/products/a.jpg", "private/../.env"} {
+		if _, err := storage.Verify(key, expires, storage.sign(key, expires)); err == nil {
+			t.Errorf("verify accepted key %q", key)
+		}
+	}
+
+	if _, err := storage.Verify(key, parsed.Query().Get("expires"), "bad-signature"); err == nil {
+		t.Error("verify accepted an invalid signature")
+	}
+}
+
+func TestNewStorageFromEnvRequiresSecrets(t *testing.T) {
+	t.Setenv("STORAGE_DRIVER", "local")
+	t.Setenv("STORAGE_SIGNING_SECRET", "")
+	if _, err := newStorageFromEnv(); err == nil {
+		t.Error("local storage started without STORAGE_SIGNING_SECRET")
+	}
+
+	t.Setenv("STORAGE_SIGNING_SECRET", "secret")
+	t.Setenv("STORAGE_LOCAL_ROOT", "")
+	storage, err := newStorageFromEnv()
+	if err != nil {
+		t.Fatalf("local storage: %v", err)
+	}
+	if root := storage.(*LocalStorage).root; root != "./storage" {
+		t.Errorf("default root = %q, want ./storage", root)
+	}
+
+	t.Setenv("STORAGE_DRIVER", "s3")
+	t.Setenv("S3_ACCESS_KEY_ID", "")
+	if _, err := newStorageFromEnv(); err == nil {
+		t.Error("s3 storage started without credentials")
+	}
+
+	t.Setenv("STORAGE_DRIVER", "ftp")
+	if _, err := newStorageFromEnv(); err == nil {
+		t.Error("unknown storage driver accepted")
+	}
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
 	"time"
 	"tokogo/config"
 	"tokogo/handlers"
+	"tokogo/helpers"
 	"tokogo/middlewares"
+	"tokogo/services"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -18,9 +21,43 @@ func main() {
 		log.Println("No .env file found, using system environment variables")
 	}
 
+	// Storage upload wajib dikonfigurasi dengan benar sebelum aplikasi menerima request
+	if err := helpers.InitStorage(); err != nil {
+		log.Fatal("Failed to initialize storage: ", err)
+	}
+
+	// Payment gateway wajib dipilih secara eksplisit beserta secret webhook-nya
+	if err := services.InitPaymentGateway(); err != nil {
+		log.Fatal("Failed to initialize payment gateway: ", err)
+	}
+
 	// Initialize database
 	config.InitDB()
 
+	// Isi slug untuk product lama yang dibuat sebelum slug diperkenalkan
+	if err := services.NewProductService().BackfillSlugs(); err != nil {
+		log.Println("Failed to backfill product slugs:", err)
+	}
+
+	// Pindahkan gambar product lama ke galeri sebelum endpoint galeri dipakai
+	if err := services.NewProductImageService().BackfillLegacyImages(); err != nil {
+		log.Println("Failed to backfill product galleries:", err)
+	}
+
+	// Catat saldo awal ledger untuk stok yang sudah ada sebelum ledger diperkenalkan
+	if err := services.NewInventoryService().BackfillOpeningBalances(); err != nil {
+		log.Println("Failed to backfill opening stock balances:", err)
+	}
+
+	// Background job untuk expire order yang tidak dibayar
+	services.NewOrderExpiryService().Start(context.Background())
+
+	// Background job untuk membersihkan reservasi stok yang kedaluwarsa
+	services.NewStockReservationService().Start(context.Background())
+
+	// Background job untuk menghapus idempotency key yang kedaluwarsa
+	services.NewIdempotencyKeyService().Start(context.Background())
+
 	// Setup Gin router
 	r := gin.Default()
 
@@ -29,12 +66,17 @@ func main() {
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"*"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "Idempotent-Replayed"},
 		AllowCredentials: true,
 	}))
 
-	// Static file serving for uploaded images
-	r.Static("/uploads", "./uploads")
+	// Uploaded files are served from disk only when using local storage,
+	// S3-compatible storage serves them directly from the bucket
+	if storage, ok := helpers.DefaultStorage().(*helpers.LocalStorage); ok {
+		uploadsDir, _ := storage.Path("uploads")
+		r.Static("/uploads", uploadsDir)
+		r.GET("/files/*key", handlers.NewFileHandler(storage).ServeSignedFile)
+	}
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler()
@@ -45,6 +87,12 @@ func main() {
 	profileHandler := handlers.NewProfileHandler()
 	cartHandler := handlers.NewCartHandler()
 	checkoutHandler := handlers.NewCheckoutHandler()
+	paymentHandler := handlers.NewPaymentHandler()
+	inventoryHandler := handlers.NewInventoryHandler()
+	productVariantHandler := handlers.NewProductVariantHandler()
+	productImageHandler := handlers.NewProductImageHandler()
+	voucherHandler := handlers.NewVoucherHandler()
+	promotionHandler := handlers.NewPromotionHandler()
 
 	// Public routes (tidak perlu authentication)
 	api := r.Group("/api/v1")
@@ -56,18 +104,24 @@ func main() {
 			auth.POST("/login", authHandler.Login)
 		}
 
+		// Payment gateway webhook (diverifikasi dengan signature)
+		api.POST("/payments/webhook", paymentHandler.HandleWebhook)
+
 		// Public routes (untuk customer)
 		public := api.Group("/public")
 		{
 			categories := public.Group("/categories")
 			{
 				categories.GET("", categoryHandler.GetAllCategories)
+				categories.GET("/tree", categoryHandler.GetCategoryTree)
+				categories.GET("/:slug", categoryHandler.GetCategoryBySlug)
 			}
 
 			products := public.Group("/products")
 			{
 				products.GET("", productHandler.GetAllProductsPublic)
 				products.GET("/:id", productHandler.GetProductByIDPublic)
+				products.GET("/slug/:slug", productHandler.GetProductBySlugPublic)
 				products.GET("/categories/:category_id", productHandler.GetProductsByCategoryPublic)
 			}
 
@@ -103,10 +157,11 @@ func main() {
 		checkout := protected.Group("/checkout")
 		{
 			checkout.POST("/summary", checkoutHandler.GetCheckoutSummary)
-			checkout.POST("", checkoutHandler.ProcessCheckout)
-			checkout.POST("/:transaction_id/confirm", checkoutHandler.ConfirmPayment)
+			checkout.POST("", middlewares.IdempotencyMiddleware(), checkoutHandler.ProcessCheckout)
+			checkout.POST("/:transaction_id/confirm", middlewares.IdempotencyMiddleware(), checkoutHandler.ConfirmPayment)
 			checkout.GET("/transactions", checkoutHandler.GetUserTransactions)
 			checkout.GET("/transactions/:transaction_id", checkoutHandler.GetTransactionByID)
+			checkout.POST("/transactions/:transaction_id/cancel", checkoutHandler.CancelTransaction)
 		}
 
 		// Admin routes (perlu admin role)
@@ -125,19 +180,55 @@ func main() {
 			{
 				categories.POST("", categoryHandler.CreateCategory)
 				categories.GET("", categoryHandler.GetAllCategories)
+				categories.GET("/tree", categoryHandler.GetCategoryTree)
 				categories.GET("/:id", categoryHandler.GetCategoryByID)
 				categories.PUT("/:id", categoryHandler.UpdateCategory)
 				categories.DELETE("/:id", categoryHandler.DeleteCategory)
+				categories.POST("/:id/restore", categoryHandler.RestoreCategory)
 			}
 
 			products := admin.Group("/products")
 			{
 				products.POST("", productHandler.CreateProduct)
 				products.GET("", productHandler.GetAllProducts)
+				products.GET("/low-stock", productHandler.GetLowStockProducts)
+				products.GET("/archived", productHandler.GetArchivedProducts)
 				products.GET("/:id", productHandler.GetProductByID)
 				products.PUT("/:id", productHandler.UpdateProduct)
 				products.DELETE("/:id", productHandler.DeleteProduct)
+				products.POST("/:id/restore", productHandler.RestoreProduct)
+				products.GET("/:id/price-history", productHandler.GetPriceHistory)
 				products.GET("/categories/:category_id", productHandler.GetProductsByCategory)
+				products.GET("/:id/stock-movements", inventoryHandler.GetStockMovements)
+				products.GET("/:id/stock-reconciliation", inventoryHandler.ReconcileStock)
+				products.POST("/:id/stock-adjustments", inventoryHandler.AdjustStock)
+				products.GET("/:id/variants", productVariantHandler.GetVariants)
+				products.POST("/:id/variants", productVariantHandler.CreateVariant)
+				products.PUT("/:id/variants/:variant_id", productVariantHandler.UpdateVariant)
+				products.DELETE("/:id/variants/:variant_id", productVariantHandler.DeleteVariant)
+				products.GET("/:id/images", productImageHandler.GetImages)
+				products.POST("/:id/images", productImageHandler.UploadImages)
+				products.PUT("/:id/images/order", productImageHandler.ReorderImages)
+				products.PUT("/:id/images/:image_id/primary", productImageHandler.SetPrimaryImage)
+				products.DELETE("/:id/images/:image_id", productImageHandler.DeleteImage)
+			}
+
+			vouchers := admin.Group("/vouchers")
+			{
+				vouchers.POST("", voucherHandler.CreateVoucher)
+				vouchers.GET("", voucherHandler.GetAllVouchers)
+				vouchers.GET("/:id", voucherHandler.GetVoucherByID)
+				vouchers.PUT("/:id", voucherHandler.UpdateVoucher)
+				vouchers.DELETE("/:id", voucherHandler.DeleteVoucher)
+			}
+
+			promotions := admin.Group("/promotions")
+			{
+				promotions.POST("", promotionHandler.CreatePromotion)
+				promotions.GET("", promotionHandler.GetAllPromotions)
+				promotions.GET("/:id", promotionHandler.GetPromotionByID)
+				promotions.PUT("/:id", promotionHandler.UpdatePromotion)
+				promotions.DELETE("/:id", promotionHandler.DeletePromotion)
 			}
 
 			userManagement := admin.Group("/user-management")
@@ -152,8 +243,12 @@ func main() {
 			transactions := admin.Group("/transactions")
 			{
 				transactions.GET("", transactionHandler.GetAllTransactions)
+				transactions.GET("/payment-verifications", transactionHandler.GetPaymentVerificationQueue)
 				transactions.GET("/:id", transactionHandler.GetTransactionByID)
 				transactions.PUT("/:id/status", transactionHandler.UpdateTransactionStatus)
+				transactions.POST("/:id/cancel", transactionHandler.CancelTransaction)
+				transactions.POST("/:id/payment/approve", transactionHandler.ApprovePaymentProof)
+				transactions.POST("/:id/payment/reject", transactionHandler.RejectPaymentProof)
 			}
 		}
 	}
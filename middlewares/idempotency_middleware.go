@@ -0,0 +1,253 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+	"tokogo/config"
+	"tokogo/helpers"
+	"tokogo/models"
+	"tokogo/repositories"
+	"tokogo/responses"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyHeader adalah nama header yang dikirim client untuk request idempotent
+const IdempotencyHeader = "Idempotency-Key"
+
+// maxIdempotentBodySize membatasi body yang dibaca middleware, cukup untuk satu file upload beserta field form-nya
+const maxIdempotentBodySize = helpers.MaxUploadSize + 1024*1024
+
+// bodyCaptureWriter menyalin response body agar bisa disimpan setelah handler selesai
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware middleware untuk menghormati header Idempotency-Key.
+// Request ulang dengan key dan body yang sama mendapatkan response awal,
+// sedangkan key yang sama dengan body berbeda ditolak dengan 422.
+// Harus dipasang setelah AuthMiddleware.
+func IdempotencyMiddleware() gin.HandlerFunc {
+	ttlHours, err := strconv.Atoi(config.GetEnv("IDEMPOTENCY_KEY_TTL_HOURS", "24"))
+	if err != nil || ttlHours < 1 {
+		ttlHours = 24
+	}
+	ttl := time.Duration(ttlHours) * time.Hour
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > 255 {
+			c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Idempotency-Key must not exceed 255 characters",
+			})
+			c.Abort()
+			return
+		}
+
+		userID := c.GetUint("user_id")
+		if userID == 0 {
+			c.JSON(http.StatusUnauthorized, responses.ErrorResponse{
+				Error:   "unauthorized",
+				Message: "User not authenticated",
+			})
+			c.Abort()
+			return
+		}
+
+		// Baca body lalu kembalikan agar tetap bisa di-bind oleh handler
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, responses.ErrorResponse{
+					Error:   "payload_too_large",
+					Message: "Request body is too large",
+				})
+				c.Abort()
+				return
+			}
+			c.JSON(http.StatusBadRequest, responses.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Failed to read request body",
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		requestHash := hashRequest(c.Request.Method, c.Request.URL.Path, c.GetHeader("Content-Type"), body)
+		repo := repositories.NewIdempotencyRepository(config.DB)
+
+		existing, err := repo.GetByUserIDAndKey(userID, key)
+		if err == nil && existing.ExpiresAt.Before(time.Now()) {
+			// Key lama sudah kedaluwarsa, perlakukan sebagai key baru
+			if err := repo.Delete(existing.ID); err != nil {
+				abortIdempotencyError(c)
+				return
+			}
+			existing = nil
+		}
+
+		if err == nil && existing != nil {
+			replayIdempotentResponse(c, existing, requestHash)
+			return
+		}
+
+		record := &models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: requestHash,
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+
+		// Unique index (user_id, key) menjamin hanya satu request yang diproses
+		if err := repo.Create(record); err != nil {
+			if existing, err := repo.GetByUserIDAndKey(userID, key); err == nil {
+				replayIdempotentResponse(c, existing, requestHash)
+				return
+			}
+			abortIdempotencyError(c)
+			return
+		}
+
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		// Key dilepas bila handler panic agar retry tidak tertahan sebagai "in progress" sampai TTL habis
+		finished := false
+		defer func() {
+			if finished {
+				return
+			}
+			if err := repo.Delete(record.ID); err != nil {
+				log.Printf("Failed to release idempotency key %d: %v", record.ID, err)
+			}
+		}()
+
+		c.Next()
+		finished = true
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			// Error server tidak disimpan agar client bisa mencoba lagi dengan key yang sama
+			repo.Delete(record.ID)
+			return
+		}
+
+		// Key yang gagal disimpan dihapus agar tidak tertahan sebagai "in progress" dan bisa diulang
+		if err := repo.SaveResponse(record.ID, status, writer.body.String()); err != nil {
+			log.Printf("Failed to save idempotent response for key %d: %v", record.ID, err)
+			if err := repo.Delete(record.ID); err != nil {
+				log.Printf("Failed to release idempotency key %d: %v", record.ID, err)
+			}
+		}
+	}
+}
+
+// replayIdempotentResponse mengirim ulang response yang tersimpan untuk idempotency key
+func replayIdempotentResponse(c *gin.Context, existing *models.IdempotencyKey, requestHash string) {
+	if existing.RequestHash != requestHash {
+		c.JSON(http.StatusUnprocessableEntity, responses.ErrorResponse{
+			Error:   "idempotency_key_conflict",
+			Message: "Idempotency-Key has already been used with a different request",
+		})
+		c.Abort()
+		return
+	}
+
+	if !existing.IsCompleted() {
+		c.JSON(http.StatusConflict, responses.ErrorResponse{
+			Error:   "request_in_progress",
+			Message: "A request with this Idempotency-Key is still being processed",
+		})
+		c.Abort()
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(existing.ResponseStatus, "application/json; charset=utf-8", []byte(existing.ResponseBody))
+	c.Abort()
+}
+
+func abortIdempotencyError(c *gin.Context) {
+	c.JSON(http.StatusInternalServerError, responses.ErrorResponse{
+		Error:   "idempotency_error",
+		Message: "Failed to process Idempotency-Key",
+	})
+	c.Abort()
+}
+
+// hashRequest menghasilkan sidik jari request dari method, path, dan body. Body multipart di-hash
+// dari isi setiap field dan file-nya, sehingga retry dengan boundary baru tetap dianggap request yang sama.
+func hashRequest(method, path, contentType string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	if fingerprint, err := multipartFingerprint(contentType, body); err == nil {
+		hash.Write(fingerprint)
+	} else {
+		hash.Write(body)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// multipartFingerprint menyusun nama field, nama file, dan digest SHA-256 isi setiap part sesuai urutannya.
+// Mengembalikan error bila body bukan multipart/form-data yang valid.
+func multipartFingerprint(contentType string, body []byte) ([]byte, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	if mediaType != "multipart/form-data" {
+		return nil, http.ErrNotMultipart
+	}
+
+	var fingerprint bytes.Buffer
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return fingerprint.Bytes(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		digest := sha256.New()
+		if _, err := io.Copy(digest, part); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&fingerprint, "%q %q %x\n", part.FormName(), part.FileName(), digest.Sum(nil))
+	}
+}
@@ -0,0 +1,98 @@
+package middlewares
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartBody membentuk body multipart dengan boundary tertentu, seperti retry dari client
+func multipartBody(t *testing.T, boundary string, fields map[string]string, fileContent string) (string, []byte) {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.SetBoundary(boundary); err != nil {
+		t.Fatalf("set boundary: %v", err)
+	}
+	for _, name := range []string{"transaction_id", "note"} {
+		if value, ok := fields[name]; ok {
+			writer.WriteField(name, value)
+		}
+	}
+	file, _ := writer.CreateFormFile("proof", "bukti.jpg")
+	file.Write([]byte(fileContent))
+	writer.Close()
+
+	return writer.FormDataContentType(), body.Bytes()
+}
+
+func TestHashRequestIgnoresMultipartBoundary(t *testing.T) {
+	fields := map[string]string{"transaction_id": "10", "note": "transfer BCA"}
+
+	firstType, first := multipartBody(t, "boundary-first-attempt", fields, "receipt-bytes")
+	retryType, retry := multipartBody(t, "boundary-retry-attempt", fields, "receipt-bytes")
+	if bytes.Equal(first, retry) {
+		t.Fatal("test bodies should differ only by boundary")
+	}
+
+	original := hashRequest("POST", "/api/transactions/10/payment-proof", firstType, first)
+	if got := hashRequest("POST", "/api/transactions/10/payment-proof", retryType, retry); got != original {
+		t.Error("retry with a new boundary produced a different hash")
+	}
+
+	otherFileType, otherFile := multipartBody(t, "boundary-retry-attempt", fields, "other-receipt")
+	if hashRequest("POST", "/api/transactions/10/payment-proof", otherFileType, otherFile) == original {
+		t.Error("different file content produced the same hash")
+	}
+
+	otherFieldType, otherField := multipartBody(t, "boundary-retry-attempt", map[string]string{"transaction_id": "11", "note": "transfer BCA"}, "receipt-bytes")
+	if hashRequest("POST", "/api/transactions/10/payment-proof", otherFieldType, otherField) == original {
+		t.Error("different form field produced the same hash")
+	}
+}
+
+func TestHashRequestJSONBody(t *testing.T) {
+	body := []byte(`{"shipping_address":"Jl. Test 1","payment_method":"e_wallet"}`)
+
+	hash := hashRequest("POST", "/api/checkout", "application/json", body)
+	if hashRequest("POST", "/api/checkout", "application/json", body) != hash {
+		t.Error("same request produced a different hash")
+	}
+	if hashRequest("POST", "/api/checkout", "application/json", []byte(`{"shipping_address":"Jl. Test 2","payment_method":"e_wallet"}`)) == hash {
+		t.Error("different body produced the same hash")
+	}
+	if hashRequest("POST", "/api/cart", "application/json", body) == hash {
+		t.Error("different path produced the same hash")
+	}
+
+	// Body multipart yang rusak tetap di-hash apa adanya
+	if hashRequest("POST", "/api/checkout", "multipart/form-data; boundary=x", []byte("not multipart")) == "" {
+		t.Error("invalid multipart body produced an empty hash")
+	}
+}
+
+func TestIdempotencyMiddlewareRejectsOversizedBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Body yang terlalu besar ditolak sebelum idempotency key disimpan
+	router := gin.New()
+	router.POST("/api/checkout", func(c *gin.Context) {
+		c.Set("user_id", uint(1))
+	}, IdempotencyMiddleware(), func(c *gin.Context) {
+		t.Error("handler reached with an oversized body")
+	})
+
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/checkout", bytes.NewReader(make([]byte, maxIdempotentBodySize+1)))
+	req.Header.Set(IdempotencyHeader, "oversized-body")
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", recorder.Code)
+	}
+}
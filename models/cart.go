@@ -3,16 +3,26 @@ package models
 import "time"
 
 type Cart struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	UserID    uint      `json:"user_id" gorm:"not null"`
-	User      User      `json:"user" gorm:"foreignKey:UserID"`
-	ProductID uint      `json:"product_id" gorm:"not null"`
-	Product   Product   `json:"product" gorm:"foreignKey:ProductID"`
-	Quantity  int       `json:"quantity" gorm:"not null;default:1"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	UserID    uint            `json:"user_id" gorm:"not null"`
+	User      User            `json:"user" gorm:"foreignKey:UserID"`
+	ProductID uint            `json:"product_id" gorm:"not null"`
+	Product   Product         `json:"product" gorm:"foreignKey:ProductID"`
+	VariantID *uint           `json:"variant_id" gorm:"index"`
+	Variant   *ProductVariant `json:"variant" gorm:"foreignKey:VariantID"`
+	Quantity  int             `json:"quantity" gorm:"not null;default:1"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
 }
 
 func (Cart) TableName() string {
 	return "carts"
 }
+
+// UnitPrice mengembalikan harga satuan item yang berlaku pada waktu now, memakai harga variant bila ada
+func (c Cart) UnitPrice(now time.Time) float64 {
+	if c.Variant != nil {
+		return c.Variant.EffectivePrice(c.Product, now)
+	}
+	return c.Product.EffectivePrice(now)
+}
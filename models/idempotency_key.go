@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// IdempotencyKey menyimpan response dari request yang dikirim dengan header Idempotency-Key
+type IdempotencyKey struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	UserID         uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_idempotency_user_key"`
+	Key            string    `json:"key" gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_user_key"`
+	Method         string    `json:"method" gorm:"type:varchar(10);not null"`
+	Path           string    `json:"path" gorm:"type:varchar(255);not null"`
+	RequestHash    string    `json:"request_hash" gorm:"type:char(64);not null"`
+	ResponseStatus int       `json:"response_status" gorm:"not null;default:0"`
+	ResponseBody   string    `json:"response_body" gorm:"type:longtext"`
+	ExpiresAt      time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for IdempotencyKey
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// IsCompleted menandakan response untuk key ini sudah tersimpan
+func (k IdempotencyKey) IsCompleted() bool {
+	return k.ResponseStatus != 0
+}
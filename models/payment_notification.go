@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// PaymentNotification menyimpan setiap notifikasi mentah dari payment gateway untuk rekonsiliasi
+type PaymentNotification struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	Gateway        string    `json:"gateway" gorm:"type:varchar(50);not null"`
+	EventID        string    `json:"event_id" gorm:"type:varchar(255);index"`
+	TransactionID  *uint     `json:"transaction_id" gorm:"index"`
+	Status         string    `json:"status" gorm:"type:varchar(30)"`
+	Payload        string    `json:"payload" gorm:"type:longtext;not null"`
+	Signature      string    `json:"signature" gorm:"type:varchar(255)"`
+	SignatureValid bool      `json:"signature_valid" gorm:"not null;default:false"`
+	Processed      bool      `json:"processed" gorm:"not null;default:false"`
+	Error          string    `json:"error" gorm:"type:text"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ProcessedPaymentEvent menandai event payment gateway yang sudah diproses. Dicatat di transaction
+// yang sama dengan perubahan order, dan unique index (gateway, event_id) membuat pengiriman ulang
+// yang datang bersamaan tidak ikut diproses.
+type ProcessedPaymentEvent struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	Gateway       string    `json:"gateway" gorm:"type:varchar(50);not null;uniqueIndex:idx_processed_payment_event"`
+	EventID       string    `json:"event_id" gorm:"type:varchar(255);not null;uniqueIndex:idx_processed_payment_event"`
+	TransactionID uint      `json:"transaction_id" gorm:"not null;index"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for PaymentNotification
+func (PaymentNotification) TableName() string {
+	return "payment_notifications"
+}
+
+// TableName returns the table name for ProcessedPaymentEvent
+func (ProcessedPaymentEvent) TableName() string {
+	return "processed_payment_events"
+}
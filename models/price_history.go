@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// PriceHistory adalah catatan append-only setiap perubahan harga product
+type PriceHistory struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	ProductID      uint       `json:"product_id" gorm:"not null;index:idx_price_histories_product_created"`
+	PurchasePrice  float64    `json:"purchase_price" gorm:"not null;type:decimal(10,2)"`
+	SellingPrice   float64    `json:"selling_price" gorm:"not null;type:decimal(10,2)"`
+	CompareAtPrice *float64   `json:"compare_at_price" gorm:"type:decimal(10,2)"`
+	SalePrice      *float64   `json:"sale_price" gorm:"type:decimal(10,2)"`
+	SaleStartsAt   *time.Time `json:"sale_starts_at"`
+	SaleEndsAt     *time.Time `json:"sale_ends_at"`
+	ActorID        *uint      `json:"actor_id"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"index:idx_price_histories_product_created"`
+}
+
+// TableName returns the table name for PriceHistory
+func (PriceHistory) TableName() string {
+	return "price_histories"
+}
+
+// NewPriceHistory membuat catatan harga product saat ini
+func NewPriceHistory(product Product, actorID uint) PriceHistory {
+	return PriceHistory{
+		ProductID:      product.ID,
+		PurchasePrice:  product.PurchasePrice,
+		SellingPrice:   product.SellingPrice,
+		CompareAtPrice: product.CompareAtPrice,
+		SalePrice:      product.SalePrice,
+		SaleStartsAt:   product.SaleStartsAt,
+		SaleEndsAt:     product.SaleEndsAt,
+		ActorID:        &actorID,
+	}
+}
+
+// SamePrices mengecek apakah harga product masih sama dengan catatan ini
+func (h PriceHistory) SamePrices(product Product) bool {
+	return h.PurchasePrice == product.PurchasePrice &&
+		h.SellingPrice == product.SellingPrice &&
+		equalFloatPtr(h.CompareAtPrice, product.CompareAtPrice) &&
+		equalFloatPtr(h.SalePrice, product.SalePrice) &&
+		equalTimePtr(h.SaleStartsAt, product.SaleStartsAt) &&
+		equalTimePtr(h.SaleEndsAt, product.SaleEndsAt)
+}
+
+func equalFloatPtr(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
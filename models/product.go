@@ -1,21 +1,110 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status publikasi product
+const (
+	ProductStatusDraft     = "draft"
+	ProductStatusPublished = "published"
+	ProductStatusHidden    = "hidden"
+)
 
 type Product struct {
-	ID            uint      `json:"id" gorm:"primaryKey"`
-	Name          string    `json:"name" gorm:"not null"`
-	Description   string    `json:"description"`
-	PurchasePrice float64   `json:"purchase_price" gorm:"not null;type:decimal(10,2)"`
-	SellingPrice  float64   `json:"selling_price" gorm:"not null;type:decimal(10,2)"`
-	Stock         int       `json:"stock" gorm:"not null;default:0"`
-	CategoryID    uint      `json:"category_id" gorm:"not null"`
-	Category      Category  `json:"category" gorm:"foreignKey:CategoryID"`
-	ImageURL      string    `json:"image_url"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID                uint             `json:"id" gorm:"primaryKey"`
+	Name              string           `json:"name" gorm:"not null;index:idx_products_name_fulltext,class:FULLTEXT;index:idx_products_search_fulltext,class:FULLTEXT"`
+	Slug              string           `json:"slug" gorm:"type:varchar(255);uniqueIndex"`
+	Description       string           `json:"description" gorm:"index:idx_products_search_fulltext,class:FULLTEXT"`
+	PurchasePrice     float64          `json:"purchase_price" gorm:"not null;type:decimal(10,2)"`
+	SellingPrice      float64          `json:"selling_price" gorm:"not null;type:decimal(10,2)"`
+	CompareAtPrice    *float64         `json:"compare_at_price" gorm:"type:decimal(10,2)"`
+	SalePrice         *float64         `json:"sale_price" gorm:"type:decimal(10,2)"`
+	SaleStartsAt      *time.Time       `json:"sale_starts_at"`
+	SaleEndsAt        *time.Time       `json:"sale_ends_at"`
+	Stock             int              `json:"stock" gorm:"not null;default:0"`
+	LowStockThreshold int              `json:"low_stock_threshold" gorm:"not null;default:0"`
+	CategoryID        uint             `json:"category_id" gorm:"not null"`
+	Category          Category         `json:"category" gorm:"foreignKey:CategoryID"`
+	ImageURL          string           `json:"image_url"`
+	Status            string           `json:"status" gorm:"type:varchar(20);not null;default:published;index"`
+	PublishAt         *time.Time       `json:"publish_at" gorm:"index"`
+	UnpublishAt       *time.Time       `json:"unpublish_at"`
+	Variants          []ProductVariant `json:"variants" gorm:"foreignKey:ProductID"`
+	Images            []ProductImage   `json:"images" gorm:"foreignKey:ProductID"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt   `json:"-" gorm:"index"`
 }
 
 func (Product) TableName() string {
 	return "products"
 }
+
+// IsLowStock mengecek apakah stok sudah mencapai atau di bawah batas low stock.
+// Stok product dengan variant ada di variant-nya, sehingga product dianggap low stock
+// bila salah satu variant mencapai batas tersebut.
+func (p Product) IsLowStock() bool {
+	if len(p.Variants) == 0 {
+		return p.Stock <= p.LowStockThreshold
+	}
+	for _, variant := range p.Variants {
+		if variant.Stock <= p.LowStockThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// IsArchived mengecek apakah product sudah diarsipkan (soft delete)
+func (p Product) IsArchived() bool {
+	return p.DeletedAt.Valid
+}
+
+// IsVisible mengecek apakah product tampil di storefront pada waktu now:
+// status published dan berada di dalam jadwal publish_at/unpublish_at jika diisi
+func (p Product) IsVisible(now time.Time) bool {
+	if p.Status != ProductStatusPublished || p.IsArchived() {
+		return false
+	}
+	if p.PublishAt != nil && p.PublishAt.After(now) {
+		return false
+	}
+	return p.UnpublishAt == nil || p.UnpublishAt.After(now)
+}
+
+// IsOnSale mengecek apakah harga sale berlaku pada waktu now
+func (p Product) IsOnSale(now time.Time) bool {
+	if p.SalePrice == nil || *p.SalePrice >= p.SellingPrice {
+		return false
+	}
+	if p.SaleStartsAt != nil && p.SaleStartsAt.After(now) {
+		return false
+	}
+	return p.SaleEndsAt == nil || p.SaleEndsAt.After(now)
+}
+
+// EffectivePrice mengembalikan harga jual yang berlaku pada waktu now
+func (p Product) EffectivePrice(now time.Time) float64 {
+	if p.IsOnSale(now) {
+		return *p.SalePrice
+	}
+	return p.SellingPrice
+}
+
+// CompareAtPriceAt mengembalikan harga coret yang ditampilkan di samping harga yang berlaku.
+// Compare-at price dipakai jika lebih tinggi, jika tidak harga normal saat sale sedang aktif.
+func (p Product) CompareAtPriceAt(now time.Time) *float64 {
+	price := p.EffectivePrice(now)
+	if p.CompareAtPrice != nil && *p.CompareAtPrice > price {
+		compareAt := *p.CompareAtPrice
+		return &compareAt
+	}
+	if p.IsOnSale(now) {
+		compareAt := p.SellingPrice
+		return &compareAt
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ProductImage adalah satu gambar di galeri product. Gambar primary juga
+// disalin ke Product.ImageURL agar client lama tetap mendapat satu gambar utama.
+type ProductImage struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ProductID    uint      `json:"product_id" gorm:"not null;index"`
+	ImageURL     string    `json:"image_url" gorm:"not null"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	MediumURL    string    `json:"medium_url"`
+	LargeURL     string    `json:"large_url"`
+	Position     int       `json:"position" gorm:"not null;default:0"`
+	IsPrimary    bool      `json:"is_primary" gorm:"not null;default:false"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name for ProductImage
+func (ProductImage) TableName() string {
+	return "product_images"
+}
+
+// Paths mengembalikan path gambar asli beserta semua rendition-nya
+func (i ProductImage) Paths() []string {
+	return []string{i.ImageURL, i.ThumbnailURL, i.MediumURL, i.LargeURL}
+}
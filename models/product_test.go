@@ -0,0 +1,27 @@
+package models
+
+import "testing"
+
+func TestProductIsLowStock(t *testing.T) {
+	tests := []struct {
+		name     string
+		stock    int
+		variants []ProductVariant
+		want     bool
+	}{
+		{name: "above threshold", stock: 10, want: false},
+		{name: "at threshold", stock: 5, want: true},
+		// Stok product dengan variant selalu 0, yang dibandingkan stok variant-nya
+		{name: "variants above threshold", stock: 0, variants: []ProductVariant{{Stock: 8}, {Stock: 12}}, want: false},
+		{name: "one variant at threshold", stock: 0, variants: []ProductVariant{{Stock: 8}, {Stock: 3}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := Product{Stock: tt.stock, LowStockThreshold: 5, Variants: tt.variants}
+			if got := product.IsLowStock(); got != tt.want {
+				t.Errorf("low stock = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
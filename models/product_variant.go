@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ProductVariant adalah kombinasi opsi (misalnya ukuran dan warna) dari sebuah product
+// dengan SKU, harga, dan stok sendiri
+type ProductVariant struct {
+	ID            uint              `json:"id" gorm:"primaryKey"`
+	ProductID     uint              `json:"product_id" gorm:"not null;index"`
+	SKU           string            `json:"sku" gorm:"type:varchar(100);uniqueIndex;not null"`
+	Options       map[string]string `json:"options" gorm:"type:json;serializer:json;not null"`
+	PriceOverride *float64          `json:"price_override" gorm:"type:decimal(10,2)"`
+	Stock         int               `json:"stock" gorm:"not null;default:0"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt    `json:"-" gorm:"index"`
+}
+
+// TableName returns the table name for ProductVariant
+func (ProductVariant) TableName() string {
+	return "product_variants"
+}
+
+// EffectivePrice mengembalikan harga variant, atau harga product yang berlaku pada waktu now
+// (termasuk harga sale) bila tidak di-override
+func (v ProductVariant) EffectivePrice(product Product, now time.Time) float64 {
+	if v.PriceOverride != nil {
+		return *v.PriceOverride
+	}
+	return product.EffectivePrice(now)
+}
+
+// SameOptions mengecek apakah dua variant memiliki kombinasi opsi yang sama
+func (v ProductVariant) SameOptions(options map[string]string) bool {
+	if len(v.Options) != len(options) {
+		return false
+	}
+	for name, value := range v.Options {
+		if options[name] != value {
+			return false
+		}
+	}
+	return true
+}
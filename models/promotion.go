@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Jenis promosi otomatis
+const (
+	PromotionTypeBuyXGetY     = "buy_x_get_y"
+	PromotionTypeQuantityTier = "quantity_tier"
+	PromotionTypeBundle       = "bundle"
+)
+
+// Promotion adalah promosi otomatis yang dievaluasi saat checkout tanpa kode.
+// Promosi dengan Priority lebih tinggi dievaluasi lebih dulu, bila sama yang ID-nya lebih kecil.
+// Setiap unit item cart hanya bisa mendapat satu promosi, dan promosi Exclusive yang berlaku
+// menghentikan evaluasi promosi berikutnya.
+type Promotion struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	Name        string `json:"name" gorm:"type:varchar(255);not null"`
+	Description string `json:"description" gorm:"type:text"`
+	Type        string `json:"type" gorm:"type:varchar(20);not null"`
+	Priority    int    `json:"priority" gorm:"not null;default:0"`
+	Exclusive   bool   `json:"exclusive" gorm:"not null"`
+	IsActive    bool   `json:"is_active" gorm:"not null"`
+	// BuyQuantity dan GetQuantity dipakai buy_x_get_y: beli BuyQuantity, GetQuantity unit
+	// termurah berikutnya mendapat diskon GetDiscountPercent (100 berarti gratis)
+	BuyQuantity        int     `json:"buy_quantity" gorm:"not null;default:0"`
+	GetQuantity        int     `json:"get_quantity" gorm:"not null;default:0"`
+	GetDiscountPercent float64 `json:"get_discount_percent" gorm:"type:decimal(5,2);not null;default:0"`
+	// BundlePrice adalah harga satu paket bundle berisi seluruh Items
+	BundlePrice *float64 `json:"bundle_price" gorm:"type:decimal(15,2)"`
+	// Items membatasi product yang ikut promosi. Untuk bundle, Items adalah isi paket beserta quantity-nya.
+	// Items kosong pada buy_x_get_y dan quantity_tier berarti berlaku untuk semua product.
+	Items     []PromotionItem `json:"items" gorm:"foreignKey:PromotionID"`
+	Tiers     []PromotionTier `json:"tiers" gorm:"foreignKey:PromotionID"`
+	StartsAt  *time.Time      `json:"starts_at"`
+	EndsAt    *time.Time      `json:"ends_at"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	DeletedAt gorm.DeletedAt  `json:"-" gorm:"index"`
+}
+
+// PromotionItem adalah product yang ikut promosi
+type PromotionItem struct {
+	ID          uint    `json:"id" gorm:"primaryKey"`
+	PromotionID uint    `json:"promotion_id" gorm:"not null;index"`
+	ProductID   uint    `json:"product_id" gorm:"not null"`
+	Product     Product `json:"product" gorm:"foreignKey:ProductID"`
+	Quantity    int     `json:"quantity" gorm:"not null;default:1"`
+}
+
+// PromotionTier adalah satu tingkat diskon quantity_tier
+type PromotionTier struct {
+	ID              uint    `json:"id" gorm:"primaryKey"`
+	PromotionID     uint    `json:"promotion_id" gorm:"not null;index"`
+	MinQuantity     int     `json:"min_quantity" gorm:"not null"`
+	DiscountPercent float64 `json:"discount_percent" gorm:"type:decimal(5,2);not null"`
+}
+
+// AppliedPromotion adalah diskon dari satu promosi pada satu item, disimpan di detail transaksi
+type AppliedPromotion struct {
+	PromotionID uint    `json:"promotion_id"`
+	Name        string  `json:"name"`
+	Discount    float64 `json:"discount"`
+}
+
+// TableName returns the table name for Promotion
+func (Promotion) TableName() string {
+	return "promotions"
+}
+
+// TableName returns the table name for PromotionItem
+func (PromotionItem) TableName() string {
+	return "promotion_items"
+}
+
+// TableName returns the table name for PromotionTier
+func (PromotionTier) TableName() string {
+	return "promotion_tiers"
+}
+
+// IsRunning mengecek apakah promosi aktif dan berada dalam periode berlakunya pada waktu now
+func (p Promotion) IsRunning(now time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.StartsAt != nil && p.StartsAt.After(now) {
+		return false
+	}
+	return p.EndsAt == nil || p.EndsAt.After(now)
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Jenis entity yang memiliki slug
+const (
+	SlugEntityProduct  = "product"
+	SlugEntityCategory = "category"
+)
+
+// SlugRedirect menyimpan slug lama setelah product atau category di-rename
+// agar URL lama tetap bisa diarahkan ke slug yang baru
+type SlugRedirect struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	EntityType string    `json:"entity_type" gorm:"type:varchar(20);not null;uniqueIndex:idx_slug_redirects_entity_slug"`
+	Slug       string    `json:"slug" gorm:"type:varchar(255);not null;uniqueIndex:idx_slug_redirects_entity_slug"`
+	EntityID   uint      `json:"entity_id" gorm:"not null;index"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for SlugRedirect
+func (SlugRedirect) TableName() string {
+	return "slug_redirects"
+}
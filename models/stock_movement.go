@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Alasan perubahan stok
+const (
+	StockMovementSale             = "sale"
+	StockMovementCancellation     = "cancellation"
+	StockMovementManualAdjustment = "manual_adjustment"
+	StockMovementRestock          = "restock"
+	StockMovementReturn           = "return"
+	StockMovementOpeningBalance   = "opening_balance" // stok yang sudah ada sebelum ledger diperkenalkan
+)
+
+// StockMovement adalah ledger append-only untuk setiap perubahan stok product
+type StockMovement struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ProductID   uint      `json:"product_id" gorm:"not null;index"`
+	Product     Product   `json:"product" gorm:"foreignKey:ProductID"`
+	VariantID   *uint     `json:"variant_id" gorm:"index"`
+	Delta       int       `json:"delta" gorm:"not null"`
+	Reason      string    `json:"reason" gorm:"type:varchar(30);not null"`
+	ReferenceID *uint     `json:"reference_id" gorm:"index"`
+	ActorID     *uint     `json:"actor_id"`
+	Note        string    `json:"note" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for StockMovement
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
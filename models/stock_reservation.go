@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// StockReservation menahan stok product untuk user selama proses checkout
+type StockReservation struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	ProductID uint      `json:"product_id" gorm:"not null;index:idx_stock_reservation_product_expiry"`
+	VariantID *uint     `json:"variant_id" gorm:"index"`
+	Quantity  int       `json:"quantity" gorm:"not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index:idx_stock_reservation_product_expiry"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for StockReservation
+func (StockReservation) TableName() string {
+	return "stock_reservations"
+}
@@ -1,35 +1,243 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// Status transaksi
+const (
+	TransactionStatusPending              = "pending"
+	TransactionStatusAwaitingVerification = "awaiting_verification"
+	TransactionStatusPaid                 = "paid"
+	TransactionStatusProcessing           = "processing"
+	TransactionStatusShipped              = "shipped"
+	TransactionStatusDelivered            = "delivered"
+	TransactionStatusCompleted            = "completed"
+	TransactionStatusCancelled            = "cancelled"
+	TransactionStatusRefunded             = "refunded"
+	TransactionStatusFailed               = "failed"
+	TransactionStatusExpired              = "expired"
+)
+
+// Status refund dana melalui payment gateway
+const (
+	RefundStatusPending   = "pending"
+	RefundStatusSucceeded = "succeeded"
+	RefundStatusFailed    = "failed"
+)
+
+// Metode pembayaran
+const (
+	PaymentMethodCOD          = "cod"
+	PaymentMethodBankTransfer = "bank_transfer"
+)
+
+// transactionTransitions mendefinisikan perpindahan status yang diizinkan
+var transactionTransitions = map[string][]string{
+	TransactionStatusPending: {
+		TransactionStatusAwaitingVerification,
+		TransactionStatusPaid,
+		TransactionStatusProcessing,
+		TransactionStatusCancelled,
+		TransactionStatusFailed,
+		TransactionStatusExpired,
+	},
+	TransactionStatusAwaitingVerification: {
+		TransactionStatusPaid,
+		TransactionStatusPending,
+		TransactionStatusCancelled,
+	},
+	TransactionStatusPaid: {
+		TransactionStatusProcessing,
+		TransactionStatusCancelled,
+		TransactionStatusRefunded,
+	},
+	TransactionStatusProcessing: {
+		TransactionStatusShipped,
+		TransactionStatusCancelled,
+		TransactionStatusRefunded,
+	},
+	TransactionStatusShipped: {
+		TransactionStatusDelivered,
+	},
+	TransactionStatusDelivered: {
+		TransactionStatusCompleted,
+		TransactionStatusRefunded,
+	},
+	TransactionStatusCompleted: {
+		TransactionStatusRefunded,
+	},
+}
 
 // Transaction represents the transaction model
 type Transaction struct {
-	ID                 uint                `json:"id" gorm:"primaryKey"`
-	UserID             uint                `json:"user_id" gorm:"not null"`
-	User               User                `json:"user" gorm:"foreignKey:UserID"`
-	Status             string              `json:"status" gorm:"type:enum('pending','paid','failed','expired');default:'pending'"`
-	TotalAmount        float64             `json:"total_amount" gorm:"type:decimal(15,2);not null"`
-	ShippingAddress    string              `json:"shipping_address" gorm:"type:text;not null"`
-	PaymentMethod      string              `json:"payment_method" gorm:"type:varchar(50);not null"`
-	PaymentURL         string              `json:"payment_url" gorm:"type:varchar(500)"`
-	PaymentProof       string              `json:"payment_proof" gorm:"type:varchar(500)"`
-	Notes              string              `json:"notes" gorm:"type:text"`
-	TransactionDetails []TransactionDetail `json:"transaction_details" gorm:"foreignKey:TransactionID"`
-	CreatedAt          time.Time           `json:"created_at"`
-	UpdatedAt          time.Time           `json:"updated_at"`
+	ID                   uint                       `json:"id" gorm:"primaryKey"`
+	UserID               uint                       `json:"user_id" gorm:"not null"`
+	User                 User                       `json:"user" gorm:"foreignKey:UserID"`
+	Status               string                     `json:"status" gorm:"type:enum('pending','awaiting_verification','paid','processing','shipped','delivered','completed','cancelled','refunded','failed','expired');default:'pending'"`
+	TotalAmount          float64                    `json:"total_amount" gorm:"type:decimal(15,2);not null"`
+	VoucherID            *uint                      `json:"voucher_id" gorm:"index"`
+	VoucherCode          string                     `json:"voucher_code" gorm:"type:varchar(50)"`
+	DiscountAmount       float64                    `json:"discount_amount" gorm:"type:decimal(15,2);not null;default:0"`
+	PromotionDiscount    float64                    `json:"promotion_discount" gorm:"type:decimal(15,2);not null;default:0"`
+	ShippingAddress      string                     `json:"shipping_address" gorm:"type:text;not null"`
+	PaymentMethod        string                     `json:"payment_method" gorm:"type:varchar(50);not null"`
+	PaymentReference     string                     `json:"payment_reference" gorm:"type:varchar(255);index"`
+	PaymentURL           string                     `json:"payment_url" gorm:"type:varchar(500)"`
+	PaymentProof         string                     `json:"payment_proof" gorm:"type:varchar(500)"`
+	PaymentRejectionNote string                     `json:"payment_rejection_note" gorm:"type:text"`
+	PaymentDueAt         *time.Time                 `json:"payment_due_at" gorm:"index"`
+	Notes                string                     `json:"notes" gorm:"type:text"`
+	CancellationReason   string                     `json:"cancellation_reason" gorm:"type:text"`
+	RefundStatus         string                     `json:"refund_status" gorm:"type:varchar(20)"`
+	RefundError          string                     `json:"refund_error" gorm:"type:text"`
+	RefundStartedAt      *time.Time                 `json:"refund_started_at"`
+	RefundedAt           *time.Time                 `json:"refunded_at"`
+	LatePaymentAt        *time.Time                 `json:"late_payment_at"`
+	TransactionDetails   []TransactionDetail        `json:"transaction_details" gorm:"foreignKey:TransactionID"`
+	StatusHistories      []TransactionStatusHistory `json:"status_histories" gorm:"foreignKey:TransactionID"`
+	CreatedAt            time.Time                  `json:"created_at"`
+	UpdatedAt            time.Time                  `json:"updated_at"`
 }
 
 // TransactionDetail represents the transaction detail model
 type TransactionDetail struct {
-	ID            uint        `json:"id" gorm:"primaryKey"`
-	TransactionID uint        `json:"transaction_id" gorm:"not null"`
-	Transaction   Transaction `json:"transaction" gorm:"foreignKey:TransactionID"`
-	ProductID     uint        `json:"product_id" gorm:"not null"`
-	Product       Product     `json:"product" gorm:"foreignKey:ProductID"`
-	Quantity      int         `json:"quantity" gorm:"not null"`
-	Price         float64     `json:"price" gorm:"type:decimal(15,2);not null"`
-	CreatedAt     time.Time   `json:"created_at"`
-	UpdatedAt     time.Time   `json:"updated_at"`
+	ID            uint               `json:"id" gorm:"primaryKey"`
+	TransactionID uint               `json:"transaction_id" gorm:"not null"`
+	Transaction   Transaction        `json:"transaction" gorm:"foreignKey:TransactionID"`
+	ProductID     uint               `json:"product_id" gorm:"not null"`
+	Product       Product            `json:"product" gorm:"foreignKey:ProductID"`
+	VariantID     *uint              `json:"variant_id" gorm:"index"`
+	Variant       *ProductVariant    `json:"variant" gorm:"foreignKey:VariantID"`
+	Quantity      int                `json:"quantity" gorm:"not null"`
+	Price         float64            `json:"price" gorm:"type:decimal(15,2);not null"`
+	Discount      float64            `json:"discount" gorm:"type:decimal(15,2);not null;default:0"`
+	Promotions    []AppliedPromotion `json:"promotions" gorm:"type:json;serializer:json"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+// TransactionStatusHistory mencatat setiap perubahan status transaksi
+type TransactionStatusHistory struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	TransactionID uint      `json:"transaction_id" gorm:"not null;index"`
+	FromStatus    string    `json:"from_status" gorm:"type:varchar(30)"`
+	ToStatus      string    `json:"to_status" gorm:"type:varchar(30);not null"`
+	ActorID       *uint     `json:"actor_id"`
+	ActorRole     string    `json:"actor_role" gorm:"type:varchar(20);not null"`
+	Note          string    `json:"note" gorm:"type:text"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Actor role untuk status history
+const (
+	ActorRoleCustomer = "customer"
+	ActorRoleAdmin    = "admin"
+	ActorRoleSystem   = "system"
+)
+
+// IsValidTransactionStatus mengecek apakah status dikenal
+func IsValidTransactionStatus(status string) bool {
+	if _, ok := transactionTransitions[status]; ok {
+		return true
+	}
+	switch status {
+	case TransactionStatusCancelled, TransactionStatusRefunded, TransactionStatusFailed, TransactionStatusExpired:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo mengecek apakah transaksi boleh berpindah ke status tujuan
+func (t *Transaction) CanTransitionTo(status string) error {
+	if !IsValidTransactionStatus(status) {
+		return fmt.Errorf("unknown transaction status %s", status)
+	}
+
+	// Pembayaran yang masuk setelah order ditutup hanya bisa diselesaikan dengan refund
+	if status == TransactionStatusRefunded && t.HasLatePayment() {
+		return nil
+	}
+
+	allowed := false
+	for _, next := range transactionTransitions[t.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("cannot change transaction status from %s to %s", t.Status, status)
+	}
+
+	// Order non-COD harus dibayar sebelum diproses dan dikirim
+	if t.Status == TransactionStatusPending && status == TransactionStatusProcessing && t.PaymentMethod != PaymentMethodCOD {
+		return fmt.Errorf("cannot process unpaid %s order", t.PaymentMethod)
+	}
+
+	return nil
+}
+
+// IsPaymentOverdue mengecek apakah batas waktu pembayaran sudah lewat pada waktu now.
+// Order lama tanpa payment_due_at memakai created_at ditambah window.
+func (t *Transaction) IsPaymentOverdue(now time.Time, window time.Duration) bool {
+	dueAt := t.CreatedAt.Add(window)
+	if t.PaymentDueAt != nil {
+		dueAt = *t.PaymentDueAt
+	}
+	return now.After(dueAt)
+}
+
+// IsPaymentCollected mengecek apakah dana order sudah diterima dan belum dikembalikan.
+// Order COD yang sedang diproses belum dibayar.
+func (t *Transaction) IsPaymentCollected() bool {
+	switch t.Status {
+	case TransactionStatusPaid:
+		return true
+	case TransactionStatusProcessing:
+		return t.PaymentMethod != PaymentMethodCOD
+	}
+	return false
+}
+
+// HasLatePayment mengecek apakah dana diterima payment gateway setelah order dibatalkan,
+// gagal atau kedaluwarsa sehingga harus dikembalikan lewat refund
+func (t *Transaction) HasLatePayment() bool {
+	if t.LatePaymentAt == nil {
+		return false
+	}
+	switch t.Status {
+	case TransactionStatusCancelled, TransactionStatusFailed, TransactionStatusExpired:
+		return true
+	}
+	return false
+}
+
+// IsRefundInProgress mengecek apakah refund masih menunggu hasil dari payment gateway pada waktu now.
+// Refund pending yang dimulai lebih lama dari staleAfter dianggap terhenti (misalnya proses mati
+// sebelum hasilnya dicatat) sehingga boleh diulang.
+func (t *Transaction) IsRefundInProgress(now time.Time, staleAfter time.Duration) bool {
+	if t.RefundStatus != RefundStatusPending {
+		return false
+	}
+	return t.RefundStartedAt != nil && now.Before(t.RefundStartedAt.Add(staleAfter))
+}
+
+// IsCancellableBy mengecek apakah transaksi masih boleh dibatalkan oleh role tertentu.
+// Customer hanya boleh membatalkan sebelum order dibayar atau diproses, admin sampai sebelum dikirim.
+// Order yang sudah dibayar tidak bisa dibatalkan, dananya harus dikembalikan lewat refund oleh admin.
+func (t *Transaction) IsCancellableBy(actorRole string) bool {
+	if t.IsPaymentCollected() {
+		return false
+	}
+	switch t.Status {
+	case TransactionStatusPending, TransactionStatusAwaitingVerification:
+		return true
+	case TransactionStatusProcessing:
+		return actorRole == ActorRoleAdmin
+	}
+	return false
 }
 
 // TableName returns the table name for Transaction
@@ -41,3 +249,8 @@ func (Transaction) TableName() string {
 func (TransactionDetail) TableName() string {
 	return "transaction_details"
 }
+
+// TableName returns the table name for TransactionStatusHistory
+func (TransactionStatusHistory) TableName() string {
+	return "transaction_status_histories"
+}
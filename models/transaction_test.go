@@ -0,0 +1,152 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTransactionIsCancellableBy(t *testing.T) {
+	tests := []struct {
+		status        string
+		paymentMethod string
+		customer      bool
+		admin         bool
+	}{
+		{TransactionStatusPending, "e_wallet", true, true},
+		{TransactionStatusAwaitingVerification, PaymentMethodBankTransfer, true, true},
+		// Order yang sudah dibayar harus di-refund, bukan dibatalkan
+		{TransactionStatusPaid, "e_wallet", false, false},
+		{TransactionStatusProcessing, "credit_card", false, false},
+		{TransactionStatusProcessing, PaymentMethodCOD, false, true},
+		{TransactionStatusShipped, PaymentMethodCOD, false, false},
+	}
+
+	for _, tt := range tests {
+		transaction := Transaction{Status: tt.status, PaymentMethod: tt.paymentMethod}
+		if got := transaction.IsCancellableBy(ActorRoleCustomer); got != tt.customer {
+			t.Errorf("%s/%s cancellable by customer = %v, want %v", tt.status, tt.paymentMethod, got, tt.customer)
+		}
+		if got := transaction.IsCancellableBy(ActorRoleAdmin); got != tt.admin {
+			t.Errorf("%s/%s cancellable by admin = %v, want %v", tt.status, tt.paymentMethod, got, tt.admin)
+		}
+	}
+}
+
+func TestPaidTransactionCanBeRefunded(t *testing.T) {
+	for _, status := range []string{TransactionStatusPaid, TransactionStatusProcessing, TransactionStatusDelivered, TransactionStatusCompleted} {
+		transaction := Transaction{Status: status, PaymentMethod: "e_wallet"}
+		if err := transaction.CanTransitionTo(TransactionStatusRefunded); err != nil {
+			t.Errorf("%s cannot be refunded: %v", status, err)
+		}
+	}
+}
+
+func TestTransactionCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from          string
+		to            string
+		paymentMethod string
+		allowed       bool
+	}{
+		{TransactionStatusPending, TransactionStatusPaid, "e_wallet", true},
+		{TransactionStatusPending, TransactionStatusExpired, "e_wallet", true},
+		// Order non-COD harus dibayar sebelum diproses
+		{TransactionStatusPending, TransactionStatusProcessing, "e_wallet", false},
+		{TransactionStatusPending, TransactionStatusProcessing, PaymentMethodCOD, true},
+		{TransactionStatusPending, TransactionStatusShipped, PaymentMethodCOD, false},
+		{TransactionStatusAwaitingVerification, TransactionStatusPending, PaymentMethodBankTransfer, true},
+		{TransactionStatusPaid, TransactionStatusPending, "e_wallet", false},
+		{TransactionStatusShipped, TransactionStatusCancelled, PaymentMethodCOD, false},
+		{TransactionStatusShipped, TransactionStatusDelivered, PaymentMethodCOD, true},
+		// Status akhir tidak bisa berpindah lagi
+		{TransactionStatusCancelled, TransactionStatusPending, "e_wallet", false},
+		{TransactionStatusRefunded, TransactionStatusPaid, "e_wallet", false},
+		{TransactionStatusExpired, TransactionStatusPaid, "e_wallet", false},
+		{TransactionStatusPaid, "unknown", "e_wallet", false},
+	}
+
+	for _, tt := range tests {
+		transaction := Transaction{Status: tt.from, PaymentMethod: tt.paymentMethod}
+		err := transaction.CanTransitionTo(tt.to)
+		if got := err == nil; got != tt.allowed {
+			t.Errorf("%s -> %s (%s) allowed = %v, want %v (err: %v)", tt.from, tt.to, tt.paymentMethod, got, tt.allowed, err)
+		}
+	}
+}
+
+func TestTransactionIsPaymentOverdue(t *testing.T) {
+	now := time.Now()
+	window := time.Hour
+	later := now.Add(30 * time.Minute)
+	earlier := now.Add(-time.Minute)
+
+	tests := []struct {
+		name         string
+		createdAt    time.Time
+		paymentDueAt *time.Time
+		want         bool
+	}{
+		{name: "legacy order within window", createdAt: now.Add(-30 * time.Minute), want: false},
+		{name: "legacy order past window", createdAt: now.Add(-2 * time.Hour), want: true},
+		// Bukti pembayaran ditolak: order lama mendapat batas pembayaran baru
+		{name: "old order with restarted deadline", createdAt: now.Add(-3 * time.Hour), paymentDueAt: &later, want: false},
+		{name: "deadline passed", createdAt: now.Add(-10 * time.Minute), paymentDueAt: &earlier, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := Transaction{CreatedAt: tt.createdAt, PaymentDueAt: tt.paymentDueAt}
+			if got := transaction.IsPaymentOverdue(now, window); got != tt.want {
+				t.Errorf("overdue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionIsRefundInProgress(t *testing.T) {
+	now := time.Now()
+	staleAfter := 15 * time.Minute
+	recent := now.Add(-time.Minute)
+	stale := now.Add(-time.Hour)
+
+	tests := []struct {
+		name            string
+		refundStatus    string
+		refundStartedAt *time.Time
+		want            bool
+	}{
+		{name: "no refund", want: false},
+		{name: "recent pending refund", refundStatus: RefundStatusPending, refundStartedAt: &recent, want: true},
+		// Proses mati sebelum hasil refund dicatat: refund boleh diulang
+		{name: "stale pending refund", refundStatus: RefundStatusPending, refundStartedAt: &stale, want: false},
+		{name: "pending refund without start time", refundStatus: RefundStatusPending, want: false},
+		{name: "failed refund", refundStatus: RefundStatusFailed, refundStartedAt: &recent, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction := Transaction{RefundStatus: tt.refundStatus, RefundStartedAt: tt.refundStartedAt}
+			if got := transaction.IsRefundInProgress(now, staleAfter); got != tt.want {
+				t.Errorf("in progress = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatePaymentCanOnlyBeRefunded(t *testing.T) {
+	paidAt := time.Now()
+	for _, status := range []string{TransactionStatusCancelled, TransactionStatusFailed, TransactionStatusExpired} {
+		transaction := Transaction{Status: status, PaymentMethod: "e_wallet"}
+		if err := transaction.CanTransitionTo(TransactionStatusRefunded); err == nil {
+			t.Errorf("%s without late payment can be refunded", status)
+		}
+
+		transaction.LatePaymentAt = &paidAt
+		if err := transaction.CanTransitionTo(TransactionStatusRefunded); err != nil {
+			t.Errorf("%s with late payment cannot be refunded: %v", status, err)
+		}
+		if err := transaction.CanTransitionTo(TransactionStatusPaid); err == nil {
+			t.Errorf("%s with late payment can be marked paid", status)
+		}
+	}
+}
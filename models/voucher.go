@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Jenis voucher
+const (
+	VoucherTypePercentage   = "percentage"
+	VoucherTypeFixed        = "fixed"
+	VoucherTypeFreeShipping = "free_shipping"
+)
+
+// Voucher adalah kode diskon yang dikelola admin dan dipakai customer saat checkout.
+// Jika Categories atau Products diisi, diskon hanya dihitung dari item yang cocok.
+type Voucher struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	Code         string         `json:"code" gorm:"type:varchar(50);uniqueIndex;not null"`
+	Description  string         `json:"description" gorm:"type:text"`
+	Type         string         `json:"type" gorm:"type:varchar(20);not null"`
+	Value        float64        `json:"value" gorm:"type:decimal(15,2);not null;default:0"`
+	MinSpend     float64        `json:"min_spend" gorm:"type:decimal(15,2);not null;default:0"`
+	MaxDiscount  *float64       `json:"max_discount" gorm:"type:decimal(15,2)"`
+	UsageLimit   *int           `json:"usage_limit"`
+	PerUserLimit *int           `json:"per_user_limit"`
+	UsedCount    int            `json:"used_count" gorm:"not null;default:0"`
+	StartsAt     *time.Time     `json:"starts_at"`
+	EndsAt       *time.Time     `json:"ends_at"`
+	IsActive     bool           `json:"is_active" gorm:"not null"`
+	Categories   []Category     `json:"categories" gorm:"many2many:voucher_categories"`
+	Products     []Product      `json:"products" gorm:"many2many:voucher_products"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// VoucherRedemption mencatat pemakaian voucher pada satu transaksi. ReleasedAt diisi
+// saat order batal, gagal, atau kedaluwarsa sehingga kuota voucher kembali.
+type VoucherRedemption struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	VoucherID     uint       `json:"voucher_id" gorm:"not null;index:idx_voucher_redemptions_voucher_user"`
+	UserID        uint       `json:"user_id" gorm:"not null;index:idx_voucher_redemptions_voucher_user"`
+	TransactionID uint       `json:"transaction_id" gorm:"not null;uniqueIndex"`
+	Discount      float64    `json:"discount" gorm:"type:decimal(15,2);not null"`
+	ReleasedAt    *time.Time `json:"released_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TableName returns the table name for Voucher
+func (Voucher) TableName() string {
+	return "vouchers"
+}
+
+// TableName returns the table name for VoucherRedemption
+func (VoucherRedemption) TableName() string {
+	return "voucher_redemptions"
+}
+
+// IsRestricted mengecek apakah voucher hanya berlaku untuk category atau product tertentu
+func (v Voucher) IsRestricted() bool {
+	return len(v.Categories) > 0 || len(v.Products) > 0
+}
+
+// IsExhausted mengecek apakah kuota pemakaian global voucher sudah habis
+func (v Voucher) IsExhausted() bool {
+	return v.UsageLimit != nil && v.UsedCount >= *v.UsageLimit
+}
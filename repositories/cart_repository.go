@@ -16,19 +16,26 @@ func NewCartRepository(db *gorm.DB) *CartRepository {
 	}
 }
 
+// WithTx mengembalikan CartRepository yang memakai transaction handle tx
+func (r *CartRepository) WithTx(tx *gorm.DB) *CartRepository {
+	return &CartRepository{db: tx}
+}
+
 func (r *CartRepository) Create(cart *models.Cart) error {
 	return r.db.Create(cart).Error
 }
 
 func (r *CartRepository) GetByUserID(userID uint) ([]models.Cart, error) {
 	var carts []models.Cart
-	err := r.db.Preload("Product").Preload("User").Where("user_id = ?", userID).Find(&carts).Error
+	err := r.db.Preload("Product").Preload("Variant").Preload("User").Where("user_id = ?", userID).Find(&carts).Error
 	return carts, err
 }
 
-func (r *CartRepository) GetByUserIDAndProductID(userID, productID uint) (*models.Cart, error) {
+// GetByUserIDAndProductID mengambil item cart user untuk product dan variant tertentu (variantID nil untuk product tanpa variant)
+func (r *CartRepository) GetByUserIDAndProductID(userID, productID uint, variantID *uint) (*models.Cart, error) {
 	var cart models.Cart
-	err := r.db.Preload("Product").Preload("User").Where("user_id = ? AND product_id = ?", userID, productID).First(&cart).Error
+	query := r.db.Preload("Product").Preload("Variant").Preload("User").Where("user_id = ? AND product_id = ?", userID, productID)
+	err := whereVariant(query, variantID).First(&cart).Error
 	if err != nil {
 		return nil, err
 	}
@@ -43,8 +50,19 @@ func (r *CartRepository) Delete(cartID uint) error {
 	return r.db.Delete(&models.Cart{}, cartID).Error
 }
 
-func (r *CartRepository) DeleteByUserIDAndProductID(userID, productID uint) error {
-	return r.db.Where("user_id = ? AND product_id = ?", userID, productID).Delete(&models.Cart{}).Error
+func (r *CartRepository) DeleteByUserIDAndProductID(userID, productID uint, variantID *uint) error {
+	query := r.db.Where("user_id = ? AND product_id = ?", userID, productID)
+	return whereVariant(query, variantID).Delete(&models.Cart{}).Error
+}
+
+// DeleteByVariantID menghapus item cart semua user yang mereferensikan variant
+func (r *CartRepository) DeleteByVariantID(variantID uint) error {
+	return r.db.Where("variant_id = ?", variantID).Delete(&models.Cart{}).Error
+}
+
+// DeleteByProductID menghapus item cart semua user yang mereferensikan product
+func (r *CartRepository) DeleteByProductID(productID uint) error {
+	return r.db.Where("product_id = ?", productID).Delete(&models.Cart{}).Error
 }
 
 func (r *CartRepository) ClearCart(userID uint) error {
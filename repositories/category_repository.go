@@ -6,6 +6,7 @@ import (
 	"tokogo/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CategoryRepository struct {
@@ -19,6 +20,11 @@ func NewCategoryRepository() *CategoryRepository {
 	}
 }
 
+// WithTx mengembalikan CategoryRepository yang memakai transaction handle tx
+func (r *CategoryRepository) WithTx(tx *gorm.DB) *CategoryRepository {
+	return &CategoryRepository{db: tx}
+}
+
 // CreateCategory menyimpan category baru ke database
 func (r *CategoryRepository) CreateCategory(category *models.Category) error {
 	return r.db.Create(category).Error
@@ -39,6 +45,34 @@ func (r *CategoryRepository) GetCategoryByID(id uint) (*models.Category, error)
 	return &category, nil
 }
 
+// GetCategoryByIDForUpdate mengambil category dan mengunci row-nya (SELECT ... FOR UPDATE)
+// sehingga product dan sub category baru tidak bisa memakainya sampai transaction selesai.
+// Hanya bermakna bila dipanggil di dalam transaction.
+func (r *CategoryRepository) GetCategoryByIDForUpdate(id uint) (*models.Category, error) {
+	return r.getCategoryByIDLocked(id, "UPDATE")
+}
+
+// GetCategoryByIDForShare mengambil category dengan shared lock (SELECT ... FOR SHARE) sehingga
+// category tidak bisa dihapus selama transaction yang memakainya berjalan, dan penghapusan yang
+// sedang berjalan ditunggu sampai selesai. Hanya bermakna bila dipanggil di dalam transaction.
+func (r *CategoryRepository) GetCategoryByIDForShare(id uint) (*models.Category, error) {
+	return r.getCategoryByIDLocked(id, "SHARE")
+}
+
+func (r *CategoryRepository) getCategoryByIDLocked(id uint, strength string) (*models.Category, error) {
+	var category models.Category
+	err := r.db.Clauses(clause.Locking{Strength: strength}).Where("id = ?", id).First(&category).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+
+	return &category, nil
+}
+
 // GetCategoryBySlug mengambil category berdasarkan slug
 func (r *CategoryRepository) GetCategoryBySlug(slug string) (*models.Category, error) {
 	var category models.Category
@@ -71,9 +105,178 @@ func (r *CategoryRepository) GetAllCategories(page, limit int) ([]models.Categor
 	return categories, total, err
 }
 
-// UpdateCategory mengupdate category berdasarkan ID
+// categoryKeyset mengurutkan category dari yang terbaru untuk listing berbasis cursor
+var categoryKeyset = keyset{name: "created_at_desc", column: "categories.created_at", idColumn: "categories.id", desc: true}
+
+// GetAllCategoriesByCursor mengambil categories setelah posisi cursor tanpa OFFSET dan COUNT
+func (r *CategoryRepository) GetAllCategoriesByCursor(cursor string, limit int) ([]models.Category, string, error) {
+	var categories []models.Category
+
+	query, err := categoryKeyset.apply(r.db.Model(&models.Category{}), cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := query.Find(&categories).Error; err != nil {
+		return nil, "", err
+	}
+
+	categories, next := nextCursor(categoryKeyset, categories, limit, func(c models.Category) (interface{}, uint) {
+		return c.CreatedAt, c.ID
+	})
+	return categories, next, nil
+}
+
+// UpdateCategory mengupdate category berdasarkan ID. parent_id ikut disimpan walaupun nil
+// agar category bisa dipindah kembali ke root.
 func (r *CategoryRepository) UpdateCategory(id uint, category *models.Category) error {
-	return r.db.Where("id = ?", id).Updates(category).Error
+	return r.db.Where("id = ?", id).Select("name", "slug", "parent_id").Updates(category).Error
+}
+
+// CountProducts menghitung product yang masih memakai category
+func (r *CategoryRepository) CountProducts(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Product{}).Where("category_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// CountChildren menghitung sub category langsung dari category
+func (r *CategoryRepository) CountChildren(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// ReassignProducts memindahkan semua product dari satu category ke category lain,
+// termasuk product yang diarsipkan agar tetap punya category saat di-restore
+func (r *CategoryRepository) ReassignProducts(fromID, toID uint) error {
+	return r.db.Unscoped().Model(&models.Product{}).Where("category_id = ?", fromID).Update("category_id", toID).Error
+}
+
+// GetDeletedCategoryByID mengambil category yang sudah di-soft delete
+func (r *CategoryRepository) GetDeletedCategoryByID(id uint) (*models.Category, error) {
+	var category models.Category
+	err := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&category).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("deleted category not found")
+		}
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// RestoreCategory mengembalikan category yang sudah di-soft delete beserta parent dan slug-nya
+func (r *CategoryRepository) RestoreCategory(id uint, parentID *uint, slug string) error {
+	return r.db.Unscoped().Model(&models.Category{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "parent_id": parentID, "slug": slug}).Error
+}
+
+// GetAllCategoriesForTree mengambil semua category untuk disusun menjadi tree
+func (r *CategoryRepository) GetAllCategoriesForTree() ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Order("name ASC, id ASC").Find(&categories).Error
+	return categories, err
+}
+
+// GetDescendantIDs mengambil id category beserta seluruh turunannya
+func (r *CategoryRepository) GetDescendantIDs(id uint) ([]uint, error) {
+	ids := []uint{id}
+	visited := map[uint]bool{id: true}
+	level := []uint{id}
+
+	for len(level) > 0 {
+		var children []uint
+		if err := r.db.Model(&models.Category{}).Where("parent_id IN ?", level).Pluck("id", &children).Error; err != nil {
+			return nil, err
+		}
+
+		level = nil
+		for _, child := range children {
+			if visited[child] {
+				continue
+			}
+			visited[child] = true
+			ids = append(ids, child)
+			level = append(level, child)
+		}
+	}
+
+	return ids, nil
+}
+
+// IsDescendantOf mengecek apakah category candidateID berada di bawah category ancestorID
+// (atau merupakan category itu sendiri). Dipakai untuk mencegah siklus saat memindah parent.
+func (r *CategoryRepository) IsDescendantOf(candidateID, ancestorID uint) (bool, error) {
+	visited := map[uint]bool{}
+	current := &candidateID
+
+	for current != nil {
+		if *current == ancestorID {
+			return true, nil
+		}
+		if visited[*current] {
+			return false, nil
+		}
+		visited[*current] = true
+
+		var category models.Category
+		if err := r.db.Select("id", "parent_id").Where("id = ?", *current).First(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+		current = category.ParentID
+	}
+
+	return false, nil
+}
+
+// loadCategoryAncestors mengisi rantai Parent setiap category sampai root.
+// Parent diambil per level sehingga jumlah query sebanding dengan kedalaman tree.
+func loadCategoryAncestors(db *gorm.DB, categories []*models.Category) error {
+	loaded := make(map[uint]*models.Category)
+	pending := categories
+
+	for len(pending) > 0 {
+		var parentIDs []uint
+		for _, category := range pending {
+			if category.ParentID == nil {
+				continue
+			}
+			if _, ok := loaded[*category.ParentID]; !ok {
+				parentIDs = append(parentIDs, *category.ParentID)
+			}
+		}
+
+		var parents []models.Category
+		if len(parentIDs) > 0 {
+			if err := db.Session(&gorm.Session{NewDB: true}).Where("id IN ?", parentIDs).Find(&parents).Error; err != nil {
+				return err
+			}
+		}
+
+		var next []*models.Category
+		for i := range parents {
+			parent := &parents[i]
+			if _, ok := loaded[parent.ID]; ok {
+				continue
+			}
+			loaded[parent.ID] = parent
+			next = append(next, parent)
+		}
+
+		for _, category := range pending {
+			if category.ParentID != nil {
+				category.Parent = loaded[*category.ParentID]
+			}
+		}
+		pending = next
+	}
+
+	return nil
 }
 
 // DeleteCategory menghapus category berdasarkan ID (soft delete)
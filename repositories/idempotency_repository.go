@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"time"
+	"tokogo/models"
+
+	"gorm.io/gorm"
+)
+
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository membuat instance baru IdempotencyRepository
+func NewIdempotencyRepository(db *gorm.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db: db,
+	}
+}
+
+// Create menyimpan idempotency key baru. Gagal bila key yang sama sudah ada untuk user tersebut.
+func (r *IdempotencyRepository) Create(key *models.IdempotencyKey) error {
+	return r.db.Create(key).Error
+}
+
+// GetByUserIDAndKey mengambil idempotency key milik user
+func (r *IdempotencyRepository) GetByUserIDAndKey(userID uint, key string) (*models.IdempotencyKey, error) {
+	var idempotencyKey models.IdempotencyKey
+	err := r.db.Where("user_id = ? AND `key` = ?", userID, key).First(&idempotencyKey).Error
+	if err != nil {
+		return nil, err
+	}
+	return &idempotencyKey, nil
+}
+
+// SaveResponse menyimpan response yang dihasilkan untuk idempotency key
+func (r *IdempotencyRepository) SaveResponse(id uint, status int, body string) error {
+	return r.db.Model(&models.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"response_status": status,
+		"response_body":   body,
+	}).Error
+}
+
+// Delete menghapus idempotency key (dipakai bila request gagal sehingga boleh diulang)
+func (r *IdempotencyRepository) Delete(id uint) error {
+	return r.db.Delete(&models.IdempotencyKey{}, id).Error
+}
+
+// DeleteExpired menghapus idempotency key yang sudah kedaluwarsa
+func (r *IdempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidCursor dikembalikan jika token cursor rusak atau dibuat untuk urutan yang berbeda
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorToken adalah isi token next_cursor. Token di-encode base64 agar tetap opaque bagi client.
+type cursorToken struct {
+	Sort  string      `json:"s"`
+	Time  *time.Time  `json:"t,omitempty"`
+	Value interface{} `json:"v,omitempty"`
+	ID    uint        `json:"id"`
+}
+
+// keyset mendefinisikan kolom urutan yang dipakai listing berbasis cursor.
+// Baris dengan nilai kolom yang sama diurutkan lagi berdasarkan id agar posisi cursor selalu unik.
+// column boleh berupa ekspresi dengan placeholder, nilainya diisi dari args.
+type keyset struct {
+	name     string
+	column   string
+	args     []interface{}
+	idColumn string
+	desc     bool
+}
+
+// apply menambahkan urutan, kondisi setelah cursor, dan limit+1 untuk mendeteksi halaman berikutnya
+func (k keyset) apply(db *gorm.DB, cursor string, limit int) (*gorm.DB, error) {
+	direction, operator := "ASC", ">"
+	if k.desc {
+		direction, operator = "DESC", "<"
+	}
+
+	if cursor != "" {
+		token, err := k.decode(cursor)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{} = token.Value
+		if token.Time != nil {
+			value = *token.Time
+		}
+		vars := append(append(append(append([]interface{}{}, k.args...), value), k.args...), value, token.ID)
+		db = db.Where(
+			fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", k.column, operator, k.column, k.idColumn, operator),
+			vars...,
+		)
+	}
+
+	return k.order(db, direction).Limit(limit + 1), nil
+}
+
+// order menambahkan ORDER BY kolom urutan lalu id dengan arah yang sama
+func (k keyset) order(db *gorm.DB, direction string) *gorm.DB {
+	return db.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:                fmt.Sprintf("%s %s, %s %s", k.column, direction, k.idColumn, direction),
+		Vars:               k.args,
+		WithoutParentheses: true,
+	}})
+}
+
+// encode membuat token cursor dari nilai kolom urutan dan id baris terakhir
+func (k keyset) encode(value interface{}, id uint) string {
+	token := cursorToken{Sort: k.name, ID: id}
+	if t, ok := value.(time.Time); ok {
+		token.Time = &t
+	} else {
+		token.Value = value
+	}
+
+	payload, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func (k keyset) decode(cursor string) (*cursorToken, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var token cursorToken
+	if err := json.Unmarshal(payload, &token); err != nil || token.Sort != k.name || token.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	if token.Time == nil && token.Value == nil {
+		return nil, ErrInvalidCursor
+	}
+	return &token, nil
+}
+
+// nextCursor memotong baris tambahan dari hasil query dan membuat token untuk halaman berikutnya.
+// Token kosong berarti tidak ada halaman berikutnya.
+func nextCursor[T any](k keyset, rows []T, limit int, position func(T) (interface{}, uint)) ([]T, string) {
+	if len(rows) <= limit {
+		return rows, ""
+	}
+
+	rows = rows[:limit]
+	value, id := position(rows[len(rows)-1])
+	return rows, k.encode(value, id)
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"tokogo/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentNotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentNotificationRepository membuat instance baru PaymentNotificationRepository
+func NewPaymentNotificationRepository(db *gorm.DB) *PaymentNotificationRepository {
+	return &PaymentNotificationRepository{
+		db: db,
+	}
+}
+
+// WithTx mengembalikan PaymentNotificationRepository yang memakai transaction handle tx
+func (r *PaymentNotificationRepository) WithTx(tx *gorm.DB) *PaymentNotificationRepository {
+	return &PaymentNotificationRepository{db: tx}
+}
+
+// Create menyimpan notifikasi payment gateway
+func (r *PaymentNotificationRepository) Create(notification *models.PaymentNotification) error {
+	return r.db.Create(notification).Error
+}
+
+// Update mengupdate hasil pemrosesan notifikasi
+func (r *PaymentNotificationRepository) Update(notification *models.PaymentNotification) error {
+	return r.db.Save(notification).Error
+}
+
+// MarkEventProcessed mencatat event gateway sebagai sudah diproses dan mengembalikan false bila
+// event tersebut sudah pernah dicatat. Harus dipanggil di dalam transaction yang memproses event,
+// insert paralel dengan event yang sama menunggu transaction pertama selesai karena unique index.
+func (r *PaymentNotificationRepository) MarkEventProcessed(gateway, eventID string, transactionID uint) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ProcessedPaymentEvent{
+		Gateway:       gateway,
+		EventID:       eventID,
+		TransactionID: transactionID,
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
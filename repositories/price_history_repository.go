@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"tokogo/models"
+
+	"gorm.io/gorm"
+)
+
+type PriceHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewPriceHistoryRepository membuat instance baru PriceHistoryRepository
+func NewPriceHistoryRepository(db *gorm.DB) *PriceHistoryRepository {
+	return &PriceHistoryRepository{
+		db: db,
+	}
+}
+
+// WithTx mengembalikan PriceHistoryRepository yang memakai transaction handle tx
+func (r *PriceHistoryRepository) WithTx(tx *gorm.DB) *PriceHistoryRepository {
+	return &PriceHistoryRepository{db: tx}
+}
+
+// Create menambahkan satu catatan harga. Riwayat harga tidak pernah diupdate atau dihapus.
+func (r *PriceHistoryRepository) Create(history *models.PriceHistory) error {
+	return r.db.Create(history).Error
+}
+
+// GetByProductID mengambil riwayat harga product dengan pagination, terbaru lebih dulu
+func (r *PriceHistoryRepository) GetByProductID(productID uint, page, limit int) ([]models.PriceHistory, int64, error) {
+	var histories []models.PriceHistory
+	var total int64
+
+	query := r.db.Model(&models.PriceHistory{}).Where("product_id = ?", productID)
+
+	// Count total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&histories).Error
+
+	return histories, total, err
+}
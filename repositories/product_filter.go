@@ -0,0 +1,246 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"tokogo/models"
+
+	"gorm.io/gorm"
+)
+
+// Pilihan urutan listing product
+const (
+	ProductSortNewest      = "newest"
+	ProductSortPriceAsc    = "price_asc"
+	ProductSortPriceDesc   = "price_desc"
+	ProductSortBestSelling = "best_selling"
+	ProductSortNameAsc     = "name_asc"
+	ProductSortNameDesc    = "name_desc"
+)
+
+// ProductPriceBuckets adalah batas bawah setiap rentang harga pada facet harga.
+// Bucket terakhir tidak memiliki batas atas.
+var ProductPriceBuckets = []float64{0, 50000, 100000, 250000, 500000, 1000000}
+
+// ProductFilter berisi filter dan urutan untuk listing product. Nilai kosong berarti tanpa filter.
+type ProductFilter struct {
+	MinPrice    *float64
+	MaxPrice    *float64
+	InStock     bool
+	CategoryIDs []uint
+	Sort        string
+	// Status menyaring status publikasi, dipakai di listing admin
+	Status string
+	// VisibleAt membatasi hasil ke product yang tampil di storefront pada waktu tersebut
+	VisibleAt *time.Time
+}
+
+// CategoryFacet adalah jumlah product per category
+type CategoryFacet struct {
+	CategoryID   uint
+	CategoryName string
+	Count        int64
+}
+
+// PriceFacet adalah jumlah product dalam satu rentang harga. Max nil berarti tanpa batas atas.
+type PriceFacet struct {
+	Min   float64
+	Max   *float64
+	Count int64
+}
+
+// ProductFacets adalah ringkasan facet untuk sidebar filter storefront
+type ProductFacets struct {
+	Categories  []CategoryFacet
+	PriceRanges []PriceFacet
+}
+
+// apply menambahkan kondisi filter ke query product
+func (f ProductFilter) apply(db *gorm.DB) *gorm.DB {
+	db = f.applyVisibility(db)
+	db = f.applyPrice(db)
+	db = f.applyCategories(db)
+	return f.applyStock(db)
+}
+
+// applyVisibility menyaring status publikasi dan jadwal tayang. Berlaku juga untuk facet.
+func (f ProductFilter) applyVisibility(db *gorm.DB) *gorm.DB {
+	if f.Status != "" {
+		db = db.Where("products.status = ?", f.Status)
+	}
+	if f.VisibleAt != nil {
+		db = db.Where("products.status = ?", models.ProductStatusPublished).
+			Where("products.publish_at IS NULL OR products.publish_at <= ?", *f.VisibleAt).
+			Where("products.unpublish_at IS NULL OR products.unpublish_at > ?", *f.VisibleAt)
+	}
+	return db
+}
+
+// applyPrice menyaring rentang harga berdasarkan harga yang berlaku (lihat priceColumn)
+func (f ProductFilter) applyPrice(db *gorm.DB) *gorm.DB {
+	column, args := f.priceColumn()
+	if f.MinPrice != nil {
+		db = db.Where(column+" >= ?", append(append([]interface{}{}, args...), *f.MinPrice)...)
+	}
+	if f.MaxPrice != nil {
+		db = db.Where(column+" <= ?", append(append([]interface{}{}, args...), *f.MaxPrice)...)
+	}
+	return db
+}
+
+// priceColumn mengembalikan ekspresi harga untuk filter, urutan, dan facet. Di storefront
+// harga sale yang sedang aktif pada VisibleAt dipakai, sama seperti Product.EffectivePrice.
+func (f ProductFilter) priceColumn() (string, []interface{}) {
+	if f.VisibleAt == nil {
+		return "products.selling_price", nil
+	}
+	return "(CASE WHEN products.sale_price IS NOT NULL AND products.sale_price < products.selling_price" +
+			" AND (products.sale_starts_at IS NULL OR products.sale_starts_at <= ?)" +
+			" AND (products.sale_ends_at IS NULL OR products.sale_ends_at > ?)" +
+			" THEN products.sale_price ELSE products.selling_price END)",
+		[]interface{}{*f.VisibleAt, *f.VisibleAt}
+}
+
+func (f ProductFilter) applyCategories(db *gorm.DB) *gorm.DB {
+	if len(f.CategoryIDs) > 0 {
+		db = db.Where("products.category_id IN ?", f.CategoryIDs)
+	}
+	return db
+}
+
+// applyStock menyaring product yang masih bisa dibeli, termasuk product yang hanya punya stok di variant
+func (f ProductFilter) applyStock(db *gorm.DB) *gorm.DB {
+	if !f.InStock {
+		return db
+	}
+	return db.Where("products.stock > 0 OR EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND pv.stock > 0 AND pv.deleted_at IS NULL)")
+}
+
+// applySort menambahkan urutan listing. Default-nya product terbaru lebih dulu.
+func (f ProductFilter) applySort(db *gorm.DB) *gorm.DB {
+	k := f.keyset()
+	direction := "ASC"
+	if k.desc {
+		direction = "DESC"
+	}
+	return k.order(f.joinSales(db), direction)
+}
+
+// keyset mengembalikan kolom urutan sesuai pilihan sort, dipakai juga untuk listing berbasis cursor
+func (f ProductFilter) keyset() keyset {
+	switch f.Sort {
+	case ProductSortPriceAsc:
+		column, args := f.priceColumn()
+		return keyset{name: f.Sort, column: column, args: args, idColumn: "products.id"}
+	case ProductSortPriceDesc:
+		column, args := f.priceColumn()
+		return keyset{name: f.Sort, column: column, args: args, idColumn: "products.id", desc: true}
+	case ProductSortNameAsc:
+		return keyset{name: f.Sort, column: "products.name", idColumn: "products.id"}
+	case ProductSortNameDesc:
+		return keyset{name: f.Sort, column: "products.name", idColumn: "products.id", desc: true}
+	case ProductSortBestSelling:
+		return keyset{name: f.Sort, column: "COALESCE(sales.sold, 0)", idColumn: "products.id", desc: true}
+	default:
+		return keyset{name: ProductSortNewest, column: "products.created_at", idColumn: "products.id", desc: true}
+	}
+}
+
+// position mengembalikan nilai kolom urutan sebuah product untuk token cursor
+func (f ProductFilter) position(product models.Product, sold int64) (interface{}, uint) {
+	switch f.Sort {
+	case ProductSortPriceAsc, ProductSortPriceDesc:
+		if f.VisibleAt != nil {
+			return product.EffectivePrice(*f.VisibleAt), product.ID
+		}
+		return product.SellingPrice, product.ID
+	case ProductSortNameAsc, ProductSortNameDesc:
+		return product.Name, product.ID
+	case ProductSortBestSelling:
+		return sold, product.ID
+	default:
+		return product.CreatedAt, product.ID
+	}
+}
+
+// joinSales menambahkan jumlah penjualan per product jika urutan best_selling dipakai
+func (f ProductFilter) joinSales(db *gorm.DB) *gorm.DB {
+	if f.Sort != ProductSortBestSelling {
+		return db
+	}
+	return db.Joins("LEFT JOIN (?) AS sales ON sales.product_id = products.id", salesQuery(db.Session(&gorm.Session{NewDB: true})))
+}
+
+// salesQuery menghitung jumlah terjual per product dari order yang tidak batal, gagal, kedaluwarsa, atau di-refund
+func salesQuery(db *gorm.DB) *gorm.DB {
+	return db.Table("transaction_details").
+		Select("transaction_details.product_id, SUM(transaction_details.quantity) AS sold").
+		Joins("JOIN transactions ON transactions.id = transaction_details.transaction_id").
+		Where("transactions.status NOT IN ?", []string{
+			models.TransactionStatusCancelled,
+			models.TransactionStatusFailed,
+			models.TransactionStatusExpired,
+			models.TransactionStatusRefunded,
+		}).
+		Group("transaction_details.product_id")
+}
+
+// GetFacets menghitung facet category dan harga. Setiap facet mengabaikan filternya
+// sendiri agar storefront tetap bisa menampilkan pilihan lain pada dimensi yang sama.
+func (r *ProductRepository) GetFacets(filter ProductFilter) (*ProductFacets, error) {
+	facets := &ProductFacets{}
+
+	categoryQuery := r.db.Model(&models.Product{}).
+		Select("products.category_id, categories.name AS category_name, COUNT(*) AS count").
+		Joins("JOIN categories ON categories.id = products.category_id").
+		Group("products.category_id, categories.name").
+		Order("categories.name ASC")
+	categoryQuery = filter.applyStock(filter.applyPrice(filter.applyVisibility(categoryQuery)))
+	if err := categoryQuery.Scan(&facets.Categories).Error; err != nil {
+		return nil, err
+	}
+
+	bucketExpr, bucketArgs := priceBucketExpression(filter.priceColumn())
+	var bucketCounts []struct {
+		Bucket int
+		Count  int64
+	}
+	priceQuery := r.db.Model(&models.Product{}).
+		Select(bucketExpr+" AS bucket, COUNT(*) AS count", bucketArgs...).
+		Group("bucket")
+	priceQuery = filter.applyStock(filter.applyCategories(filter.applyVisibility(priceQuery)))
+	if err := priceQuery.Scan(&bucketCounts).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int64, len(bucketCounts))
+	for _, bucket := range bucketCounts {
+		counts[bucket.Bucket] = bucket.Count
+	}
+	for i, min := range ProductPriceBuckets {
+		facet := PriceFacet{Min: min, Count: counts[i]}
+		if i+1 < len(ProductPriceBuckets) {
+			max := ProductPriceBuckets[i+1]
+			facet.Max = &max
+		}
+		facets.PriceRanges = append(facets.PriceRanges, facet)
+	}
+
+	return facets, nil
+}
+
+// priceBucketExpression membentuk CASE yang mengembalikan index bucket dari ekspresi harga column
+func priceBucketExpression(column string, columnArgs []interface{}) (string, []interface{}) {
+	var builder strings.Builder
+	var args []interface{}
+
+	builder.WriteString("CASE")
+	for i := len(ProductPriceBuckets) - 1; i > 0; i-- {
+		fmt.Fprintf(&builder, " WHEN %s >= ? THEN %d", column, i)
+		args = append(append(args, columnArgs...), ProductPriceBuckets[i])
+	}
+	builder.WriteString(" ELSE 0 END")
+
+	return builder.String(), args
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"tokogo/models"
+
+	"gorm.io/gorm"
+)
+
+type ProductImageRepository struct {
+	db *gorm.DB
+}
+
+// NewProductImageRepository membuat instance baru ProductImageRepository
+func NewProductImageRepository(db *gorm.DB) *ProductImageRepository {
+	return &ProductImageRepository{
+		db: db,
+	}
+}
+
+// WithTx mengembalikan ProductImageRepository yang memakai transaction handle tx
+func (r *ProductImageRepository) WithTx(tx *gorm.DB) *ProductImageRepository {
+	return &ProductImageRepository{db: tx}
+}
+
+// Create menyimpan gambar baru
+func (r *ProductImageRepository) Create(image *models.ProductImage) error {
+	return r.db.Create(image).Error
+}
+
+// GetByProductID mengambil galeri product sesuai urutan posisi
+func (r *ProductImageRepository) GetByProductID(productID uint) ([]models.ProductImage, error) {
+	var images []models.ProductImage
+	err := r.db.Where("product_id = ?", productID).Order("position ASC, id ASC").Find(&images).Error
+	return images, err
+}
+
+// GetByIDAndProductID mengambil gambar berdasarkan ID yang harus milik product tertentu
+func (r *ProductImageRepository) GetByIDAndProductID(id, productID uint) (*models.ProductImage, error) {
+	var image models.ProductImage
+	err := r.db.Where("id = ? AND product_id = ?", id, productID).First(&image).Error
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// UpdatePosition mengupdate posisi satu gambar
+func (r *ProductImageRepository) UpdatePosition(id uint, position int) error {
+	return r.db.Model(&models.ProductImage{}).Where("id = ?", id).Update("position", position).Error
+}
+
+// SetPrimary menjadikan satu gambar sebagai primary dan mencabut status primary gambar lain
+func (r *ProductImageRepository) SetPrimary(productID, imageID uint) error {
+	if err := r.db.Model(&models.ProductImage{}).
+		Where("product_id = ? AND id <> ?", productID, imageID).
+		Update("is_primary", false).Error; err != nil {
+		return err
+	}
+
+	return r.db.Model(&models.ProductImage{}).
+		Where("id = ? AND product_id = ?", imageID, productID).
+		Update("is_primary", true).Error
+}
+
+// Delete menghapus gambar dari galeri
+func (r *ProductImageRepository) Delete(id uint) error {
+	return r.db.Delete(&models.ProductImage{}, id).Error
+}
@@ -1,74 +1,315 @@
 package repositories
 
 import (
+	"errors"
 	"tokogo/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrInsufficientStock dikembalikan ketika stok product tidak cukup untuk dikurangi
+var ErrInsufficientStock = errors.New("insufficient stock")
+
 type ProductRepository struct {
-	db *gorm.DB
+	db       *gorm.DB
+	searcher ProductSearcher
 }
 
 func NewProductRepository(db *gorm.DB) *ProductRepository {
-	return &ProductRepository{db: db}
+	return &ProductRepository{db: db, searcher: DefaultProductSearcher()}
+}
+
+// WithTx mengembalikan ProductRepository yang memakai transaction handle tx
+func (r *ProductRepository) WithTx(tx *gorm.DB) *ProductRepository {
+	return &ProductRepository{db: tx, searcher: r.searcher}
 }
 
 func (r *ProductRepository) Create(product *models.Product) error {
 	return r.db.Create(product).Error
 }
 
-func (r *ProductRepository) GetAll(page, limit int) ([]models.Product, int64, error) {
+// GetAll mengambil product dengan pagination, filter, dan urutan
+func (r *ProductRepository) GetAll(page, limit int, filter ProductFilter) ([]models.Product, int64, error) {
 	var products []models.Product
 	var total int64
 
 	offset := (page - 1) * limit
 
 	// Count total records
-	if err := r.db.Model(&models.Product{}).Count(&total).Error; err != nil {
+	if err := filter.apply(r.db.Model(&models.Product{})).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get products with pagination
-	err := r.db.Preload("Category").
+	query := filter.applySort(filter.apply(r.db.Model(&models.Product{})))
+	err := query.Select("products.*").
+		Preload("Category").Preload("Variants").Preload("Images", orderImagesByPosition).
+		Offset(offset).
+		Limit(limit).
+		Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, r.loadBreadcrumbs(products)
+}
+
+// GetAllByCursor mengambil product setelah posisi cursor tanpa OFFSET dan COUNT.
+// Cursor hanya berlaku untuk urutan yang sama dengan saat cursor dibuat.
+func (r *ProductRepository) GetAllByCursor(cursor string, limit int, filter ProductFilter) ([]models.Product, string, error) {
+	var products []models.Product
+
+	k := filter.keyset()
+	query, err := k.apply(filter.joinSales(filter.apply(r.db.Model(&models.Product{}))), cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	err = query.Select("products.*").
+		Preload("Category").Preload("Variants").Preload("Images", orderImagesByPosition).
+		Find(&products).Error
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Jumlah terjual tidak ada di model, jadi diambil terpisah untuk baris terakhir halaman
+	var sold int64
+	if filter.Sort == ProductSortBestSelling && len(products) > limit {
+		err := salesQuery(r.db.Session(&gorm.Session{NewDB: true})).
+			Where("transaction_details.product_id = ?", products[limit-1].ID).
+			Select("COALESCE(SUM(transaction_details.quantity), 0)").
+			Scan(&sold).Error
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	products, next := nextCursor(k, products, limit, func(p models.Product) (interface{}, uint) {
+		return filter.position(p, sold)
+	})
+	return products, next, r.loadBreadcrumbs(products)
+}
+
+// lowestVariantStock adalah stok terkecil dari variant aktif milik product, NULL bila product tidak punya variant
+const lowestVariantStock = "(SELECT MIN(product_variants.stock) FROM product_variants WHERE product_variants.product_id = products.id AND product_variants.deleted_at IS NULL)"
+
+// GetLowStock mengambil product dengan stok di bawah atau sama dengan low stock threshold.
+// Product dengan variant dibandingkan memakai stok variant-nya, bukan stok product.
+func (r *ProductRepository) GetLowStock(page, limit int) ([]models.Product, int64, error) {
+	var products []models.Product
+	var total int64
+
+	offset := (page - 1) * limit
+	query := r.db.Model(&models.Product{}).Where("COALESCE(" + lowestVariantStock + ", products.stock) <= products.low_stock_threshold")
+
+	// Count total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Paling kritis lebih dulu
+	err := query.Preload("Category").Preload("Variants").Preload("Images", orderImagesByPosition).
+		Order("COALESCE(" + lowestVariantStock + ", products.stock) - products.low_stock_threshold ASC, products.id ASC").
 		Offset(offset).
 		Limit(limit).
 		Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
 
-	return products, total, err
+	return products, total, r.loadBreadcrumbs(products)
 }
 
 func (r *ProductRepository) GetByID(id uint) (*models.Product, error) {
 	var product models.Product
-	err := r.db.Preload("Category").First(&product, id).Error
+	err := r.db.Preload("Category").Preload("Variants").Preload("Images", orderImagesByPosition).First(&product, id).Error
+	if err != nil {
+		return &product, err
+	}
+	return &product, loadCategoryAncestors(r.db, []*models.Category{&product.Category})
+}
+
+// GetBySlug mengambil product berdasarkan slug
+func (r *ProductRepository) GetBySlug(slug string) (*models.Product, error) {
+	var product models.Product
+	err := r.db.Preload("Category").Preload("Variants").Preload("Images", orderImagesByPosition).
+		Where("slug = ?", slug).
+		First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, loadCategoryAncestors(r.db, []*models.Category{&product.Category})
+}
+
+// GetWithoutSlug mengambil product lama yang belum memiliki slug
+func (r *ProductRepository) GetWithoutSlug(limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Where("slug IS NULL OR slug = ''").Order("id ASC").Limit(limit).Find(&products).Error
+	return products, err
+}
+
+// GetWithoutGallery mengambil product lama, termasuk yang diarsipkan, yang hanya memiliki ImageURL tanpa galeri
+func (r *ProductRepository) GetWithoutGallery(limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Unscoped().
+		Where("image_url <> '' AND NOT EXISTS (SELECT 1 FROM product_images WHERE product_images.product_id = products.id)").
+		Order("id ASC").Limit(limit).Find(&products).Error
+	return products, err
+}
+
+// UpdateSlug mengupdate slug product
+func (r *ProductRepository) UpdateSlug(id uint, slug string) error {
+	return r.db.Model(&models.Product{}).Where("id = ?", id).Update("slug", slug).Error
+}
+
+// GetByIDForUpdate mengambil product dan mengunci row-nya (SELECT ... FOR UPDATE).
+// Hanya bermakna bila dipanggil di dalam transaction.
+func (r *ProductRepository) GetByIDForUpdate(id uint) (*models.Product, error) {
+	var product models.Product
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, id).Error
 	return &product, err
 }
 
+// DecrementStock mengurangi stok product secara kondisional (stock >= quantity)
+// sehingga stok tidak pernah menjadi negatif walaupun ada checkout paralel
+func (r *ProductRepository) DecrementStock(id uint, quantity int) error {
+	result := r.db.Model(&models.Product{}).
+		Where("id = ? AND stock >= ?", id, quantity).
+		Update("stock", gorm.Expr("stock - ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// IncrementStock menambah stok product, misalnya saat order dibatalkan atau kedaluwarsa.
+// Product yang diarsipkan tetap menerima stok kembali agar ledger tetap sesuai saat di-restore.
+func (r *ProductRepository) IncrementStock(id uint, quantity int) error {
+	return r.db.Unscoped().Model(&models.Product{}).
+		Where("id = ?", id).
+		Update("stock", gorm.Expr("stock + ?", quantity)).Error
+}
+
+// Update menyimpan perubahan product kecuali stok. Stok hanya diubah lewat
+// DecrementStock/IncrementStock agar selalu tercatat di ledger. Relasi seperti
+// Variants tidak ikut disimpan, variant dikelola lewat ProductVariantRepository.
 func (r *ProductRepository) Update(product *models.Product) error {
-	return r.db.Save(product).Error
+	return r.db.Omit("Stock", clause.Associations).Save(product).Error
 }
 
+// Search mencari product berdasarkan nama dan deskripsi, diurutkan dari yang paling relevan.
+// Filter harga, stok, dan category tetap berlaku, sedangkan urutan selalu berdasarkan relevansi.
+func (r *ProductRepository) Search(query string, page, limit int, filter ProductFilter) ([]ProductSearchHit, int64, error) {
+	matches, total, err := r.searcher.Search(filter.apply(r.db), query, page, limit)
+	if err != nil || len(matches) == 0 {
+		return nil, total, err
+	}
+
+	ids := make([]uint, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+
+	var products []models.Product
+	err = r.db.Preload("Category").Preload("Variants").Preload("Images", orderImagesByPosition).
+		Where("id IN ?", ids).
+		Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadBreadcrumbs(products); err != nil {
+		return nil, 0, err
+	}
+
+	// Kembalikan product sesuai urutan relevansi dari searcher
+	byID := make(map[uint]models.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+
+	hits := make([]ProductSearchHit, 0, len(matches))
+	for _, match := range matches {
+		product, ok := byID[match.ID]
+		if !ok {
+			continue
+		}
+		hits = append(hits, ProductSearchHit{
+			Product:    product,
+			Score:      match.Score,
+			Highlights: BuildSearchHighlights(query, product),
+		})
+	}
+
+	return hits, total, nil
+}
+
+// UpdateImageURL mengupdate path gambar utama product
+func (r *ProductRepository) UpdateImageURL(id uint, imageURL string) error {
+	return r.db.Model(&models.Product{}).Where("id = ?", id).Update("image_url", imageURL).Error
+}
+
+// Delete mengarsipkan product (soft delete). Detail transaksi lama tetap bisa menampilkannya.
 func (r *ProductRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Product{}, id).Error
 }
 
-func (r *ProductRepository) GetByCategoryID(categoryID uint, page, limit int) ([]models.Product, int64, error) {
+// GetArchived mengambil product yang sudah diarsipkan, yang terakhir diarsipkan lebih dulu
+func (r *ProductRepository) GetArchived(page, limit int) ([]models.Product, int64, error) {
 	var products []models.Product
 	var total int64
 
 	offset := (page - 1) * limit
+	query := r.db.Unscoped().Model(&models.Product{}).Where("deleted_at IS NOT NULL")
 
 	// Count total records
-	if err := r.db.Model(&models.Product{}).Where("category_id = ?", categoryID).Count(&total).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// Get products with pagination
-	err := r.db.Preload("Category").
-		Where("category_id = ?", categoryID).
+	err := query.Preload("Category", unscoped).Preload("Variants").Preload("Images", orderImagesByPosition).
+		Order("deleted_at DESC, id DESC").
 		Offset(offset).
 		Limit(limit).
 		Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return products, total, r.loadBreadcrumbs(products)
+}
 
-	return products, total, err
+// GetArchivedByID mengambil product yang sudah diarsipkan berdasarkan ID
+func (r *ProductRepository) GetArchivedByID(id uint) (*models.Product, error) {
+	var product models.Product
+	err := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&product).Error
+	return &product, err
+}
+
+// Restore mengembalikan product yang sudah diarsipkan
+func (r *ProductRepository) Restore(id uint) error {
+	return r.db.Unscoped().Model(&models.Product{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+// GetByCategoryID mengambil product dalam satu category dengan filter dan urutan
+func (r *ProductRepository) GetByCategoryID(categoryID uint, page, limit int, filter ProductFilter) ([]models.Product, int64, error) {
+	filter.CategoryIDs = []uint{categoryID}
+	return r.GetAll(page, limit, filter)
+}
+
+// orderImagesByPosition mengurutkan galeri product sesuai posisi yang diatur admin
+func orderImagesByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
+
+// loadBreadcrumbs mengisi rantai parent category setiap product untuk breadcrumb
+func (r *ProductRepository) loadBreadcrumbs(products []models.Product) error {
+	categories := make([]*models.Category, 0, len(products))
+	for i := range products {
+		categories = append(categories, &products[i].Category)
+	}
+	return loadCategoryAncestors(r.db, categories)
 }
@@ -0,0 +1,330 @@
+package repositories
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"sync"
+	"tokogo/config"
+	"tokogo/models"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// fuzzySearchScanLimit membatasi jumlah product yang dinilai di memory
+// oleh pencarian in-process dan fallback typo dari pencarian FULLTEXT
+const fuzzySearchScanLimit = 2000
+
+// snippetLength adalah panjang maksimal potongan deskripsi pada hasil pencarian
+const snippetLength = 160
+
+// ProductSearchMatch adalah satu product yang cocok dengan query beserta skor relevansinya
+type ProductSearchMatch struct {
+	ID    uint
+	Score float64
+}
+
+// ProductSearchHit adalah hasil pencarian lengkap dengan product dan potongan teks yang di-highlight
+type ProductSearchHit struct {
+	Product    models.Product
+	Score      float64
+	Highlights map[string]string
+}
+
+// ProductSearcher adalah kontrak mesin pencarian product. Hasil diurutkan dari yang paling relevan.
+type ProductSearcher interface {
+	Search(db *gorm.DB, query string, page, limit int) ([]ProductSearchMatch, int64, error)
+}
+
+var (
+	defaultProductSearcher     ProductSearcher
+	defaultProductSearcherOnce sync.Once
+)
+
+// DefaultProductSearcher mengembalikan searcher yang dipilih melalui PRODUCT_SEARCH_ENGINE (fulltext atau memory)
+func DefaultProductSearcher() ProductSearcher {
+	defaultProductSearcherOnce.Do(func() {
+		switch config.GetEnv("PRODUCT_SEARCH_ENGINE", "fulltext") {
+		case "memory":
+			defaultProductSearcher = NewInMemoryProductSearcher()
+		default:
+			defaultProductSearcher = NewFullTextProductSearcher()
+		}
+	})
+	return defaultProductSearcher
+}
+
+// FullTextProductSearcher memakai index FULLTEXT MySQL dengan prefix matching (boolean mode).
+// Bila tidak ada hasil, pencarian diulang secara in-process agar query dengan typo tetap menemukan product.
+type FullTextProductSearcher struct {
+	fallback *InMemoryProductSearcher
+}
+
+// NewFullTextProductSearcher membuat instance baru FullTextProductSearcher
+func NewFullTextProductSearcher() *FullTextProductSearcher {
+	return &FullTextProductSearcher{fallback: NewInMemoryProductSearcher()}
+}
+
+func (s *FullTextProductSearcher) Search(db *gorm.DB, query string, page, limit int) ([]ProductSearchMatch, int64, error) {
+	terms := tokenizeSearchText(query)
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+
+	// Setiap term dicari sebagai prefix, misalnya "kem" menemukan "kemeja"
+	booleanQuery := strings.Join(terms, "* ") + "*"
+	match := "MATCH(name, description) AGAINST (? IN BOOLEAN MODE)"
+
+	// db sudah membawa filter listing; setiap query memakai session baru agar kondisi MATCH
+	// tidak menempel ke statement milik query berikutnya, termasuk fallback typo
+	var total int64
+	if err := db.Session(&gorm.Session{}).Model(&models.Product{}).Where(match, booleanQuery).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return s.fallback.Search(db, query, page, limit)
+	}
+
+	// Kecocokan pada nama diberi bobot lebih tinggi dari deskripsi
+	var matches []ProductSearchMatch
+	err := db.Session(&gorm.Session{}).Model(&models.Product{}).
+		Select("id, MATCH(name) AGAINST (? IN BOOLEAN MODE) * 2 + "+match+" AS score", booleanQuery, booleanQuery).
+		Where(match, booleanQuery).
+		Order("score DESC, id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Scan(&matches).Error
+
+	return matches, total, err
+}
+
+// InMemoryProductSearcher menilai relevansi product di memory. Dipakai untuk database
+// tanpa FULLTEXT (misalnya saat testing) dan sebagai fallback typo-tolerant.
+type InMemoryProductSearcher struct{}
+
+// NewInMemoryProductSearcher membuat instance baru InMemoryProductSearcher
+func NewInMemoryProductSearcher() *InMemoryProductSearcher {
+	return &InMemoryProductSearcher{}
+}
+
+func (s *InMemoryProductSearcher) Search(db *gorm.DB, query string, page, limit int) ([]ProductSearchMatch, int64, error) {
+	terms := tokenizeSearchText(query)
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+
+	var candidates []models.Product
+	err := db.Session(&gorm.Session{}).Model(&models.Product{}).
+		Select("id, name, description").
+		Order("id DESC").
+		Limit(fuzzySearchScanLimit).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matches []ProductSearchMatch
+	for _, product := range candidates {
+		if score := scoreProduct(terms, product.Name, product.Description); score > 0 {
+			matches = append(matches, ProductSearchMatch{ID: product.ID, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID > matches[j].ID
+	})
+
+	total := int64(len(matches))
+	start := min((page-1)*limit, len(matches))
+	end := min(start+limit, len(matches))
+
+	return matches[start:end], total, nil
+}
+
+// BuildSearchHighlights membuat potongan nama dan deskripsi dengan kata yang cocok dibungkus <mark>.
+// Teks asli di-escape sehingga aman ditampilkan sebagai HTML.
+func BuildSearchHighlights(query string, product models.Product) map[string]string {
+	terms := tokenizeSearchText(query)
+	highlights := make(map[string]string)
+
+	if name, ok := highlightText(product.Name, terms, 0); ok {
+		highlights["name"] = name
+	}
+	if description, ok := highlightText(product.Description, terms, snippetLength); ok {
+		highlights["description"] = description
+	}
+
+	return highlights
+}
+
+// scoreProduct menjumlahkan skor terbaik setiap term pada nama (bobot 2) dan deskripsi
+func scoreProduct(terms []string, name, description string) float64 {
+	nameWords := tokenizeSearchText(name)
+	descriptionWords := tokenizeSearchText(description)
+
+	var score float64
+	for _, term := range terms {
+		score += 2*bestWordMatch(term, nameWords) + bestWordMatch(term, descriptionWords)
+	}
+	return score
+}
+
+func bestWordMatch(term string, words []string) float64 {
+	var best float64
+	for _, word := range words {
+		best = max(best, matchWord(term, word))
+		if best == 3 {
+			break
+		}
+	}
+	return best
+}
+
+// matchWord menilai kecocokan term dengan satu kata: sama persis 3, prefix 2, typo 1
+func matchWord(term, word string) float64 {
+	switch {
+	case term == word:
+		return 3
+	case strings.HasPrefix(word, term):
+		return 2
+	}
+
+	typos := maxTypos(term)
+	if typos == 0 {
+		return 0
+	}
+
+	// Bandingkan juga dengan prefix kata agar "kemej" -> "kemeja" dan "kmeja" -> "kemeja" sama-sama cocok
+	if editDistance(term, word, typos) <= typos {
+		return 1
+	}
+	termRunes, wordRunes := []rune(term), []rune(word)
+	if len(wordRunes) > len(termRunes) && editDistance(term, string(wordRunes[:len(termRunes)]), typos) <= typos {
+		return 1
+	}
+	return 0
+}
+
+// maxTypos menentukan jumlah typo yang ditoleransi berdasarkan panjang term
+func maxTypos(term string) int {
+	switch n := len([]rune(term)); {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance menghitung Levenshtein distance, berhenti lebih awal bila melebihi limit
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > limit {
+		return limit + 1
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// tokenizeSearchText memecah teks menjadi kata huruf kecil yang hanya berisi huruf dan angka
+func tokenizeSearchText(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// highlightText membungkus kata yang cocok dengan <mark>. Bila maxLength > 0, teks dipotong
+// di sekitar kecocokan pertama. Mengembalikan false bila tidak ada kata yang cocok.
+func highlightText(text string, terms []string, maxLength int) (string, bool) {
+	runes := []rune(text)
+
+	type span struct{ start, end int }
+	var spans []span
+	for start := 0; start < len(runes); {
+		if !unicode.IsLetter(runes[start]) && !unicode.IsDigit(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end])) {
+			end++
+		}
+
+		word := strings.ToLower(string(runes[start:end]))
+		for _, term := range terms {
+			if matchWord(term, word) > 0 {
+				spans = append(spans, span{start, end})
+				break
+			}
+		}
+		start = end
+	}
+
+	if len(spans) == 0 {
+		return "", false
+	}
+
+	windowStart, windowEnd := 0, len(runes)
+	if maxLength > 0 && len(runes) > maxLength {
+		windowStart = max(0, spans[0].start-maxLength/4)
+		windowEnd = min(len(runes), windowStart+maxLength)
+	}
+
+	var builder strings.Builder
+	if windowStart > 0 {
+		builder.WriteString("…")
+	}
+	cursor := windowStart
+	for _, s := range spans {
+		if s.start < windowStart || s.end > windowEnd {
+			continue
+		}
+		builder.WriteString(html.EscapeString(string(runes[cursor:s.start])))
+		builder.WriteString("<mark>")
+		builder.WriteString(html.EscapeString(string(runes[s.start:s.end])))
+		builder.WriteString("</mark>")
+		cursor = s.end
+	}
+	builder.WriteString(html.EscapeString(string(runes[cursor:windowEnd])))
+	if windowEnd < len(runes) {
+		builder.WriteString("…")
+	}
+
+	return builder.String(), true
+}
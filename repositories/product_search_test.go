@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"tokogo/models"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sqlRecorder mencatat setiap SQL yang dibangun GORM
+type sqlRecorder struct {
+	logger.Interface
+	statements []string
+}
+
+func (r *sqlRecorder) LogMode(logger.LogLevel) logger.Interface { return r }
+
+func (r *sqlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, sql)
+}
+
+// dryRunDB membuat koneksi MySQL DryRun: SQL dibangun tanpa dikirim ke database
+func dryRunDB(t *testing.T) (*gorm.DB, *sqlRecorder) {
+	t.Helper()
+	recorder := &sqlRecorder{Interface: logger.Discard}
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "test@tcp(127.0.0.1:1)/test", SkipInitializeWithVersion: true}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               recorder,
+	})
+	if err != nil {
+		t.Fatalf("open dry run db: %v", err)
+	}
+	return db, recorder
+}
+
+func TestFullTextSearchFallbackDoesNotInheritMatch(t *testing.T) {
+	db, recorder := dryRunDB(t)
+	filter := ProductFilter{Status: models.ProductStatusPublished}
+
+	// DryRun tidak mengembalikan row sehingga COUNT bernilai 0 dan fallback typo dijalankan
+	if _, _, err := NewFullTextProductSearcher().Search(filter.apply(db), "kmeja", 1, 10); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if len(recorder.statements) != 2 {
+		t.Fatalf("statements = %q, want count and fallback scan", recorder.statements)
+	}
+
+	count, fallback := recorder.statements[0], recorder.statements[1]
+	if !strings.Contains(count, "MATCH(name, description)") || !strings.Contains(count, "products.status") {
+		t.Errorf("count query = %s", count)
+	}
+	if strings.Contains(fallback, "MATCH(") {
+		t.Errorf("fallback scan still filtered by FULLTEXT: %s", fallback)
+	}
+	if !strings.Contains(fallback, "products.status") {
+		t.Errorf("fallback scan lost listing filter: %s", fallback)
+	}
+}
+
+func TestSearchReusesFilteredScope(t *testing.T) {
+	db, recorder := dryRunDB(t)
+	scope := ProductFilter{Status: models.ProductStatusPublished}.apply(db)
+
+	// Scope yang sama dipakai berulang tanpa kondisi dari pencarian sebelumnya ikut terbawa
+	searcher := NewInMemoryProductSearcher()
+	for i := 0; i < 2; i++ {
+		if _, _, err := searcher.Search(scope, "kemeja", 1, 10); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	}
+	if len(recorder.statements) != 2 || recorder.statements[0] != recorder.statements[1] {
+		t.Errorf("statements differ between searches: %q", recorder.statements)
+	}
+}
+
+func TestScoreProductToleratesTypos(t *testing.T) {
+	tests := []struct {
+		query       string
+		name        string
+		description string
+		want        float64
+	}{
+		{query: "kemeja", name: "Kemeja Flanel", want: 6},
+		{query: "kem", name: "Kemeja Flanel", want: 4},
+		{query: "kmeja", name: "Kemeja Flanel", want: 2},
+		{query: "kemej", name: "Kemeja Flanel", want: 4},
+		{query: "flanel", name: "Kaos", description: "bahan flanel lembut", want: 3},
+		{query: "flnel", name: "Kaos", description: "bahan flanel lembut", want: 1},
+		// Prefix kata dengan huruf multi-byte dipotong per karakter, bukan per byte
+		{query: "kafee", name: "Kaféen Latte", want: 2},
+		// Term pendek tidak diberi toleransi typo
+		{query: "kas", name: "Kaos", want: 0},
+		{query: "sepatu", name: "Kemeja Flanel", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query+"/"+tt.name, func(t *testing.T) {
+			if got := scoreProduct(tokenizeSearchText(tt.query), tt.name, tt.description); got != tt.want {
+				t.Errorf("score = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"tokogo/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProductVariantRepository struct {
+	db *gorm.DB
+}
+
+// NewProductVariantRepository membuat instance baru ProductVariantRepository
+func NewProductVariantRepository(db *gorm.DB) *ProductVariantRepository {
+	return &ProductVariantRepository{
+		db: db,
+	}
+}
+
+// WithTx mengembalikan ProductVariantRepository yang memakai transaction handle tx
+func (r *ProductVariantRepository) WithTx(tx *gorm.DB) *ProductVariantRepository {
+	return &ProductVariantRepository{db: tx}
+}
+
+// Create menyimpan variant baru
+func (r *ProductVariantRepository) Create(variant *models.ProductVariant) error {
+	return r.db.Create(variant).Error
+}
+
+// GetByIDAndProductID mengambil variant berdasarkan ID yang harus milik product tertentu
+func (r *ProductVariantRepository) GetByIDAndProductID(id, productID uint) (*models.ProductVariant, error) {
+	var variant models.ProductVariant
+	err := r.db.Where("id = ? AND product_id = ?", id, productID).First(&variant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// GetByIDForUpdate mengambil variant dan mengunci row-nya (SELECT ... FOR UPDATE).
+// Hanya bermakna bila dipanggil di dalam transaction.
+func (r *ProductVariantRepository) GetByIDForUpdate(id uint) (*models.ProductVariant, error) {
+	var variant models.ProductVariant
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&variant, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// CheckSKUExists mengecek apakah SKU sudah dipakai variant lain (termasuk yang sudah dihapus)
+func (r *ProductVariantRepository) CheckSKUExists(sku string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Unscoped().Model(&models.ProductVariant{}).Where("sku = ?", sku)
+
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// Update menyimpan perubahan variant kecuali stok. Stok hanya diubah lewat
+// DecrementStock/IncrementStock agar selalu tercatat di ledger.
+func (r *ProductVariantRepository) Update(variant *models.ProductVariant) error {
+	return r.db.Omit("Stock").Save(variant).Error
+}
+
+// Delete menghapus variant (soft delete) agar riwayat transaksi tetap utuh
+func (r *ProductVariantRepository) Delete(id uint) error {
+	return r.db.Delete(&models.ProductVariant{}, id).Error
+}
+
+// DecrementStock mengurangi stok variant secara kondisional (stock >= quantity)
+func (r *ProductVariantRepository) DecrementStock(id uint, quantity int) error {
+	result := r.db.Model(&models.ProductVariant{}).
+		Where("id = ? AND stock >= ?", id, quantity).
+		Update("stock", gorm.Expr("stock - ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// IncrementStock menambah stok variant, misalnya saat order dibatalkan atau kedaluwarsa.
+// Variant yang sudah dihapus tetap menerima stok kembali agar ledger tetap sesuai saat di-restore.
+func (r *ProductVariantRepository) IncrementStock(id uint, quantity int) error {
+	result := r.db.Unscoped().Model(&models.ProductVariant{}).
+		Where("id = ?", id).
+		Update("stock", gorm.Expr("stock + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// whereVariant menambahkan kondisi variant_id. nil berarti item product tanpa variant.
+func whereVariant(db *gorm.DB, variantID *uint) *gorm.DB {
+	if variantID == nil {
+		return db.Where("variant_id IS NULL")
+	}
+	return db.Where("variant_id = ?", *variantID)
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"time"
+	"tokogo/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PromotionRepository struct {
+	db *gorm.DB
+}
+
+// NewPromotionRepository membuat instance baru PromotionRepository
+func NewPromotionRepository(db *gorm.DB) *PromotionRepository {
+	return &PromotionRepository{
+		db: db,
+	}
+}
+
+// WithTx mengembalikan PromotionRepository yang memakai transaction handle tx
+func (r *PromotionRepository) WithTx(tx *gorm.DB) *PromotionRepository {
+	return &PromotionRepository{db: tx}
+}
+
+// Create menyimpan promosi baru beserta item dan tier-nya
+func (r *PromotionRepository) Create(promotion *models.Promotion) error {
+	return r.db.Omit("Items.Product").Create(promotion).Error
+}
+
+// GetAll mengambil promosi dengan pagination, urut sesuai urutan evaluasi
+func (r *PromotionRepository) GetAll(page, limit int) ([]models.Promotion, int64, error) {
+	var promotions []models.Promotion
+	var total int64
+
+	// Count total records
+	if err := r.db.Model(&models.Promotion{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := r.preload(r.db).
+		Order("priority DESC, id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&promotions).Error
+
+	return promotions, total, err
+}
+
+// GetByID mengambil promosi berdasarkan ID
+func (r *PromotionRepository) GetByID(id uint) (*models.Promotion, error) {
+	var promotion models.Promotion
+	err := r.preload(r.db).First(&promotion, id).Error
+	return &promotion, err
+}
+
+// GetRunning mengambil promosi aktif yang berlaku pada waktu now sesuai urutan evaluasi
+func (r *PromotionRepository) GetRunning(now time.Time) ([]models.Promotion, error) {
+	var promotions []models.Promotion
+	err := r.preload(r.db).
+		Where("is_active = ?", true).
+		Where("starts_at IS NULL OR starts_at <= ?", now).
+		Where("ends_at IS NULL OR ends_at > ?", now).
+		Order("priority DESC, id ASC").
+		Find(&promotions).Error
+	return promotions, err
+}
+
+// Update menyimpan perubahan promosi dan mengganti seluruh item dan tier-nya.
+// Sebaiknya dipanggil di dalam transaction agar penggantian item dan tier tidak setengah jalan.
+func (r *PromotionRepository) Update(promotion *models.Promotion) error {
+	if err := r.db.Omit(clause.Associations).Save(promotion).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("promotion_id = ?", promotion.ID).Delete(&models.PromotionItem{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("promotion_id = ?", promotion.ID).Delete(&models.PromotionTier{}).Error; err != nil {
+		return err
+	}
+
+	for i := range promotion.Items {
+		promotion.Items[i].ID = 0
+		promotion.Items[i].PromotionID = promotion.ID
+	}
+	for i := range promotion.Tiers {
+		promotion.Tiers[i].ID = 0
+		promotion.Tiers[i].PromotionID = promotion.ID
+	}
+	if len(promotion.Items) > 0 {
+		if err := r.db.Omit("Product").Create(&promotion.Items).Error; err != nil {
+			return err
+		}
+	}
+	if len(promotion.Tiers) > 0 {
+		return r.db.Create(&promotion.Tiers).Error
+	}
+	return nil
+}
+
+// Delete menghapus promosi (soft delete). Promosi yang sudah tercatat di transaksi tidak terpengaruh.
+func (r *PromotionRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Promotion{}, id).Error
+}
+
+func (r *PromotionRepository) preload(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Preload("Items.Product").Preload("Tiers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("min_quantity ASC")
+	})
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"tokogo/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SlugRepository struct {
+	db *gorm.DB
+}
+
+// NewSlugRepository membuat instance baru SlugRepository
+func NewSlugRepository(db *gorm.DB) *SlugRepository {
+	return &SlugRepository{
+		db: db,
+	}
+}
+
+// WithTx mengembalikan SlugRepository yang memakai transaction handle tx
+func (r *SlugRepository) WithTx(tx *gorm.DB) *SlugRepository {
+	return &SlugRepository{db: tx}
+}
+
+// UniqueSlug mengembalikan base jika belum dipakai, atau base-2, base-3, dan seterusnya.
+// Pengecekan ikut baris yang sudah di-soft delete karena unique index tetap berlaku untuknya.
+func (r *SlugRepository) UniqueSlug(model interface{}, base string, excludeID uint, reserved ...string) (string, error) {
+	var taken []string
+	err := r.db.Unscoped().Model(model).
+		Where("slug = ? OR slug LIKE ?", base, base+"-%").
+		Where("id <> ?", excludeID).
+		Pluck("slug", &taken).Error
+	if err != nil {
+		return "", err
+	}
+
+	used := make(map[string]bool, len(taken)+len(reserved))
+	for _, slug := range taken {
+		used[slug] = true
+	}
+	for _, slug := range reserved {
+		used[slug] = true
+	}
+
+	if !used[base] {
+		return base, nil
+	}
+	for suffix := 2; ; suffix++ {
+		candidate := fmt.Sprintf("%s-%d", base, suffix)
+		if !used[candidate] {
+			return candidate, nil
+		}
+	}
+}
+
+// SaveRedirect mencatat slug lama yang harus diarahkan ke entity. Jika slug lama sudah
+// tercatat, entity tujuannya diperbarui.
+func (r *SlugRepository) SaveRedirect(entityType, slug string, entityID uint) error {
+	redirect := models.SlugRedirect{EntityType: entityType, Slug: slug, EntityID: entityID}
+	return r.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"entity_id"}),
+	}).Create(&redirect).Error
+}
+
+// DeleteRedirect menghapus redirect untuk slug yang kini dipakai kembali oleh entity aktif
+func (r *SlugRepository) DeleteRedirect(entityType, slug string) error {
+	return r.db.Where("entity_type = ? AND slug = ?", entityType, slug).Delete(&models.SlugRedirect{}).Error
+}
+
+// FindRedirect mencari entity tujuan dari slug lama
+func (r *SlugRepository) FindRedirect(entityType, slug string) (*models.SlugRedirect, error) {
+	var redirect models.SlugRedirect
+	err := r.db.Where("entity_type = ? AND slug = ?", entityType, slug).First(&redirect).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("slug not found")
+		}
+		return nil, err
+	}
+	return &redirect, nil
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"tokogo/models"
+
+	"gorm.io/gorm"
+)
+
+type StockMovementRepository struct {
+	db *gorm.DB
+}
+
+// NewStockMovementRepository membuat instance baru StockMovementRepository
+func NewStockMovementRepository(db *gorm.DB) *StockMovementRepository {
+	return &StockMovementRepository{
+		db: db,
+	}
+}
+
+// WithTx mengembalikan StockMovementRepository yang memakai transaction handle tx
+func (r *StockMovementRepository) WithTx(tx *gorm.DB) *StockMovementRepository {
+	return &StockMovementRepository{db: tx}
+}
+
+// Create menambahkan satu baris ke ledger. Ledger tidak pernah diupdate atau dihapus.
+func (r *StockMovementRepository) Create(movement *models.StockMovement) error {
+	return r.db.Create(movement).Error
+}
+
+// GetByProductID mengambil riwayat pergerakan stok product dengan pagination, terbaru lebih dulu
+func (r *StockMovementRepository) GetByProductID(productID uint, page, limit int) ([]models.StockMovement, int64, error) {
+	var movements []models.StockMovement
+	var total int64
+
+	query := r.db.Model(&models.StockMovement{}).Where("product_id = ?", productID)
+
+	// Count total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&movements).Error
+
+	return movements, total, err
+}
+
+// SumDeltaByProductID menjumlahkan delta ledger untuk stok product (variantID nil) atau salah satu variant-nya
+func (r *StockMovementRepository) SumDeltaByProductID(productID uint, variantID *uint) (int, error) {
+	var sum int
+	query := r.db.Model(&models.StockMovement{}).
+		Select("COALESCE(SUM(delta), 0)").
+		Where("product_id = ?", productID)
+	err := whereVariant(query, variantID).Scan(&sum).Error
+	return sum, err
+}
+
+// HasOpeningBalance mengecek apakah stok product (variantID nil) atau variant-nya sudah punya saldo awal di ledger
+func (r *StockMovementRepository) HasOpeningBalance(productID uint, variantID *uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&models.StockMovement{}).
+		Where("product_id = ? AND reason = ?", productID, models.StockMovementOpeningBalance)
+	err := whereVariant(query, variantID).Count(&count).Error
+	return count > 0, err
+}
+
+// GetProductIDsWithoutOpeningBalance mengambil product yang stoknya tidak sama dengan jumlah ledger
+// dan belum punya saldo awal, yaitu product yang dibuat sebelum ledger diperkenalkan
+func (r *StockMovementRepository) GetProductIDsWithoutOpeningBalance(limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Product{}).
+		Where("NOT EXISTS (SELECT 1 FROM stock_movements m WHERE m.product_id = products.id AND m.variant_id IS NULL AND m.reason = ?)", models.StockMovementOpeningBalance).
+		Where("products.stock <> (SELECT COALESCE(SUM(m.delta), 0) FROM stock_movements m WHERE m.product_id = products.id AND m.variant_id IS NULL)").
+		Order("products.id ASC").
+		Limit(limit).
+		Pluck("products.id", &ids).Error
+	return ids, err
+}
+
+// GetVariantsWithoutOpeningBalance sama seperti GetProductIDsWithoutOpeningBalance untuk stok variant
+func (r *StockMovementRepository) GetVariantsWithoutOpeningBalance(limit int) ([]models.ProductVariant, error) {
+	var variants []models.ProductVariant
+	err := r.db.Model(&models.ProductVariant{}).
+		Select("product_variants.id, product_variants.product_id").
+		Where("NOT EXISTS (SELECT 1 FROM stock_movements m WHERE m.variant_id = product_variants.id AND m.reason = ?)", models.StockMovementOpeningBalance).
+		Where("product_variants.stock <> (SELECT COALESCE(SUM(m.delta), 0) FROM stock_movements m WHERE m.variant_id = product_variants.id)").
+		Order("product_variants.id ASC").
+		Limit(limit).
+		Find(&variants).Error
+	return variants, err
+}
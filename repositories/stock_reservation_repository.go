@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"time"
+	"tokogo/models"
+
+	"gorm.io/gorm"
+)
+
+type StockReservationRepository struct {
+	db *gorm.DB
+}
+
+// NewStockReservationRepository membuat instance baru StockReservationRepository
+func NewStockReservationRepository(db *gorm.DB) *StockReservationRepository {
+	return &StockReservationRepository{
+		db: db,
+	}
+}
+
+// WithTx mengembalikan StockReservationRepository yang memakai transaction handle tx
+func (r *StockReservationRepository) WithTx(tx *gorm.DB) *StockReservationRepository {
+	return &StockReservationRepository{db: tx}
+}
+
+// Create menyimpan reservasi stok baru
+func (r *StockReservationRepository) Create(reservation *models.StockReservation) error {
+	return r.db.Create(reservation).Error
+}
+
+// GetReservedQuantity menghitung stok product (atau variant-nya) yang sedang ditahan user lain dan belum kedaluwarsa
+func (r *StockReservationRepository) GetReservedQuantity(productID uint, variantID *uint, excludeUserID uint, now time.Time) (int, error) {
+	var reserved int
+	query := r.db.Model(&models.StockReservation{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("product_id = ? AND user_id <> ? AND expires_at > ?", productID, excludeUserID, now)
+	err := whereVariant(query, variantID).Scan(&reserved).Error
+	return reserved, err
+}
+
+// DeleteByUserID melepaskan semua reservasi milik user
+func (r *StockReservationRepository) DeleteByUserID(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.StockReservation{}).Error
+}
+
+// DeleteExpired menghapus reservasi yang sudah kedaluwarsa
+func (r *StockReservationRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&models.StockReservation{})
+	return result.RowsAffected, result.Error
+}
@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"errors"
+	"time"
 	"tokogo/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TransactionRepository struct {
@@ -17,6 +20,11 @@ func NewTransactionRepository(db *gorm.DB) *TransactionRepository {
 	}
 }
 
+// WithTx mengembalikan TransactionRepository yang memakai transaction handle tx
+func (r *TransactionRepository) WithTx(tx *gorm.DB) *TransactionRepository {
+	return &TransactionRepository{db: tx}
+}
+
 // GetAllTransactions mengambil semua transaksi dengan pagination dan filter
 func (r *TransactionRepository) GetAllTransactions(page, limit int, status string) ([]models.Transaction, int64, error) {
 	var transactions []models.Transaction
@@ -44,12 +52,38 @@ func (r *TransactionRepository) GetAllTransactions(page, limit int, status strin
 	return transactions, total, nil
 }
 
+// transactionKeyset mengurutkan transaksi dari yang terbaru untuk listing berbasis cursor
+var transactionKeyset = keyset{name: "created_at_desc", column: "transactions.created_at", idColumn: "transactions.id", desc: true}
+
+// GetAllTransactionsByCursor mengambil transaksi setelah posisi cursor tanpa OFFSET dan COUNT
+func (r *TransactionRepository) GetAllTransactionsByCursor(cursor string, limit int, status string) ([]models.Transaction, string, error) {
+	var transactions []models.Transaction
+
+	query := r.db.Preload("User").Model(&models.Transaction{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query, err := transactionKeyset.apply(query, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := query.Find(&transactions).Error; err != nil {
+		return nil, "", err
+	}
+
+	transactions, next := nextCursor(transactionKeyset, transactions, limit, func(t models.Transaction) (interface{}, uint) {
+		return t.CreatedAt, t.ID
+	})
+	return transactions, next, nil
+}
+
 // GetTransactionByID mengambil transaksi berdasarkan ID dengan detail
 func (r *TransactionRepository) GetTransactionByID(id uint) (*models.Transaction, error) {
 	var transaction models.Transaction
 
 	// Get transaction dengan preload User dan TransactionDetails
-	err := r.db.Preload("User").Preload("TransactionDetails.Product").First(&transaction, id).Error
+	err := r.db.Preload("User").Preload("TransactionDetails.Product", unscoped).Preload("TransactionDetails.Variant", unscoped).Preload("StatusHistories", orderStatusHistories).First(&transaction, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -66,6 +100,109 @@ func (r *TransactionRepository) UpdateTransactionStatus(id uint, status string)
 	return nil
 }
 
+// GetAwaitingVerification mengambil transaksi yang menunggu verifikasi bukti pembayaran, terlama lebih dulu
+func (r *TransactionRepository) GetAwaitingVerification(page, limit int) ([]models.Transaction, int64, error) {
+	var transactions []models.Transaction
+	var total int64
+
+	query := r.db.Preload("User").Model(&models.Transaction{}).Where("status = ?", models.TransactionStatusAwaitingVerification)
+
+	// Count total records
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination dan ambil data
+	offset := (page - 1) * limit
+	if err := query.Offset(offset).Limit(limit).Order("updated_at ASC").Find(&transactions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return transactions, total, nil
+}
+
+// UpdatePaymentRejectionNote menyimpan catatan penolakan bukti pembayaran
+func (r *TransactionRepository) UpdatePaymentRejectionNote(id uint, note string) error {
+	return r.db.Model(&models.Transaction{}).Where("id = ?", id).Update("payment_rejection_note", note).Error
+}
+
+// UpdatePaymentDueAt menyimpan batas waktu pembayaran transaksi
+func (r *TransactionRepository) UpdatePaymentDueAt(id uint, dueAt time.Time) error {
+	return r.db.Model(&models.Transaction{}).Where("id = ?", id).Update("payment_due_at", dueAt).Error
+}
+
+// UpdateCancellationReason menyimpan alasan pembatalan transaksi
+func (r *TransactionRepository) UpdateCancellationReason(id uint, reason string) error {
+	return r.db.Model(&models.Transaction{}).Where("id = ?", id).Update("cancellation_reason", reason).Error
+}
+
+// UpdateRefundResult menyimpan status refund dan pesan error dari payment gateway.
+// Waktu mulai refund dicatat saat refund menjadi pending, waktu refund dicatat bila refund berhasil.
+func (r *TransactionRepository) UpdateRefundResult(id uint, status, refundError string) error {
+	updates := map[string]interface{}{
+		"refund_status": status,
+		"refund_error":  refundError,
+	}
+	switch status {
+	case models.RefundStatusPending:
+		updates["refund_started_at"] = time.Now()
+	case models.RefundStatusSucceeded:
+		updates["refunded_at"] = time.Now()
+	}
+	return r.db.Model(&models.Transaction{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// MarkLatePayment menandai transaksi yang menerima pembayaran setelah order ditutup
+func (r *TransactionRepository) MarkLatePayment(id uint) error {
+	return r.db.Model(&models.Transaction{}).Where("id = ?", id).Update("late_payment_at", time.Now()).Error
+}
+
+// UpdatePaymentCharge menyimpan referensi tagihan dan payment URL dari payment gateway
+func (r *TransactionRepository) UpdatePaymentCharge(id uint, reference, paymentURL string) error {
+	return r.db.Model(&models.Transaction{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"payment_reference": reference,
+		"payment_url":       paymentURL,
+	}).Error
+}
+
+// GetByIDForUpdate mengambil transaksi dan mengunci row-nya (SELECT ... FOR UPDATE).
+// Hanya bermakna bila dipanggil di dalam transaction.
+func (r *TransactionRepository) GetByIDForUpdate(id uint) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&transaction, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// GetExpiredPendingIDs mengambil ID transaksi pending non-COD yang batas pembayarannya sudah lewat,
+// diurutkan berdasarkan ID dan dimulai setelah afterID. Transaksi lama tanpa payment_due_at
+// dianggap lewat bila dibuat sebelum createdBefore.
+func (r *TransactionRepository) GetExpiredPendingIDs(now, createdBefore time.Time, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.Transaction{}).
+		Where("status = ? AND payment_method <> ?", models.TransactionStatusPending, models.PaymentMethodCOD).
+		Where("payment_due_at < ? OR (payment_due_at IS NULL AND created_at < ?)", now, createdBefore).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetDetailsByTransactionID mengambil detail transaksi tanpa preload
+func (r *TransactionRepository) GetDetailsByTransactionID(transactionID uint) ([]models.TransactionDetail, error) {
+	var details []models.TransactionDetail
+	err := r.db.Where("transaction_id = ?", transactionID).Order("product_id ASC").Find(&details).Error
+	return details, err
+}
+
+// CreateStatusHistory mencatat perubahan status transaksi
+func (r *TransactionRepository) CreateStatusHistory(history *models.TransactionStatusHistory) error {
+	return r.db.Create(history).Error
+}
+
 // Create membuat transaksi baru
 func (r *TransactionRepository) Create(transaction *models.Transaction) error {
 	return r.db.Create(transaction).Error
@@ -79,7 +216,7 @@ func (r *TransactionRepository) Update(transaction *models.Transaction) error {
 // GetByID mengambil transaksi berdasarkan ID
 func (r *TransactionRepository) GetByID(id uint) (*models.Transaction, error) {
 	var transaction models.Transaction
-	err := r.db.Preload("User").Preload("TransactionDetails.Product").First(&transaction, id).Error
+	err := r.db.Preload("User").Preload("TransactionDetails.Product", unscoped).Preload("TransactionDetails.Variant", unscoped).Preload("StatusHistories", orderStatusHistories).First(&transaction, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +226,7 @@ func (r *TransactionRepository) GetByID(id uint) (*models.Transaction, error) {
 // GetByUserID mengambil transaksi berdasarkan User ID
 func (r *TransactionRepository) GetByUserID(userID uint) ([]models.Transaction, error) {
 	var transactions []models.Transaction
-	err := r.db.Preload("User").Preload("TransactionDetails.Product").Where("user_id = ?", userID).Order("created_at DESC").Find(&transactions).Error
+	err := r.db.Preload("User").Preload("TransactionDetails.Product", unscoped).Preload("TransactionDetails.Variant", unscoped).Where("user_id = ?", userID).Order("created_at DESC").Find(&transactions).Error
 	return transactions, err
 }
 
@@ -97,3 +234,37 @@ func (r *TransactionRepository) GetByUserID(userID uint) ([]models.Transaction,
 func (r *TransactionRepository) CreateTransactionDetail(detail *models.TransactionDetail) error {
 	return r.db.Create(detail).Error
 }
+
+// orderStatusHistories mengurutkan status history dari yang paling lama
+func orderStatusHistories(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC, id ASC")
+}
+
+// unscoped ikut memuat row yang sudah di-soft delete, misalnya variant yang sudah dihapus pada riwayat transaksi
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// ReleaseVoucherRedemption melepas pemakaian voucher pada transaksi yang batal, gagal, atau kedaluwarsa
+// dan mengembalikan kuota voucher-nya. Tidak melakukan apa-apa bila transaksi tidak memakai voucher.
+func (r *TransactionRepository) ReleaseVoucherRedemption(transactionID uint) error {
+	var redemption models.VoucherRedemption
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("transaction_id = ? AND released_at IS NULL", transactionID).
+		First(&redemption).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := r.db.Model(&redemption).Update("released_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	// Voucher yang sudah dihapus tetap dikurangi agar jumlah pemakaian sesuai riwayat
+	return r.db.Unscoped().Model(&models.Voucher{}).
+		Where("id = ? AND used_count > 0", redemption.VoucherID).
+		Update("used_count", gorm.Expr("used_count - 1")).Error
+}
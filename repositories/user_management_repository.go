@@ -63,6 +63,27 @@ func (r *UserManagementRepository) GetAllUsers(page, limit int) ([]models.User,
 	return users, total, nil
 }
 
+// userKeyset mengurutkan users berdasarkan id, sama seperti urutan bawaan listing offset
+var userKeyset = keyset{name: "id_asc", column: "users.id", idColumn: "users.id"}
+
+// GetAllUsersByCursor mengambil users setelah posisi cursor tanpa OFFSET dan COUNT
+func (r *UserManagementRepository) GetAllUsersByCursor(cursor string, limit int) ([]models.User, string, error) {
+	var users []models.User
+
+	query, err := userKeyset.apply(r.db.Model(&models.User{}), cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := query.Find(&users).Error; err != nil {
+		return nil, "", err
+	}
+
+	users, next := nextCursor(userKeyset, users, limit, func(u models.User) (interface{}, uint) {
+		return u.ID, u.ID
+	})
+	return users, next, nil
+}
+
 // UpdateUser mengupdate user
 func (r *UserManagementRepository) UpdateUser(user *models.User) error {
 	return r.db.Save(user).Error
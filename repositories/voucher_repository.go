@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"errors"
+	"tokogo/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVoucherExhausted dikembalikan ketika kuota pemakaian global voucher sudah habis
+var ErrVoucherExhausted = errors.New("voucher usage limit reached")
+
+type VoucherRepository struct {
+	db *gorm.DB
+}
+
+// NewVoucherRepository membuat instance baru VoucherRepository
+func NewVoucherRepository(db *gorm.DB) *VoucherRepository {
+	return &VoucherRepository{
+		db: db,
+	}
+}
+
+// WithTx mengembalikan VoucherRepository yang memakai transaction handle tx
+func (r *VoucherRepository) WithTx(tx *gorm.DB) *VoucherRepository {
+	return &VoucherRepository{db: tx}
+}
+
+// Create menyimpan voucher baru beserta batasan category dan product-nya
+func (r *VoucherRepository) Create(voucher *models.Voucher) error {
+	return r.db.Omit("Categories.*", "Products.*").Create(voucher).Error
+}
+
+// GetAll mengambil voucher dengan pagination, terbaru lebih dulu
+func (r *VoucherRepository) GetAll(page, limit int) ([]models.Voucher, int64, error) {
+	var vouchers []models.Voucher
+	var total int64
+
+	// Count total records
+	if err := r.db.Model(&models.Voucher{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := r.db.Preload("Categories").Preload("Products").
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&vouchers).Error
+
+	return vouchers, total, err
+}
+
+// GetByID mengambil voucher berdasarkan ID
+func (r *VoucherRepository) GetByID(id uint) (*models.Voucher, error) {
+	var voucher models.Voucher
+	err := r.db.Preload("Categories").Preload("Products").First(&voucher, id).Error
+	return &voucher, err
+}
+
+// GetByCode mengambil voucher berdasarkan kode
+func (r *VoucherRepository) GetByCode(code string) (*models.Voucher, error) {
+	var voucher models.Voucher
+	err := r.db.Preload("Categories").Preload("Products").Where("code = ?", code).First(&voucher).Error
+	return &voucher, err
+}
+
+// GetByCodeForUpdate mengambil voucher berdasarkan kode dan mengunci row-nya (SELECT ... FOR UPDATE)
+// sehingga pemakaian voucher yang sama oleh checkout paralel berjalan bergantian.
+// Hanya bermakna bila dipanggil di dalam transaction.
+func (r *VoucherRepository) GetByCodeForUpdate(code string) (*models.Voucher, error) {
+	var voucher models.Voucher
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Categories").Preload("Products").
+		Where("code = ?", code).
+		First(&voucher).Error
+	return &voucher, err
+}
+
+// CheckCodeExists mengecek apakah kode sudah dipakai voucher lain, termasuk voucher yang sudah dihapus
+func (r *VoucherRepository) CheckCodeExists(code string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Unscoped().Model(&models.Voucher{}).Where("code = ?", code)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// Update menyimpan perubahan voucher dan mengganti batasan category dan product-nya
+// dalam satu transaction, sehingga voucher tidak pernah tersimpan dengan batasan setengah terganti
+func (r *VoucherRepository) Update(voucher *models.Voucher) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("UsedCount", clause.Associations).Save(voucher).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(voucher).Association("Categories").Replace(voucher.Categories); err != nil {
+			return err
+		}
+		return tx.Model(voucher).Association("Products").Replace(voucher.Products)
+	})
+}
+
+// Delete menghapus voucher (soft delete). Riwayat pemakaian tetap tersimpan.
+func (r *VoucherRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Voucher{}, id).Error
+}
+
+// CountUserRedemptions menghitung pemakaian voucher oleh satu user yang belum dilepas.
+// Memakai locking read agar di dalam transaction tetap membaca data terbaru, bukan snapshot
+// awal transaction, sehingga pemakaian oleh checkout paralel yang sudah commit ikut terhitung.
+func (r *VoucherRepository) CountUserRedemptions(voucherID, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Clauses(clause.Locking{Strength: "SHARE"}).Model(&models.VoucherRedemption{}).
+		Where("voucher_id = ? AND user_id = ? AND released_at IS NULL", voucherID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+// Redeem menambah jumlah pemakaian voucher secara kondisional (used_count < usage_limit)
+// sehingga kuota tidak pernah terlampaui walaupun ada checkout paralel
+func (r *VoucherRepository) Redeem(voucherID uint) error {
+	result := r.db.Model(&models.Voucher{}).
+		Where("id = ? AND (usage_limit IS NULL OR used_count < usage_limit)", voucherID).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVoucherExhausted
+	}
+	return nil
+}
+
+// CreateRedemption mencatat pemakaian voucher pada satu transaksi
+func (r *VoucherRepository) CreateRedemption(redemption *models.VoucherRedemption) error {
+	return r.db.Create(redemption).Error
+}
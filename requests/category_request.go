@@ -9,12 +9,15 @@ import (
 
 // CreateCategoryRequest represents the request structure for creating category
 type CreateCategoryRequest struct {
-	Name string `json:"name" validate:"required,min=2,max=100"`
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	ParentID *uint  `json:"parent_id" validate:"omitempty,min=1"`
 }
 
 // UpdateCategoryRequest represents the request structure for updating category
+// ParentID nil memindahkan category ke root
 type UpdateCategoryRequest struct {
-	Name string `json:"name" validate:"required,min=2,max=100"`
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	ParentID *uint  `json:"parent_id" validate:"omitempty,min=1"`
 }
 
 // Validate validates the CreateCategoryRequest using the validator
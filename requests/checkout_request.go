@@ -1,11 +1,15 @@
 package requests
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 type CheckoutRequest struct {
 	ShippingAddress string `json:"shipping_address" binding:"required"`
 	PaymentMethod   string `json:"payment_method" binding:"required"`
 	Notes           string `json:"notes"`
+	VoucherCode     string `json:"voucher_code"`
 }
 
 func (r *CheckoutRequest) Validate() error {
@@ -29,17 +33,36 @@ func (r *CheckoutRequest) Validate() error {
 		return errors.New("invalid payment method")
 	}
 
+	if len(r.VoucherCode) > 50 {
+		return errors.New("voucher_code must not exceed 50 characters")
+	}
+
 	return nil
 }
 
+// ConfirmPaymentRequest is sent as multipart form data together with the
+// payment_proof image file
 type ConfirmPaymentRequest struct {
-	PaymentProof string `json:"payment_proof" binding:"required"`
-	Notes        string `json:"notes"`
+	Notes string `form:"notes" json:"notes"`
 }
 
 func (r *ConfirmPaymentRequest) Validate() error {
-	if r.PaymentProof == "" {
-		return errors.New("payment_proof is required")
+	if len(r.Notes) > 1000 {
+		return errors.New("notes must not exceed 1000 characters")
+	}
+	return nil
+}
+
+type CancelTransactionRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+func (r *CancelTransactionRequest) Validate() error {
+	if strings.TrimSpace(r.Reason) == "" {
+		return errors.New("reason is required")
+	}
+	if len(r.Reason) > 1000 {
+		return errors.New("reason must not exceed 1000 characters")
 	}
 	return nil
 }
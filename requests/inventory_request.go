@@ -0,0 +1,32 @@
+package requests
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// AdjustStockRequest represents the request structure for a manual stock adjustment
+type AdjustStockRequest struct {
+	VariantID *uint  `json:"variant_id" validate:"omitempty,min=1"`
+	Delta     int    `json:"delta" validate:"required"`
+	Reason    string `json:"reason" validate:"required,oneof=manual_adjustment restock return"`
+	Note      string `json:"note" validate:"omitempty,max=1000"`
+}
+
+// Validate validates the AdjustStockRequest using the validator
+func (r *AdjustStockRequest) Validate() error {
+	validate := validator.New()
+
+	// Validasi struct fields
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	// Validasi custom: restock dan return hanya boleh menambah stok
+	if r.Reason != "manual_adjustment" && r.Delta < 0 {
+		return errors.New("restock and return must increase stock")
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package requests
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ReorderProductImagesRequest represents the request structure for reordering product gallery
+type ReorderProductImagesRequest struct {
+	ImageIDs []uint `json:"image_ids" validate:"required,min=1,dive,min=1"`
+}
+
+// Validate validates the ReorderProductImagesRequest using the validator
+func (r *ReorderProductImagesRequest) Validate() error {
+	validate := validator.New()
+
+	// Validasi struct fields
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	// Validasi custom: setiap gambar hanya boleh muncul sekali
+	seen := make(map[uint]bool, len(r.ImageIDs))
+	for _, id := range r.ImageIDs {
+		if seen[id] {
+			return errors.New("image_ids must not contain duplicates")
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package requests
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ProductListQuery represents the query parameters for filtering and sorting public product listings
+type ProductListQuery struct {
+	MinPrice           *float64 `form:"min_price" validate:"omitempty,min=0"`
+	MaxPrice           *float64 `form:"max_price" validate:"omitempty,min=0"`
+	InStock            bool     `form:"in_stock"`
+	CategoryIDs        string   `form:"category_ids"`
+	IncludeDescendants bool     `form:"include_descendants"`
+	Sort               string   `form:"sort" validate:"omitempty,oneof=newest price_asc price_desc best_selling name_asc name_desc"`
+}
+
+// Validate validates the ProductListQuery using the validator
+func (r *ProductListQuery) Validate() error {
+	validate := validator.New()
+
+	// Validasi struct fields
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	// Validasi custom: rentang harga harus masuk akal
+	if r.MinPrice != nil && r.MaxPrice != nil && *r.MinPrice > *r.MaxPrice {
+		return errors.New("min_price must not be greater than max_price")
+	}
+
+	_, err := r.CategoryIDList()
+	return err
+}
+
+// CategoryIDList mengubah category_ids yang dipisah koma menjadi slice id
+func (r *ProductListQuery) CategoryIDList() ([]uint, error) {
+	var ids []uint
+	for _, part := range strings.Split(r.CategoryIDs, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || id == 0 {
+			return nil, errors.New("category_ids must be a comma separated list of category IDs")
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
@@ -3,28 +3,50 @@ package requests
 import (
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 )
 
 // CreateProductRequest represents the request structure for creating product
 type CreateProductRequest struct {
-	Name          string  `json:"name" validate:"required,min=3,max=255"`
-	Description   string  `json:"description"`
-	PurchasePrice float64 `json:"purchase_price" validate:"required,min=0"`
-	SellingPrice  float64 `json:"selling_price" validate:"required,min=0"`
-	Stock         int     `json:"stock" validate:"min=0"`
-	CategoryID    uint    `json:"category_id" validate:"required"`
+	Name              string     `json:"name" validate:"required,min=3,max=255"`
+	Description       string     `json:"description"`
+	PurchasePrice     float64    `json:"purchase_price" validate:"required,min=0"`
+	SellingPrice      float64    `json:"selling_price" validate:"required,min=0"`
+	Stock             int        `json:"stock" validate:"min=0"`
+	LowStockThreshold int        `json:"low_stock_threshold" validate:"min=0"`
+	CategoryID        uint       `json:"category_id" validate:"required"`
+	Status            string     `json:"status" validate:"omitempty,oneof=draft published hidden"`
+	PublishAt         *time.Time `json:"publish_at"`
+	UnpublishAt       *time.Time `json:"unpublish_at"`
+	CompareAtPrice    *float64   `json:"compare_at_price" validate:"omitempty,gt=0"`
+	SalePrice         *float64   `json:"sale_price" validate:"omitempty,gt=0"`
+	SaleStartsAt      *time.Time `json:"sale_starts_at"`
+	SaleEndsAt        *time.Time `json:"sale_ends_at"`
 }
 
-// UpdateProductRequest represents the request structure for updating product
+// UpdateProductRequest represents the request structure for updating product.
+// Stok tidak bisa diubah dari sini, gunakan endpoint stock adjustment agar tercatat di ledger.
+// Field opsional yang tidak dikirim tidak mengubah nilai yang tersimpan. ClearPublishSchedule
+// mengosongkan publish_at dan unpublish_at, ClearSale mengosongkan compare_at_price, sale_price
+// dan periode sale sebelum nilai baru dari request diterapkan.
 type UpdateProductRequest struct {
-	Name          string  `json:"name" validate:"required,min=3,max=255"`
-	Description   string  `json:"description"`
-	PurchasePrice float64 `json:"purchase_price" validate:"required,min=0"`
-	SellingPrice  float64 `json:"selling_price" validate:"required,min=0"`
-	Stock         int     `json:"stock" validate:"min=0"`
-	CategoryID    uint    `json:"category_id" validate:"required"`
+	Name                 string     `json:"name" validate:"required,min=3,max=255"`
+	Description          string     `json:"description"`
+	PurchasePrice        float64    `json:"purchase_price" validate:"required,min=0"`
+	SellingPrice         float64    `json:"selling_price" validate:"required,min=0"`
+	LowStockThreshold    *int       `json:"low_stock_threshold" validate:"omitempty,min=0"`
+	CategoryID           uint       `json:"category_id" validate:"required"`
+	Status               string     `json:"status" validate:"omitempty,oneof=draft published hidden"`
+	PublishAt            *time.Time `json:"publish_at"`
+	UnpublishAt          *time.Time `json:"unpublish_at"`
+	CompareAtPrice       *float64   `json:"compare_at_price" validate:"omitempty,gt=0"`
+	SalePrice            *float64   `json:"sale_price" validate:"omitempty,gt=0"`
+	SaleStartsAt         *time.Time `json:"sale_starts_at"`
+	SaleEndsAt           *time.Time `json:"sale_ends_at"`
+	ClearPublishSchedule bool       `json:"clear_publish_schedule"`
+	ClearSale            bool       `json:"clear_sale"`
 }
 
 // Validate validates the CreateProductRequest using the validator
@@ -46,7 +68,11 @@ func (r *CreateProductRequest) Validate() error {
 		return errors.New("selling price must be greater than purchase price")
 	}
 
-	return nil
+	if err := ValidatePublishWindow(r.PublishAt, r.UnpublishAt); err != nil {
+		return err
+	}
+
+	return ValidateSalePrice(r.SellingPrice, r.CompareAtPrice, r.SalePrice, r.SaleStartsAt, r.SaleEndsAt)
 }
 
 // Validate validates the UpdateProductRequest using the validator
@@ -68,5 +94,43 @@ func (r *UpdateProductRequest) Validate() error {
 		return errors.New("selling price must be greater than purchase price")
 	}
 
+	if err := ValidatePublishWindow(r.PublishAt, r.UnpublishAt); err != nil {
+		return err
+	}
+
+	// Sale price boleh tidak dikirim karena nilai yang tersimpan dipertahankan,
+	// gabungannya divalidasi ulang oleh service
+	return validateSaleFields(r.SellingPrice, r.CompareAtPrice, r.SalePrice, r.SaleStartsAt, r.SaleEndsAt)
+}
+
+// ValidatePublishWindow memastikan jadwal unpublish berada setelah jadwal publish.
+// Dipakai juga oleh service untuk memeriksa jadwal hasil gabungan saat update.
+func ValidatePublishWindow(publishAt, unpublishAt *time.Time) error {
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return errors.New("unpublish_at must be after publish_at")
+	}
+	return nil
+}
+
+// ValidateSalePrice memastikan harga sale lebih murah dari harga jual, harga coret lebih mahal
+// dari harga jual, dan periode sale valid
+func ValidateSalePrice(sellingPrice float64, compareAtPrice, salePrice *float64, startsAt, endsAt *time.Time) error {
+	if salePrice == nil && (startsAt != nil || endsAt != nil) {
+		return errors.New("sale_price is required when sale period is set")
+	}
+	return validateSaleFields(sellingPrice, compareAtPrice, salePrice, startsAt, endsAt)
+}
+
+// validateSaleFields memeriksa field harga sale yang diisi tanpa mewajibkan sale_price
+func validateSaleFields(sellingPrice float64, compareAtPrice, salePrice *float64, startsAt, endsAt *time.Time) error {
+	if compareAtPrice != nil && *compareAtPrice <= sellingPrice {
+		return errors.New("compare_at_price must be greater than selling price")
+	}
+	if salePrice != nil && *salePrice >= sellingPrice {
+		return errors.New("sale_price must be less than selling price")
+	}
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return errors.New("sale_ends_at must be after sale_starts_at")
+	}
 	return nil
 }
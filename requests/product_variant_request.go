@@ -0,0 +1,63 @@
+package requests
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// CreateProductVariantRequest represents the request structure for creating product variant
+type CreateProductVariantRequest struct {
+	SKU           string            `json:"sku" validate:"required,max=100"`
+	Options       map[string]string `json:"options" validate:"required,min=1"`
+	PriceOverride *float64          `json:"price_override" validate:"omitempty,min=0"`
+	Stock         int               `json:"stock" validate:"min=0"`
+}
+
+// UpdateProductVariantRequest represents the request structure for updating product variant
+type UpdateProductVariantRequest struct {
+	SKU           string            `json:"sku" validate:"required,max=100"`
+	Options       map[string]string `json:"options" validate:"required,min=1"`
+	PriceOverride *float64          `json:"price_override" validate:"omitempty,min=0"`
+}
+
+// Validate validates the CreateProductVariantRequest using the validator
+func (r *CreateProductVariantRequest) Validate() error {
+	validate := validator.New()
+
+	// Validasi struct fields
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	return validateVariantFields(&r.SKU, r.Options)
+}
+
+// Validate validates the UpdateProductVariantRequest using the validator
+func (r *UpdateProductVariantRequest) Validate() error {
+	validate := validator.New()
+
+	// Validasi struct fields
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	return validateVariantFields(&r.SKU, r.Options)
+}
+
+// validateVariantFields merapikan SKU dan memastikan nama serta nilai opsi tidak kosong
+func validateVariantFields(sku *string, options map[string]string) error {
+	*sku = strings.TrimSpace(*sku)
+	if *sku == "" {
+		return errors.New("sku cannot be empty")
+	}
+
+	for name, value := range options {
+		if strings.TrimSpace(name) == "" || strings.TrimSpace(value) == "" {
+			return errors.New("option name and value cannot be empty")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package requests
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// PromotionItemRequest represents a product included in a promotion
+type PromotionItemRequest struct {
+	ProductID uint `json:"product_id" validate:"required,min=1"`
+	Quantity  int  `json:"quantity" validate:"omitempty,min=1"`
+}
+
+// PromotionTierRequest represents one quantity_tier discount level
+type PromotionTierRequest struct {
+	MinQuantity     int     `json:"min_quantity" validate:"required,min=1"`
+	DiscountPercent float64 `json:"discount_percent" validate:"gt=0,max=100"`
+}
+
+// CreatePromotionRequest represents the request structure for creating promotion
+type CreatePromotionRequest struct {
+	Name               string                 `json:"name" validate:"required,min=3,max=255"`
+	Description        string                 `json:"description" validate:"max=1000"`
+	Type               string                 `json:"type" validate:"required,oneof=buy_x_get_y quantity_tier bundle"`
+	Priority           int                    `json:"priority"`
+	Exclusive          bool                   `json:"exclusive"`
+	BuyQuantity        int                    `json:"buy_quantity" validate:"min=0"`
+	GetQuantity        int                    `json:"get_quantity" validate:"min=0"`
+	GetDiscountPercent float64                `json:"get_discount_percent" validate:"min=0,max=100"`
+	BundlePrice        *float64               `json:"bundle_price" validate:"omitempty,min=0"`
+	StartsAt           *time.Time             `json:"starts_at"`
+	EndsAt             *time.Time             `json:"ends_at"`
+	IsActive           *bool                  `json:"is_active"`
+	Items              []PromotionItemRequest `json:"items" validate:"dive"`
+	Tiers              []PromotionTierRequest `json:"tiers" validate:"dive"`
+}
+
+// UpdatePromotionRequest represents the request structure for updating promotion.
+// Items dan tiers diganti seluruhnya dengan isi request.
+type UpdatePromotionRequest struct {
+	Name               string                 `json:"name" validate:"required,min=3,max=255"`
+	Description        string                 `json:"description" validate:"max=1000"`
+	Type               string                 `json:"type" validate:"required,oneof=buy_x_get_y quantity_tier bundle"`
+	Priority           int                    `json:"priority"`
+	Exclusive          bool                   `json:"exclusive"`
+	BuyQuantity        int                    `json:"buy_quantity" validate:"min=0"`
+	GetQuantity        int                    `json:"get_quantity" validate:"min=0"`
+	GetDiscountPercent float64                `json:"get_discount_percent" validate:"min=0,max=100"`
+	BundlePrice        *float64               `json:"bundle_price" validate:"omitempty,min=0"`
+	StartsAt           *time.Time             `json:"starts_at"`
+	EndsAt             *time.Time             `json:"ends_at"`
+	IsActive           *bool                  `json:"is_active"`
+	Items              []PromotionItemRequest `json:"items" validate:"dive"`
+	Tiers              []PromotionTierRequest `json:"tiers" validate:"dive"`
+}
+
+// Validate validates the CreatePromotionRequest using the validator
+func (r *CreatePromotionRequest) Validate() error {
+	validate := validator.New()
+
+	// Validasi struct fields
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	return validatePromotion(*r)
+}
+
+// Validate validates the UpdatePromotionRequest using the validator
+func (r *UpdatePromotionRequest) Validate() error {
+	validate := validator.New()
+
+	// Validasi struct fields
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	return validatePromotion(CreatePromotionRequest(*r))
+}
+
+// validatePromotion memastikan field yang dibutuhkan setiap jenis promosi terisi dan periode berlaku valid
+func validatePromotion(r CreatePromotionRequest) error {
+	products := make(map[uint]bool, len(r.Items))
+	for _, item := range r.Items {
+		if products[item.ProductID] {
+			return fmt.Errorf("product %d is listed more than once in items", item.ProductID)
+		}
+		products[item.ProductID] = true
+	}
+
+	switch r.Type {
+	case "buy_x_get_y":
+		if r.BuyQuantity < 1 || r.GetQuantity < 1 {
+			return errors.New("buy_quantity and get_quantity must be at least 1")
+		}
+		if r.GetDiscountPercent <= 0 {
+			return errors.New("get_discount_percent must be greater than 0")
+		}
+	case "quantity_tier":
+		if len(r.Tiers) == 0 {
+			return errors.New("quantity_tier promotion requires at least one tier")
+		}
+		minQuantities := make(map[int]bool, len(r.Tiers))
+		for _, tier := range r.Tiers {
+			if minQuantities[tier.MinQuantity] {
+				return fmt.Errorf("tier min_quantity %d is listed more than once", tier.MinQuantity)
+			}
+			minQuantities[tier.MinQuantity] = true
+		}
+	case "bundle":
+		if r.BundlePrice == nil {
+			return errors.New("bundle_price is required for bundle promotion")
+		}
+		if len(r.Items) == 0 {
+			return errors.New("bundle promotion requires at least one item")
+		}
+	}
+
+	if r.StartsAt != nil && r.EndsAt != nil && !r.EndsAt.After(*r.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	return nil
+}
@@ -9,12 +9,13 @@ import (
 
 // UpdateTransactionStatusRequest represents the request structure for updating transaction status
 type UpdateTransactionStatusRequest struct {
-	Status string `json:"status" validate:"required,oneof=pending paid failed expired"`
+	Status string `json:"status" validate:"required,oneof=pending awaiting_verification paid processing shipped delivered completed cancelled refunded failed expired"`
+	Note   string `json:"note" validate:"omitempty,max=1000"`
 }
 
 // GetTransactionsRequest represents the request structure for getting transactions with filters
 type GetTransactionsRequest struct {
-	Status string `json:"status" validate:"omitempty,oneof=pending paid failed expired"`
+	Status string `json:"status" validate:"omitempty,oneof=pending awaiting_verification paid processing shipped delivered completed cancelled refunded failed expired"`
 	Page   int    `json:"page" validate:"omitempty,min=1"`
 	Limit  int    `json:"limit" validate:"omitempty,min=1,max=100"`
 }
@@ -55,3 +56,25 @@ func (r *GetTransactionsRequest) Validate() error {
 
 	return nil
 }
+
+// RejectPaymentProofRequest represents the request structure for rejecting a payment proof
+type RejectPaymentProofRequest struct {
+	Note string `json:"note" validate:"required,max=1000"`
+}
+
+// Validate validates the RejectPaymentProofRequest using the validator
+func (r *RejectPaymentProofRequest) Validate() error {
+	validate := validator.New()
+
+	// Validasi struct fields
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	// Validasi custom: note tidak boleh kosong setelah trim
+	if strings.TrimSpace(r.Note) == "" {
+		return errors.New("note cannot be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package requests
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// voucherCodePattern membatasi kode voucher ke huruf, angka, strip, dan underscore
+var voucherCodePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// CreateVoucherRequest represents the request structure for creating voucher
+type CreateVoucherRequest struct {
+	Code         string     `json:"code" validate:"required,min=3,max=50"`
+	Description  string     `json:"description" validate:"max=1000"`
+	Type         string     `json:"type" validate:"required,oneof=percentage fixed free_shipping"`
+	Value        float64    `json:"value" validate:"min=0"`
+	MinSpend     float64    `json:"min_spend" validate:"min=0"`
+	MaxDiscount  *float64   `json:"max_discount" validate:"omitempty,gt=0"`
+	UsageLimit   *int       `json:"usage_limit" validate:"omitempty,min=1"`
+	PerUserLimit *int       `json:"per_user_limit" validate:"omitempty,min=1"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	IsActive     *bool      `json:"is_active"`
+	CategoryIDs  []uint     `json:"category_ids" validate:"dive,min=1"`
+	ProductIDs   []uint     `json:"product_ids" validate:"dive,min=1"`
+}
+
+// UpdateVoucherRequest represents the request structure for updating voucher.
+// Batasan category dan product diganti seluruhnya dengan isi request.
+type UpdateVoucherRequest struct {
+	Code         string     `json:"code" validate:"required,min=3,max=50"`
+	Description  string     `json:"description" validate:"max=1000"`
+	Type         string     `json:"type" validate:"required,oneof=percentage fixed free_shipping"`
+	Value        float64    `json:"value" validate:"min=0"`
+	MinSpend     float64    `json:"min_spend" validate:"min=0"`
+	MaxDiscount  *float64   `json:"max_discount" validate:"omitempty,gt=0"`
+	UsageLimit   *int       `json:"usage_limit" validate:"omitempty,min=1"`
+	PerUserLimit *int       `json:"per_user_limit" validate:"omitempty,min=1"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	IsActive     *bool      `json:"is_active"`
+	CategoryIDs  []uint     `json:"category_ids" validate:"dive,min=1"`
+	ProductIDs   []uint     `json:"product_ids" validate:"dive,min=1"`
+}
+
+// Validate validates the CreateVoucherRequest using the validator
+func (r *CreateVoucherRequest) Validate() error {
+	validate := validator.New()
+
+	// Validasi struct fields
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	return validateVoucher(r.Code, r.Type, r.Value, r.StartsAt, r.EndsAt)
+}
+
+// Validate validates the UpdateVoucherRequest using the validator
+func (r *UpdateVoucherRequest) Validate() error {
+	validate := validator.New()
+
+	// Validasi struct fields
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+
+	return validateVoucher(r.Code, r.Type, r.Value, r.StartsAt, r.EndsAt)
+}
+
+// validateVoucher memastikan format kode, nilai diskon sesuai jenis voucher, dan periode berlaku valid
+func validateVoucher(code, voucherType string, value float64, startsAt, endsAt *time.Time) error {
+	if !voucherCodePattern.MatchString(strings.TrimSpace(code)) {
+		return errors.New("code may only contain letters, numbers, dashes and underscores")
+	}
+
+	switch voucherType {
+	case "percentage":
+		if value <= 0 || value > 100 {
+			return errors.New("percentage value must be between 0 and 100")
+		}
+	case "fixed":
+		if value <= 0 {
+			return errors.New("fixed value must be greater than 0")
+		}
+	}
+
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	return nil
+}
@@ -1,18 +1,25 @@
 package responses
 
-import "tokogo/models"
+import (
+	"time"
+	"tokogo/helpers"
+	"tokogo/models"
+)
 
 type CartItemResponse struct {
-	ID           uint    `json:"id"`
-	UserID       uint    `json:"user_id"`
-	ProductID    uint    `json:"product_id"`
-	ProductName  string  `json:"product_name"`
-	ProductPrice float64 `json:"product_price"`
-	ProductImage string  `json:"product_image"`
-	Quantity     int     `json:"quantity"`
-	Subtotal     float64 `json:"subtotal"`
-	CreatedAt    string  `json:"created_at"`
-	UpdatedAt    string  `json:"updated_at"`
+	ID             uint              `json:"id"`
+	UserID         uint              `json:"user_id"`
+	ProductID      uint              `json:"product_id"`
+	ProductName    string            `json:"product_name"`
+	ProductPrice   float64           `json:"product_price"`
+	ProductImage   string            `json:"product_image"`
+	VariantID      *uint             `json:"variant_id,omitempty"`
+	VariantSKU     string            `json:"variant_sku,omitempty"`
+	VariantOptions map[string]string `json:"variant_options,omitempty"`
+	Quantity       int               `json:"quantity"`
+	Subtotal       float64           `json:"subtotal"`
+	CreatedAt      string            `json:"created_at"`
+	UpdatedAt      string            `json:"updated_at"`
 }
 
 type CartResponse struct {
@@ -22,20 +29,28 @@ type CartResponse struct {
 }
 
 func ConvertCartToResponse(cart models.Cart) CartItemResponse {
-	subtotal := float64(cart.Quantity) * cart.Product.SellingPrice
+	price := cart.UnitPrice(time.Now())
+	subtotal := float64(cart.Quantity) * price
 
-	return CartItemResponse{
+	response := CartItemResponse{
 		ID:           cart.ID,
 		UserID:       cart.UserID,
 		ProductID:    cart.ProductID,
 		ProductName:  cart.Product.Name,
-		ProductPrice: cart.Product.SellingPrice,
-		ProductImage: cart.Product.ImageURL,
+		ProductPrice: price,
+		ProductImage: helpers.PublicURL(cart.Product.ImageURL),
+		VariantID:    cart.VariantID,
 		Quantity:     cart.Quantity,
 		Subtotal:     subtotal,
 		CreatedAt:    cart.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:    cart.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
+	if cart.Variant != nil {
+		response.VariantSKU = cart.Variant.SKU
+		response.VariantOptions = cart.Variant.Options
+	}
+
+	return response
 }
 
 func ConvertCartsToResponse(carts []models.Cart) []CartItemResponse {
@@ -0,0 +1,83 @@
+package responses
+
+import "tokogo/models"
+
+// maxCategoryDepth membatasi penelusuran parent agar data yang rusak tidak menyebabkan loop
+const maxCategoryDepth = 32
+
+// CategoryTreeResponse struct untuk satu node pada tree category
+type CategoryTreeResponse struct {
+	ID       uint                   `json:"id"`
+	Name     string                 `json:"name"`
+	Slug     string                 `json:"slug"`
+	ParentID *uint                  `json:"parent_id"`
+	Children []CategoryTreeResponse `json:"children"`
+}
+
+// CategoryBreadcrumbResponse struct untuk satu langkah breadcrumb category
+type CategoryBreadcrumbResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// BuildCategoryTree menyusun daftar category datar menjadi tree. Category yang parent-nya
+// tidak ditemukan ditampilkan sebagai root agar tidak hilang dari tree.
+func BuildCategoryTree(categories []models.Category) []CategoryTreeResponse {
+	known := make(map[uint]bool, len(categories))
+	for _, category := range categories {
+		known[category.ID] = true
+	}
+
+	children := make(map[uint][]models.Category)
+	var roots []models.Category
+	for _, category := range categories {
+		if category.ParentID == nil || !known[*category.ParentID] || *category.ParentID == category.ID {
+			roots = append(roots, category)
+			continue
+		}
+		children[*category.ParentID] = append(children[*category.ParentID], category)
+	}
+
+	visited := make(map[uint]bool, len(categories))
+	var build func(nodes []models.Category) []CategoryTreeResponse
+	build = func(nodes []models.Category) []CategoryTreeResponse {
+		tree := []CategoryTreeResponse{}
+		for _, node := range nodes {
+			if visited[node.ID] {
+				continue
+			}
+			visited[node.ID] = true
+			tree = append(tree, CategoryTreeResponse{
+				ID:       node.ID,
+				Name:     node.Name,
+				Slug:     node.Slug,
+				ParentID: node.ParentID,
+				Children: build(children[node.ID]),
+			})
+		}
+		return tree
+	}
+
+	return build(roots)
+}
+
+// BuildCategoryBreadcrumbs menyusun breadcrumb dari root sampai category product
+func BuildCategoryBreadcrumbs(category models.Category) []CategoryBreadcrumbResponse {
+	if category.ID == 0 {
+		return []CategoryBreadcrumbResponse{}
+	}
+
+	var path []CategoryBreadcrumbResponse
+	current := &category
+	for depth := 0; current != nil && depth < maxCategoryDepth; depth++ {
+		path = append(path, CategoryBreadcrumbResponse{ID: current.ID, Name: current.Name, Slug: current.Slug})
+		current = current.Parent
+	}
+
+	// Balik urutan menjadi root lebih dulu
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
@@ -7,6 +7,7 @@ type CategoryResponse struct {
 	ID        uint   `json:"id"`
 	Name      string `json:"name"`
 	Slug      string `json:"slug"`
+	ParentID  *uint  `json:"parent_id"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
@@ -17,6 +18,7 @@ type CategoryListResponse struct {
 	Total      int                `json:"total"`
 	Page       int                `json:"page"`
 	Limit      int                `json:"limit"`
+	NextCursor string             `json:"next_cursor,omitempty"`
 }
 
 // ConvertCategoryToResponse mengkonversi Category model ke CategoryResponse
@@ -25,6 +27,7 @@ func ConvertCategoryToResponse(category models.Category) CategoryResponse {
 		ID:        category.ID,
 		Name:      category.Name,
 		Slug:      category.Slug,
+		ParentID:  category.ParentID,
 		CreatedAt: category.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt: category.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
@@ -1,35 +1,59 @@
 package responses
 
-import "tokogo/models"
+import (
+	"math"
+	"time"
+	"tokogo/helpers"
+	"tokogo/models"
+)
 
 type CheckoutResponse struct {
-	TransactionID   uint                   `json:"transaction_id"`
-	UserID          uint                   `json:"user_id"`
-	Status          string                 `json:"status"`
-	TotalAmount     float64                `json:"total_amount"`
-	ShippingAddress string                 `json:"shipping_address"`
-	PaymentMethod   string                 `json:"payment_method"`
-	PaymentURL      string                 `json:"payment_url,omitempty"`
-	Items           []CheckoutItemResponse `json:"items"`
-	CreatedAt       string                 `json:"created_at"`
-	UpdatedAt       string                 `json:"updated_at"`
+	TransactionID        uint                               `json:"transaction_id"`
+	UserID               uint                               `json:"user_id"`
+	Status               string                             `json:"status"`
+	TotalAmount          float64                            `json:"total_amount"`
+	VoucherCode          string                             `json:"voucher_code,omitempty"`
+	DiscountAmount       float64                            `json:"discount_amount"`
+	PromotionDiscount    float64                            `json:"promotion_discount"`
+	ShippingAddress      string                             `json:"shipping_address"`
+	PaymentMethod        string                             `json:"payment_method"`
+	PaymentURL           string                             `json:"payment_url,omitempty"`
+	PaymentProof         string                             `json:"payment_proof,omitempty"`
+	PaymentRejectionNote string                             `json:"payment_rejection_note,omitempty"`
+	PaymentDueAt         *time.Time                         `json:"payment_due_at,omitempty"`
+	CancellationReason   string                             `json:"cancellation_reason,omitempty"`
+	Items                []CheckoutItemResponse             `json:"items"`
+	StatusHistory        []TransactionStatusHistoryResponse `json:"status_history,omitempty"`
+	CreatedAt            string                             `json:"created_at"`
+	UpdatedAt            string                             `json:"updated_at"`
 }
 
 type CheckoutItemResponse struct {
-	ProductID    uint    `json:"product_id"`
-	ProductName  string  `json:"product_name"`
-	ProductPrice float64 `json:"product_price"`
-	Quantity     int     `json:"quantity"`
-	Subtotal     float64 `json:"subtotal"`
+	ProductID      uint                      `json:"product_id"`
+	ProductName    string                    `json:"product_name"`
+	VariantID      *uint                     `json:"variant_id,omitempty"`
+	VariantSKU     string                    `json:"variant_sku,omitempty"`
+	VariantOptions map[string]string         `json:"variant_options,omitempty"`
+	ProductPrice   float64                   `json:"product_price"`
+	Quantity       int                       `json:"quantity"`
+	Discount       float64                   `json:"discount"`
+	Promotions     []models.AppliedPromotion `json:"promotions,omitempty"`
+	Subtotal       float64                   `json:"subtotal"`
 }
 
 type CheckoutSummaryResponse struct {
-	TotalItems      int     `json:"total_items"`
-	TotalAmount     float64 `json:"total_amount"`
-	ShippingCost    float64 `json:"shipping_cost"`
-	GrandTotal      float64 `json:"grand_total"`
-	PaymentMethod   string  `json:"payment_method"`
-	ShippingAddress string  `json:"shipping_address"`
+	Items             []CheckoutItemResponse    `json:"items"`
+	TotalItems        int                       `json:"total_items"`
+	TotalAmount       float64                   `json:"total_amount"`
+	Promotions        []models.AppliedPromotion `json:"promotions,omitempty"`
+	PromotionDiscount float64                   `json:"promotion_discount"`
+	ShippingCost      float64                   `json:"shipping_cost"`
+	VoucherCode       string                    `json:"voucher_code,omitempty"`
+	DiscountAmount    float64                   `json:"discount_amount"`
+	GrandTotal        float64                   `json:"grand_total"`
+	PaymentMethod     string                    `json:"payment_method"`
+	ShippingAddress   string                    `json:"shipping_address"`
+	ReservedUntil     string                    `json:"reserved_until,omitempty"`
 }
 
 func ConvertTransactionToCheckoutResponse(transaction models.Transaction) CheckoutResponse {
@@ -42,41 +66,94 @@ func ConvertTransactionToCheckoutResponse(transaction models.Transaction) Checko
 			ProductName:  detail.Product.Name,
 			ProductPrice: detail.Price,
 			Quantity:     detail.Quantity,
-			Subtotal:     float64(detail.Quantity) * detail.Price,
+			VariantID:    detail.VariantID,
+			Discount:     detail.Discount,
+			Promotions:   detail.Promotions,
+			Subtotal:     float64(detail.Quantity)*detail.Price - detail.Discount,
+		}
+		if detail.Variant != nil {
+			item.VariantSKU = detail.Variant.SKU
+			item.VariantOptions = detail.Variant.Options
 		}
 		items = append(items, item)
 		totalAmount += item.Subtotal
 	}
 
 	return CheckoutResponse{
-		TransactionID:   transaction.ID,
-		UserID:          transaction.UserID,
-		Status:          transaction.Status,
-		TotalAmount:     transaction.TotalAmount,
-		ShippingAddress: transaction.ShippingAddress,
-		PaymentMethod:   transaction.PaymentMethod,
-		PaymentURL:      transaction.PaymentURL,
-		Items:           items,
-		CreatedAt:       transaction.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:       transaction.UpdatedAt.Format("2006-01-02 15:04:05"),
+		TransactionID:        transaction.ID,
+		UserID:               transaction.UserID,
+		Status:               transaction.Status,
+		TotalAmount:          transaction.TotalAmount,
+		VoucherCode:          transaction.VoucherCode,
+		DiscountAmount:       transaction.DiscountAmount,
+		PromotionDiscount:    transaction.PromotionDiscount,
+		ShippingAddress:      transaction.ShippingAddress,
+		PaymentMethod:        transaction.PaymentMethod,
+		PaymentURL:           transaction.PaymentURL,
+		PaymentProof:         helpers.SignedURL(transaction.PaymentProof),
+		PaymentRejectionNote: transaction.PaymentRejectionNote,
+		PaymentDueAt:         transaction.PaymentDueAt,
+		CancellationReason:   transaction.CancellationReason,
+		Items:                items,
+		StatusHistory:        ConvertStatusHistoriesToResponse(transaction.StatusHistories),
+		CreatedAt:            transaction.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:            transaction.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
 }
 
-func CreateCheckoutSummaryResponse(carts []models.Cart, shippingCost float64, paymentMethod, shippingAddress string) CheckoutSummaryResponse {
+// CreateCheckoutSummaryResponse membuat ringkasan checkout. linePromotions sejajar dengan carts
+// dan berisi promosi otomatis yang berlaku pada setiap item.
+func CreateCheckoutSummaryResponse(carts []models.Cart, linePromotions [][]models.AppliedPromotion, shippingCost float64, paymentMethod, shippingAddress string, now time.Time) CheckoutSummaryResponse {
+	var items []CheckoutItemResponse
+	var promotions []models.AppliedPromotion
+	promotionIndex := make(map[uint]int)
 	var totalItems int
-	var totalAmount float64
+	var totalAmount, promotionDiscount float64
+
+	for i, cart := range carts {
+		price := cart.UnitPrice(now)
+		item := CheckoutItemResponse{
+			ProductID:    cart.ProductID,
+			ProductName:  cart.Product.Name,
+			VariantID:    cart.VariantID,
+			ProductPrice: price,
+			Quantity:     cart.Quantity,
+			Promotions:   linePromotions[i],
+		}
+		if cart.Variant != nil {
+			item.VariantSKU = cart.Variant.SKU
+			item.VariantOptions = cart.Variant.Options
+		}
 
-	for _, cart := range carts {
+		// Promosi yang sama pada beberapa item dijumlahkan untuk ringkasan
+		for _, applied := range linePromotions[i] {
+			item.Discount += applied.Discount
+			if idx, ok := promotionIndex[applied.PromotionID]; ok {
+				promotions[idx].Discount = math.Round((promotions[idx].Discount+applied.Discount)*100) / 100
+				continue
+			}
+			promotionIndex[applied.PromotionID] = len(promotions)
+			promotions = append(promotions, applied)
+		}
+		item.Discount = math.Round(item.Discount*100) / 100
+		item.Subtotal = float64(cart.Quantity)*price - item.Discount
+
+		items = append(items, item)
 		totalItems += cart.Quantity
-		totalAmount += float64(cart.Quantity) * cart.Product.SellingPrice
+		totalAmount += float64(cart.Quantity) * price
+		promotionDiscount += item.Discount
 	}
+	promotionDiscount = math.Round(promotionDiscount*100) / 100
 
 	return CheckoutSummaryResponse{
-		TotalItems:      totalItems,
-		TotalAmount:     totalAmount,
-		ShippingCost:    shippingCost,
-		GrandTotal:      totalAmount + shippingCost,
-		PaymentMethod:   paymentMethod,
-		ShippingAddress: shippingAddress,
+		Items:             items,
+		TotalItems:        totalItems,
+		TotalAmount:       totalAmount,
+		Promotions:        promotions,
+		PromotionDiscount: promotionDiscount,
+		ShippingCost:      shippingCost,
+		GrandTotal:        totalAmount - promotionDiscount + shippingCost,
+		PaymentMethod:     paymentMethod,
+		ShippingAddress:   shippingAddress,
 	}
 }
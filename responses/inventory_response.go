@@ -0,0 +1,69 @@
+package responses
+
+import "tokogo/models"
+
+// StockMovementResponse struct untuk response satu baris ledger stok
+type StockMovementResponse struct {
+	ID          uint   `json:"id"`
+	ProductID   uint   `json:"product_id"`
+	VariantID   *uint  `json:"variant_id,omitempty"`
+	Delta       int    `json:"delta"`
+	Reason      string `json:"reason"`
+	ReferenceID *uint  `json:"reference_id,omitempty"`
+	ActorID     *uint  `json:"actor_id,omitempty"`
+	Note        string `json:"note,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// StockMovementListResponse struct untuk response list ledger stok
+type StockMovementListResponse struct {
+	Movements []StockMovementResponse `json:"movements"`
+	Total     int64                   `json:"total"`
+	Page      int                     `json:"page"`
+	Limit     int                     `json:"limit"`
+}
+
+// StockReconciliationResponse struct untuk hasil rekonsiliasi stok dengan ledger
+type StockReconciliationResponse struct {
+	ProductID    uint                                 `json:"product_id"`
+	ProductName  string                               `json:"product_name"`
+	CurrentStock int                                  `json:"current_stock"`
+	LedgerStock  int                                  `json:"ledger_stock"`
+	Difference   int                                  `json:"difference"`
+	InSync       bool                                 `json:"in_sync"`
+	Variants     []VariantStockReconciliationResponse `json:"variants,omitempty"`
+}
+
+// VariantStockReconciliationResponse struct untuk hasil rekonsiliasi stok satu variant
+type VariantStockReconciliationResponse struct {
+	VariantID    uint   `json:"variant_id"`
+	SKU          string `json:"sku"`
+	CurrentStock int    `json:"current_stock"`
+	LedgerStock  int    `json:"ledger_stock"`
+	Difference   int    `json:"difference"`
+	InSync       bool   `json:"in_sync"`
+}
+
+// ConvertStockMovementToResponse mengkonversi StockMovement model ke StockMovementResponse
+func ConvertStockMovementToResponse(movement models.StockMovement) StockMovementResponse {
+	return StockMovementResponse{
+		ID:          movement.ID,
+		ProductID:   movement.ProductID,
+		VariantID:   movement.VariantID,
+		Delta:       movement.Delta,
+		Reason:      movement.Reason,
+		ReferenceID: movement.ReferenceID,
+		ActorID:     movement.ActorID,
+		Note:        movement.Note,
+		CreatedAt:   movement.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// ConvertStockMovementsToResponse mengkonversi slice StockMovement ke slice StockMovementResponse
+func ConvertStockMovementsToResponse(movements []models.StockMovement) []StockMovementResponse {
+	var responses []StockMovementResponse
+	for _, movement := range movements {
+		responses = append(responses, ConvertStockMovementToResponse(movement))
+	}
+	return responses
+}
@@ -0,0 +1,53 @@
+package responses
+
+import (
+	"time"
+	"tokogo/models"
+)
+
+// PriceHistoryResponse struct untuk response satu catatan perubahan harga
+type PriceHistoryResponse struct {
+	ID             uint       `json:"id"`
+	ProductID      uint       `json:"product_id"`
+	PurchasePrice  float64    `json:"purchase_price"`
+	SellingPrice   float64    `json:"selling_price"`
+	CompareAtPrice *float64   `json:"compare_at_price"`
+	SalePrice      *float64   `json:"sale_price"`
+	SaleStartsAt   *time.Time `json:"sale_starts_at"`
+	SaleEndsAt     *time.Time `json:"sale_ends_at"`
+	ActorID        *uint      `json:"actor_id,omitempty"`
+	CreatedAt      string     `json:"created_at"`
+}
+
+// PriceHistoryListResponse struct untuk response list riwayat harga
+type PriceHistoryListResponse struct {
+	Histories []PriceHistoryResponse `json:"histories"`
+	Total     int64                  `json:"total"`
+	Page      int                    `json:"page"`
+	Limit     int                    `json:"limit"`
+}
+
+// ConvertPriceHistoryToResponse mengkonversi PriceHistory model ke PriceHistoryResponse
+func ConvertPriceHistoryToResponse(history models.PriceHistory) PriceHistoryResponse {
+	return PriceHistoryResponse{
+		ID:             history.ID,
+		ProductID:      history.ProductID,
+		PurchasePrice:  history.PurchasePrice,
+		SellingPrice:   history.SellingPrice,
+		CompareAtPrice: history.CompareAtPrice,
+		SalePrice:      history.SalePrice,
+		SaleStartsAt:   history.SaleStartsAt,
+		SaleEndsAt:     history.SaleEndsAt,
+		ActorID:        history.ActorID,
+		CreatedAt:      history.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// ConvertPriceHistoriesToResponse mengkonversi slice PriceHistory ke slice PriceHistoryResponse
+func ConvertPriceHistoriesToResponse(histories []models.PriceHistory) []PriceHistoryResponse {
+	var responses []PriceHistoryResponse
+	for _, history := range histories {
+		responses = append(responses, ConvertPriceHistoryToResponse(history))
+	}
+	return responses
+}
@@ -0,0 +1,21 @@
+package responses
+
+// CategoryFacetResponse struct untuk jumlah product per category
+type CategoryFacetResponse struct {
+	CategoryID   uint   `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Count        int64  `json:"count"`
+}
+
+// PriceFacetResponse struct untuk jumlah product dalam satu rentang harga
+type PriceFacetResponse struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int64    `json:"count"`
+}
+
+// ProductFacetsResponse struct untuk ringkasan filter sidebar storefront
+type ProductFacetsResponse struct {
+	Categories  []CategoryFacetResponse `json:"categories"`
+	PriceRanges []PriceFacetResponse    `json:"price_ranges"`
+}
@@ -0,0 +1,83 @@
+package responses
+
+import (
+	"tokogo/helpers"
+	"tokogo/models"
+)
+
+// ProductImageResponse struct untuk response satu gambar galeri product
+type ProductImageResponse struct {
+	ID           uint   `json:"id"`
+	ImageURL     string `json:"image_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	MediumURL    string `json:"medium_url"`
+	LargeURL     string `json:"large_url"`
+	Position     int    `json:"position"`
+	IsPrimary    bool   `json:"is_primary"`
+}
+
+// ImageRenditionsResponse struct untuk URL gambar utama product dalam berbagai ukuran
+type ImageRenditionsResponse struct {
+	Original  string `json:"original"`
+	Thumbnail string `json:"thumbnail"`
+	Medium    string `json:"medium"`
+	Large     string `json:"large"`
+}
+
+// ConvertProductImageToResponse mengkonversi ProductImage model ke ProductImageResponse
+func ConvertProductImageToResponse(image models.ProductImage) ProductImageResponse {
+	// Gambar lama yang diupload sebelum ada rendition memakai gambar asli untuk semua ukuran
+	return ProductImageResponse{
+		ID:           image.ID,
+		ImageURL:     helpers.PublicURL(image.ImageURL),
+		ThumbnailURL: helpers.PublicURL(orDefault(image.ThumbnailURL, image.ImageURL)),
+		MediumURL:    helpers.PublicURL(orDefault(image.MediumURL, image.ImageURL)),
+		LargeURL:     helpers.PublicURL(orDefault(image.LargeURL, image.ImageURL)),
+		Position:     image.Position,
+		IsPrimary:    image.IsPrimary,
+	}
+}
+
+// ConvertPrimaryImageToRenditions mengambil rendition gambar primary product.
+// Product tanpa galeri memakai ImageURL untuk semua ukuran.
+func ConvertPrimaryImageToRenditions(product models.Product) *ImageRenditionsResponse {
+	for _, image := range product.Images {
+		if image.IsPrimary {
+			response := ConvertProductImageToResponse(image)
+			return &ImageRenditionsResponse{
+				Original:  response.ImageURL,
+				Thumbnail: response.ThumbnailURL,
+				Medium:    response.MediumURL,
+				Large:     response.LargeURL,
+			}
+		}
+	}
+
+	if product.ImageURL == "" {
+		return nil
+	}
+	imageURL := helpers.PublicURL(product.ImageURL)
+	return &ImageRenditionsResponse{
+		Original:  imageURL,
+		Thumbnail: imageURL,
+		Medium:    imageURL,
+		Large:     imageURL,
+	}
+}
+
+// orDefault mengembalikan value, atau fallback bila value kosong
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// ConvertProductImagesToResponse mengkonversi slice ProductImage ke slice ProductImageResponse
+func ConvertProductImagesToResponse(images []models.ProductImage) []ProductImageResponse {
+	var responses []ProductImageResponse
+	for _, image := range images {
+		responses = append(responses, ConvertProductImageToResponse(image))
+	}
+	return responses
+}
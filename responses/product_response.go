@@ -1,42 +1,90 @@
 package responses
 
-import "tokogo/models"
+import (
+	"math"
+	"time"
+	"tokogo/helpers"
+	"tokogo/models"
+)
 
 type ProductResponse struct {
-	ID            uint    `json:"id"`
-	Name          string  `json:"name"`
-	Description   string  `json:"description"`
-	PurchasePrice float64 `json:"purchase_price"`
-	SellingPrice  float64 `json:"selling_price"`
-	Stock         int     `json:"stock"`
-	CategoryID    uint    `json:"category_id"`
-	CategoryName  string  `json:"category_name"`
-	ImagePath     string  `json:"image_url"`
-	CreatedAt     string  `json:"created_at"`
-	UpdatedAt     string  `json:"updated_at"`
+	ID                uint                         `json:"id"`
+	Name              string                       `json:"name"`
+	Slug              string                       `json:"slug"`
+	Description       string                       `json:"description"`
+	PurchasePrice     float64                      `json:"purchase_price"`
+	SellingPrice      float64                      `json:"selling_price"`
+	CompareAtPrice    *float64                     `json:"compare_at_price"`
+	SalePrice         *float64                     `json:"sale_price"`
+	SaleStartsAt      *time.Time                   `json:"sale_starts_at"`
+	SaleEndsAt        *time.Time                   `json:"sale_ends_at"`
+	EffectivePrice    float64                      `json:"effective_price"`
+	IsOnSale          bool                         `json:"is_on_sale"`
+	Stock             int                          `json:"stock"`
+	LowStockThreshold int                          `json:"low_stock_threshold"`
+	IsLowStock        bool                         `json:"is_low_stock"`
+	CategoryID        uint                         `json:"category_id"`
+	CategoryName      string                       `json:"category_name"`
+	Breadcrumbs       []CategoryBreadcrumbResponse `json:"breadcrumbs"`
+	Status            string                       `json:"status"`
+	PublishAt         *time.Time                   `json:"publish_at"`
+	UnpublishAt       *time.Time                   `json:"unpublish_at"`
+	IsVisible         bool                         `json:"is_visible"`
+	ImagePath         string                       `json:"image_url"`
+	ImageRenditions   *ImageRenditionsResponse     `json:"image_renditions,omitempty"`
+	Images            []ProductImageResponse       `json:"images"`
+	Variants          []ProductVariantResponse     `json:"variants,omitempty"`
+	VariantOptions    map[string][]string          `json:"variant_options,omitempty"`
+	CreatedAt         string                       `json:"created_at"`
+	UpdatedAt         string                       `json:"updated_at"`
+	ArchivedAt        string                       `json:"archived_at,omitempty"`
 }
 
 type ProductListResponse struct {
-	Products []ProductResponse `json:"products"`
-	Total    int64             `json:"total"`
-	Page     int               `json:"page"`
-	Limit    int               `json:"limit"`
+	Products   []ProductResponse `json:"products"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	NextCursor string            `json:"next_cursor,omitempty"`
 }
 
 func ConvertProductToResponse(product models.Product) ProductResponse {
-	return ProductResponse{
-		ID:            product.ID,
-		Name:          product.Name,
-		Description:   product.Description,
-		PurchasePrice: product.PurchasePrice,
-		SellingPrice:  product.SellingPrice,
-		Stock:         product.Stock,
-		CategoryID:    product.CategoryID,
-		CategoryName:  product.Category.Name,
-		ImagePath:     product.ImageURL,
-		CreatedAt:     product.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:     product.UpdatedAt.Format("2006-01-02 15:04:05"),
+	now := time.Now()
+	response := ProductResponse{
+		ID:                product.ID,
+		Name:              product.Name,
+		Slug:              product.Slug,
+		Description:       product.Description,
+		PurchasePrice:     product.PurchasePrice,
+		SellingPrice:      product.SellingPrice,
+		CompareAtPrice:    product.CompareAtPrice,
+		SalePrice:         product.SalePrice,
+		SaleStartsAt:      product.SaleStartsAt,
+		SaleEndsAt:        product.SaleEndsAt,
+		EffectivePrice:    product.EffectivePrice(now),
+		IsOnSale:          product.IsOnSale(now),
+		Stock:             product.Stock,
+		LowStockThreshold: product.LowStockThreshold,
+		IsLowStock:        product.IsLowStock(),
+		CategoryID:        product.CategoryID,
+		CategoryName:      product.Category.Name,
+		Breadcrumbs:       BuildCategoryBreadcrumbs(product.Category),
+		Status:            product.Status,
+		PublishAt:         product.PublishAt,
+		UnpublishAt:       product.UnpublishAt,
+		IsVisible:         product.IsVisible(now),
+		ImagePath:         helpers.PublicURL(product.ImageURL),
+		ImageRenditions:   ConvertPrimaryImageToRenditions(product),
+		Images:            ConvertProductImagesToResponse(product.Images),
+		Variants:          ConvertProductVariantsToResponse(product.Variants, product),
+		VariantOptions:    BuildVariantOptionMatrix(product.Variants),
+		CreatedAt:         product.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:         product.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
+	if product.IsArchived() {
+		response.ArchivedAt = product.DeletedAt.Time.Format("2006-01-02 15:04:05")
+	}
+	return response
 }
 
 func ConvertProductsToResponse(products []models.Product) []ProductResponse {
@@ -47,40 +95,75 @@ func ConvertProductsToResponse(products []models.Product) []ProductResponse {
 	return responses
 }
 
-// PublicProductResponse untuk response public (tanpa purchase_price)
+// PublicProductResponse untuk response public (tanpa purchase_price).
+// SellingPrice adalah harga yang dibayar saat ini, OriginalPrice adalah harga coret jika ada.
 type PublicProductResponse struct {
-	ID           uint    `json:"id"`
-	Name         string  `json:"name"`
-	Description  string  `json:"description"`
-	SellingPrice float64 `json:"selling_price"`
-	Stock        int     `json:"stock"`
-	CategoryID   uint    `json:"category_id"`
-	CategoryName string  `json:"category_name"`
-	ImagePath    string  `json:"image_url"`
-	CreatedAt    string  `json:"created_at"`
-	UpdatedAt    string  `json:"updated_at"`
+	ID              uint                           `json:"id"`
+	Name            string                         `json:"name"`
+	Slug            string                         `json:"slug"`
+	Description     string                         `json:"description"`
+	SellingPrice    float64                        `json:"selling_price"`
+	OriginalPrice   *float64                       `json:"original_price,omitempty"`
+	DiscountPercent int                            `json:"discount_percent,omitempty"`
+	IsOnSale        bool                           `json:"is_on_sale"`
+	SaleEndsAt      *time.Time                     `json:"sale_ends_at,omitempty"`
+	Stock           int                            `json:"stock"`
+	CategoryID      uint                           `json:"category_id"`
+	CategoryName    string                         `json:"category_name"`
+	Breadcrumbs     []CategoryBreadcrumbResponse   `json:"breadcrumbs"`
+	ImagePath       string                         `json:"image_url"`
+	ImageRenditions *ImageRenditionsResponse       `json:"image_renditions,omitempty"`
+	Images          []ProductImageResponse         `json:"images"`
+	Variants        []PublicProductVariantResponse `json:"variants,omitempty"`
+	VariantOptions  map[string][]string            `json:"variant_options,omitempty"`
+	Score           float64                        `json:"score,omitempty"`
+	Highlights      map[string]string              `json:"highlights,omitempty"`
+	CreatedAt       string                         `json:"created_at"`
+	UpdatedAt       string                         `json:"updated_at"`
 }
 
 type PublicProductListResponse struct {
-	Products []PublicProductResponse `json:"products"`
-	Total    int64                   `json:"total"`
-	Page     int                     `json:"page"`
-	Limit    int                     `json:"limit"`
+	Products   []PublicProductResponse `json:"products"`
+	Query      string                  `json:"query,omitempty"`
+	Facets     *ProductFacetsResponse  `json:"facets,omitempty"`
+	Total      int64                   `json:"total"`
+	Page       int                     `json:"page"`
+	Limit      int                     `json:"limit"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
 }
 
 func ConvertProductToPublicResponse(product models.Product) PublicProductResponse {
-	return PublicProductResponse{
-		ID:           product.ID,
-		Name:         product.Name,
-		Description:  product.Description,
-		SellingPrice: product.SellingPrice,
-		Stock:        product.Stock,
-		CategoryID:   product.CategoryID,
-		CategoryName: product.Category.Name,
-		ImagePath:    product.ImageURL,
-		CreatedAt:    product.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:    product.UpdatedAt.Format("2006-01-02 15:04:05"),
+	now := time.Now()
+	price := product.EffectivePrice(now)
+	originalPrice := product.CompareAtPriceAt(now)
+
+	response := PublicProductResponse{
+		ID:              product.ID,
+		Name:            product.Name,
+		Slug:            product.Slug,
+		Description:     product.Description,
+		SellingPrice:    price,
+		OriginalPrice:   originalPrice,
+		IsOnSale:        product.IsOnSale(now),
+		Stock:           product.Stock,
+		CategoryID:      product.CategoryID,
+		CategoryName:    product.Category.Name,
+		Breadcrumbs:     BuildCategoryBreadcrumbs(product.Category),
+		ImagePath:       helpers.PublicURL(product.ImageURL),
+		ImageRenditions: ConvertPrimaryImageToRenditions(product),
+		Images:          ConvertProductImagesToResponse(product.Images),
+		Variants:        ConvertProductVariantsToPublicResponse(product.Variants, product),
+		VariantOptions:  BuildVariantOptionMatrix(product.Variants),
+		CreatedAt:       product.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:       product.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if originalPrice != nil {
+		response.DiscountPercent = int(math.Round((*originalPrice - price) / *originalPrice * 100))
+	}
+	if response.IsOnSale {
+		response.SaleEndsAt = product.SaleEndsAt
 	}
+	return response
 }
 
 func ConvertProductsToPublicResponse(products []models.Product) []PublicProductResponse {
@@ -0,0 +1,95 @@
+package responses
+
+import (
+	"sort"
+	"time"
+	"tokogo/models"
+)
+
+// ProductVariantResponse struct untuk response variant di endpoint admin
+type ProductVariantResponse struct {
+	ID            uint              `json:"id"`
+	ProductID     uint              `json:"product_id"`
+	SKU           string            `json:"sku"`
+	Options       map[string]string `json:"options"`
+	PriceOverride *float64          `json:"price_override"`
+	Price         float64           `json:"price"`
+	Stock         int               `json:"stock"`
+	CreatedAt     string            `json:"created_at"`
+	UpdatedAt     string            `json:"updated_at"`
+}
+
+// PublicProductVariantResponse struct untuk response variant di endpoint public
+type PublicProductVariantResponse struct {
+	ID      uint              `json:"id"`
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options"`
+	Price   float64           `json:"price"`
+	Stock   int               `json:"stock"`
+}
+
+// ConvertProductVariantToResponse mengkonversi ProductVariant model ke ProductVariantResponse
+func ConvertProductVariantToResponse(variant models.ProductVariant, product models.Product) ProductVariantResponse {
+	return ProductVariantResponse{
+		ID:            variant.ID,
+		ProductID:     variant.ProductID,
+		SKU:           variant.SKU,
+		Options:       variant.Options,
+		PriceOverride: variant.PriceOverride,
+		Price:         variant.EffectivePrice(product, time.Now()),
+		Stock:         variant.Stock,
+		CreatedAt:     variant.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:     variant.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// ConvertProductVariantsToResponse mengkonversi slice ProductVariant ke slice ProductVariantResponse
+func ConvertProductVariantsToResponse(variants []models.ProductVariant, product models.Product) []ProductVariantResponse {
+	var responses []ProductVariantResponse
+	for _, variant := range variants {
+		responses = append(responses, ConvertProductVariantToResponse(variant, product))
+	}
+	return responses
+}
+
+// ConvertProductVariantsToPublicResponse mengkonversi slice ProductVariant ke slice PublicProductVariantResponse
+func ConvertProductVariantsToPublicResponse(variants []models.ProductVariant, product models.Product) []PublicProductVariantResponse {
+	var responses []PublicProductVariantResponse
+	for _, variant := range variants {
+		responses = append(responses, PublicProductVariantResponse{
+			ID:      variant.ID,
+			SKU:     variant.SKU,
+			Options: variant.Options,
+			Price:   variant.EffectivePrice(product, time.Now()),
+			Stock:   variant.Stock,
+		})
+	}
+	return responses
+}
+
+// BuildVariantOptionMatrix mengumpulkan nilai unik setiap opsi variant, misalnya
+// {"size": ["L", "M"], "color": ["black", "white"]}
+func BuildVariantOptionMatrix(variants []models.ProductVariant) map[string][]string {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]map[string]bool)
+	for _, variant := range variants {
+		for name, value := range variant.Options {
+			if seen[name] == nil {
+				seen[name] = make(map[string]bool)
+			}
+			seen[name][value] = true
+		}
+	}
+
+	matrix := make(map[string][]string, len(seen))
+	for name, values := range seen {
+		for value := range values {
+			matrix[name] = append(matrix[name], value)
+		}
+		sort.Strings(matrix[name])
+	}
+	return matrix
+}
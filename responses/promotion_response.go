@@ -0,0 +1,94 @@
+package responses
+
+import (
+	"time"
+	"tokogo/models"
+)
+
+// PromotionItemResponse struct untuk product yang ikut promosi
+type PromotionItemResponse struct {
+	ProductID   uint   `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+}
+
+// PromotionTierResponse struct untuk tingkat diskon quantity_tier
+type PromotionTierResponse struct {
+	MinQuantity     int     `json:"min_quantity"`
+	DiscountPercent float64 `json:"discount_percent"`
+}
+
+// PromotionResponse struct untuk response promosi di endpoint admin
+type PromotionResponse struct {
+	ID                 uint                    `json:"id"`
+	Name               string                  `json:"name"`
+	Description        string                  `json:"description"`
+	Type               string                  `json:"type"`
+	Priority           int                     `json:"priority"`
+	Exclusive          bool                    `json:"exclusive"`
+	BuyQuantity        int                     `json:"buy_quantity,omitempty"`
+	GetQuantity        int                     `json:"get_quantity,omitempty"`
+	GetDiscountPercent float64                 `json:"get_discount_percent,omitempty"`
+	BundlePrice        *float64                `json:"bundle_price,omitempty"`
+	StartsAt           *time.Time              `json:"starts_at"`
+	EndsAt             *time.Time              `json:"ends_at"`
+	IsActive           bool                    `json:"is_active"`
+	Items              []PromotionItemResponse `json:"items"`
+	Tiers              []PromotionTierResponse `json:"tiers"`
+	CreatedAt          string                  `json:"created_at"`
+	UpdatedAt          string                  `json:"updated_at"`
+}
+
+// PromotionListResponse struct untuk response list promosi
+type PromotionListResponse struct {
+	Promotions []PromotionResponse `json:"promotions"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+}
+
+// ConvertPromotionToResponse mengkonversi Promotion model ke PromotionResponse
+func ConvertPromotionToResponse(promotion models.Promotion) PromotionResponse {
+	response := PromotionResponse{
+		ID:                 promotion.ID,
+		Name:               promotion.Name,
+		Description:        promotion.Description,
+		Type:               promotion.Type,
+		Priority:           promotion.Priority,
+		Exclusive:          promotion.Exclusive,
+		BuyQuantity:        promotion.BuyQuantity,
+		GetQuantity:        promotion.GetQuantity,
+		GetDiscountPercent: promotion.GetDiscountPercent,
+		BundlePrice:        promotion.BundlePrice,
+		StartsAt:           promotion.StartsAt,
+		EndsAt:             promotion.EndsAt,
+		IsActive:           promotion.IsActive,
+		Items:              []PromotionItemResponse{},
+		Tiers:              []PromotionTierResponse{},
+		CreatedAt:          promotion.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:          promotion.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	for _, item := range promotion.Items {
+		response.Items = append(response.Items, PromotionItemResponse{
+			ProductID:   item.ProductID,
+			ProductName: item.Product.Name,
+			Quantity:    item.Quantity,
+		})
+	}
+	for _, tier := range promotion.Tiers {
+		response.Tiers = append(response.Tiers, PromotionTierResponse{
+			MinQuantity:     tier.MinQuantity,
+			DiscountPercent: tier.DiscountPercent,
+		})
+	}
+	return response
+}
+
+// ConvertPromotionsToResponse mengkonversi slice Promotion ke slice PromotionResponse
+func ConvertPromotionsToResponse(promotions []models.Promotion) []PromotionResponse {
+	var responses []PromotionResponse
+	for _, promotion := range promotions {
+		responses = append(responses, ConvertPromotionToResponse(promotion))
+	}
+	return responses
+}
@@ -1,31 +1,53 @@
 package responses
 
-import "time"
+import (
+	"time"
+	"tokogo/models"
+)
 
 // TransactionResponse represents the response structure for transaction
 type TransactionResponse struct {
-	ID          int64                       `json:"id"`
-	UserID      int64                       `json:"user_id"`
-	UserName    string                      `json:"user_name"`
-	UserEmail   string                      `json:"user_email"`
-	Status      string                      `json:"status"`
-	TotalAmount float64                     `json:"total_amount"`
-	PaymentURL  string                      `json:"payment_url,omitempty"`
-	CreatedAt   time.Time                   `json:"created_at"`
-	UpdatedAt   time.Time                   `json:"updated_at"`
-	Details     []TransactionDetailResponse `json:"details,omitempty"`
+	ID                   int64                              `json:"id"`
+	UserID               int64                              `json:"user_id"`
+	UserName             string                             `json:"user_name"`
+	UserEmail            string                             `json:"user_email"`
+	Status               string                             `json:"status"`
+	TotalAmount          float64                            `json:"total_amount"`
+	VoucherCode          string                             `json:"voucher_code,omitempty"`
+	DiscountAmount       float64                            `json:"discount_amount"`
+	PromotionDiscount    float64                            `json:"promotion_discount"`
+	PaymentMethod        string                             `json:"payment_method,omitempty"`
+	PaymentURL           string                             `json:"payment_url,omitempty"`
+	PaymentProof         string                             `json:"payment_proof,omitempty"`
+	PaymentRejectionNote string                             `json:"payment_rejection_note,omitempty"`
+	PaymentDueAt         *time.Time                         `json:"payment_due_at,omitempty"`
+	CancellationReason   string                             `json:"cancellation_reason,omitempty"`
+	RefundStatus         string                             `json:"refund_status,omitempty"`
+	RefundError          string                             `json:"refund_error,omitempty"`
+	RefundStartedAt      *time.Time                         `json:"refund_started_at,omitempty"`
+	RefundedAt           *time.Time                         `json:"refunded_at,omitempty"`
+	LatePaymentAt        *time.Time                         `json:"late_payment_at,omitempty"`
+	CreatedAt            time.Time                          `json:"created_at"`
+	UpdatedAt            time.Time                          `json:"updated_at"`
+	Details              []TransactionDetailResponse        `json:"details,omitempty"`
+	StatusHistory        []TransactionStatusHistoryResponse `json:"status_history,omitempty"`
 }
 
 // TransactionDetailResponse represents the response structure for transaction detail
 type TransactionDetailResponse struct {
-	ID            int64   `json:"id"`
-	TransactionID int64   `json:"transaction_id"`
-	ProductID     int64   `json:"product_id"`
-	ProductName   string  `json:"product_name"`
-	ProductImage  string  `json:"product_image,omitempty"`
-	Quantity      int     `json:"quantity"`
-	Price         float64 `json:"price"`
-	Subtotal      float64 `json:"subtotal"`
+	ID             int64                     `json:"id"`
+	TransactionID  int64                     `json:"transaction_id"`
+	ProductID      int64                     `json:"product_id"`
+	ProductName    string                    `json:"product_name"`
+	ProductImage   string                    `json:"product_image,omitempty"`
+	VariantID      *uint                     `json:"variant_id,omitempty"`
+	VariantSKU     string                    `json:"variant_sku,omitempty"`
+	VariantOptions map[string]string         `json:"variant_options,omitempty"`
+	Quantity       int                       `json:"quantity"`
+	Price          float64                   `json:"price"`
+	Discount       float64                   `json:"discount"`
+	Promotions     []models.AppliedPromotion `json:"promotions,omitempty"`
+	Subtotal       float64                   `json:"subtotal"`
 }
 
 // TransactionListResponse represents the response structure for transaction list
@@ -35,6 +57,7 @@ type TransactionListResponse struct {
 	Page         int                   `json:"page"`
 	Limit        int                   `json:"limit"`
 	TotalPages   int                   `json:"total_pages"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
 }
 
 // TransactionStatusResponse represents the response structure for transaction status update
@@ -42,3 +65,29 @@ type TransactionStatusResponse struct {
 	Message string              `json:"message"`
 	Data    TransactionResponse `json:"data"`
 }
+
+// TransactionStatusHistoryResponse represents one entry of the transaction status history
+type TransactionStatusHistoryResponse struct {
+	FromStatus string `json:"from_status,omitempty"`
+	ToStatus   string `json:"to_status"`
+	ActorID    *uint  `json:"actor_id,omitempty"`
+	ActorRole  string `json:"actor_role"`
+	Note       string `json:"note,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// ConvertStatusHistoriesToResponse converts status history models to response format
+func ConvertStatusHistoriesToResponse(histories []models.TransactionStatusHistory) []TransactionStatusHistoryResponse {
+	var responses []TransactionStatusHistoryResponse
+	for _, history := range histories {
+		responses = append(responses, TransactionStatusHistoryResponse{
+			FromStatus: history.FromStatus,
+			ToStatus:   history.ToStatus,
+			ActorID:    history.ActorID,
+			ActorRole:  history.ActorRole,
+			Note:       history.Note,
+			CreatedAt:  history.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return responses
+}
@@ -14,10 +14,11 @@ type UserManagementResponse struct {
 
 // UserListResponse struct untuk response list users
 type UserListResponse struct {
-	Users []UserManagementResponse `json:"users"`
-	Total int                      `json:"total"`
-	Page  int                      `json:"page"`
-	Limit int                      `json:"limit"`
+	Users      []UserManagementResponse `json:"users"`
+	Total      int                      `json:"total"`
+	Page       int                      `json:"page"`
+	Limit      int                      `json:"limit"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
 }
 
 // ConvertUserToManagementResponse mengkonversi model User ke UserManagementResponse
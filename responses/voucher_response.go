@@ -0,0 +1,86 @@
+package responses
+
+import (
+	"time"
+	"tokogo/models"
+)
+
+// VoucherProductResponse struct untuk product yang dibatasi voucher
+type VoucherProductResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// VoucherCategoryResponse struct untuk category yang dibatasi voucher
+type VoucherCategoryResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// VoucherResponse struct untuk response voucher di endpoint admin
+type VoucherResponse struct {
+	ID           uint                      `json:"id"`
+	Code         string                    `json:"code"`
+	Description  string                    `json:"description"`
+	Type         string                    `json:"type"`
+	Value        float64                   `json:"value"`
+	MinSpend     float64                   `json:"min_spend"`
+	MaxDiscount  *float64                  `json:"max_discount"`
+	UsageLimit   *int                      `json:"usage_limit"`
+	PerUserLimit *int                      `json:"per_user_limit"`
+	UsedCount    int                       `json:"used_count"`
+	StartsAt     *time.Time                `json:"starts_at"`
+	EndsAt       *time.Time                `json:"ends_at"`
+	IsActive     bool                      `json:"is_active"`
+	Categories   []VoucherCategoryResponse `json:"categories"`
+	Products     []VoucherProductResponse  `json:"products"`
+	CreatedAt    string                    `json:"created_at"`
+	UpdatedAt    string                    `json:"updated_at"`
+}
+
+// VoucherListResponse struct untuk response list voucher
+type VoucherListResponse struct {
+	Vouchers []VoucherResponse `json:"vouchers"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	Limit    int               `json:"limit"`
+}
+
+// ConvertVoucherToResponse mengkonversi Voucher model ke VoucherResponse
+func ConvertVoucherToResponse(voucher models.Voucher) VoucherResponse {
+	response := VoucherResponse{
+		ID:           voucher.ID,
+		Code:         voucher.Code,
+		Description:  voucher.Description,
+		Type:         voucher.Type,
+		Value:        voucher.Value,
+		MinSpend:     voucher.MinSpend,
+		MaxDiscount:  voucher.MaxDiscount,
+		UsageLimit:   voucher.UsageLimit,
+		PerUserLimit: voucher.PerUserLimit,
+		UsedCount:    voucher.UsedCount,
+		StartsAt:     voucher.StartsAt,
+		EndsAt:       voucher.EndsAt,
+		IsActive:     voucher.IsActive,
+		Categories:   []VoucherCategoryResponse{},
+		Products:     []VoucherProductResponse{},
+		CreatedAt:    voucher.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:    voucher.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	for _, category := range voucher.Categories {
+		response.Categories = append(response.Categories, VoucherCategoryResponse{ID: category.ID, Name: category.Name})
+	}
+	for _, product := range voucher.Products {
+		response.Products = append(response.Products, VoucherProductResponse{ID: product.ID, Name: product.Name})
+	}
+	return response
+}
+
+// ConvertVouchersToResponse mengkonversi slice Voucher ke slice VoucherResponse
+func ConvertVouchersToResponse(vouchers []models.Voucher) []VoucherResponse {
+	var responses []VoucherResponse
+	for _, voucher := range vouchers {
+		responses = append(responses, ConvertVoucherToResponse(voucher))
+	}
+	return responses
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"time"
 	"tokogo/config"
 	"tokogo/models"
 	"tokogo/repositories"
@@ -10,14 +11,18 @@ import (
 )
 
 type CartService struct {
-	cartRepo    *repositories.CartRepository
-	productRepo *repositories.ProductRepository
+	cartRepo        *repositories.CartRepository
+	productRepo     *repositories.ProductRepository
+	variantRepo     *repositories.ProductVariantRepository
+	reservationRepo *repositories.StockReservationRepository
 }
 
 func NewCartService() *CartService {
 	return &CartService{
-		cartRepo:    repositories.NewCartRepository(config.DB),
-		productRepo: repositories.NewProductRepository(config.DB),
+		cartRepo:        repositories.NewCartRepository(config.DB),
+		productRepo:     repositories.NewProductRepository(config.DB),
+		variantRepo:     repositories.NewProductVariantRepository(config.DB),
+		reservationRepo: repositories.NewStockReservationRepository(config.DB),
 	}
 }
 
@@ -27,20 +32,33 @@ func (s *CartService) AddToCart(userID uint, req requests.AddToCartRequest) (*re
 	if err != nil {
 		return nil, errors.New("product not found")
 	}
+	if !product.IsVisible(time.Now()) {
+		return nil, errors.New("product is not available")
+	}
+
+	// Product dengan variant wajib memilih variant
+	variant, err := s.resolveVariant(product, req.VariantID)
+	if err != nil {
+		return nil, err
+	}
 
-	// Check if product is in stock
-	if product.Stock < req.Quantity {
+	// Check if product is in stock (stock reserved by other shoppers is unavailable)
+	available, err := s.availableStock(product, variant, userID)
+	if err != nil {
+		return nil, err
+	}
+	if available < req.Quantity {
 		return nil, errors.New("insufficient stock")
 	}
 
 	// Check if item already exists in cart
-	existingCart, err := s.cartRepo.GetByUserIDAndProductID(userID, req.ProductID)
+	existingCart, err := s.cartRepo.GetByUserIDAndProductID(userID, req.ProductID, req.VariantID)
 	if err == nil {
 		// Item exists, update quantity
 		existingCart.Quantity += req.Quantity
 
 		// Check stock again after adding
-		if product.Stock < existingCart.Quantity {
+		if available < existingCart.Quantity {
 			return nil, errors.New("insufficient stock")
 		}
 
@@ -56,6 +74,7 @@ func (s *CartService) AddToCart(userID uint, req requests.AddToCartRequest) (*re
 	cart := &models.Cart{
 		UserID:    userID,
 		ProductID: req.ProductID,
+		VariantID: req.VariantID,
 		Quantity:  req.Quantity,
 	}
 
@@ -64,7 +83,7 @@ func (s *CartService) AddToCart(userID uint, req requests.AddToCartRequest) (*re
 	}
 
 	// Get the created cart with product details
-	createdCart, err := s.cartRepo.GetByUserIDAndProductID(userID, req.ProductID)
+	createdCart, err := s.cartRepo.GetByUserIDAndProductID(userID, req.ProductID, req.VariantID)
 	if err != nil {
 		return nil, errors.New("failed to retrieve cart item")
 	}
@@ -83,24 +102,31 @@ func (s *CartService) GetCart(userID uint) (*responses.CartResponse, error) {
 	return &response, nil
 }
 
-func (s *CartService) UpdateCartItem(userID uint, productID uint, req requests.UpdateCartItemRequest) (*responses.CartItemResponse, error) {
+func (s *CartService) UpdateCartItem(userID uint, productID uint, variantID *uint, req requests.UpdateCartItemRequest) (*responses.CartItemResponse, error) {
 	// Check if product exists
 	product, err := s.productRepo.GetByID(productID)
 	if err != nil {
 		return nil, errors.New("product not found")
 	}
-
-	// Check if product is in stock
-	if product.Stock < req.Quantity {
-		return nil, errors.New("insufficient stock")
+	if !product.IsVisible(time.Now()) {
+		return nil, errors.New("product is not available")
 	}
 
 	// Get existing cart item
-	cart, err := s.cartRepo.GetByUserIDAndProductID(userID, productID)
+	cart, err := s.cartRepo.GetByUserIDAndProductID(userID, productID, variantID)
 	if err != nil {
 		return nil, errors.New("cart item not found")
 	}
 
+	// Check if product is in stock (stock reserved by other shoppers is unavailable)
+	available, err := s.availableStock(product, cart.Variant, userID)
+	if err != nil {
+		return nil, err
+	}
+	if available < req.Quantity {
+		return nil, errors.New("insufficient stock")
+	}
+
 	// Update quantity
 	cart.Quantity = req.Quantity
 
@@ -112,14 +138,14 @@ func (s *CartService) UpdateCartItem(userID uint, productID uint, req requests.U
 	return &response, nil
 }
 
-func (s *CartService) RemoveFromCart(userID uint, productID uint) error {
+func (s *CartService) RemoveFromCart(userID uint, productID uint, variantID *uint) error {
 	// Check if cart item exists
-	_, err := s.cartRepo.GetByUserIDAndProductID(userID, productID)
+	_, err := s.cartRepo.GetByUserIDAndProductID(userID, productID, variantID)
 	if err != nil {
 		return errors.New("cart item not found")
 	}
 
-	if err := s.cartRepo.DeleteByUserIDAndProductID(userID, productID); err != nil {
+	if err := s.cartRepo.DeleteByUserIDAndProductID(userID, productID, variantID); err != nil {
 		return errors.New("failed to remove from cart")
 	}
 
@@ -142,3 +168,37 @@ func (s *CartService) GetCartItemCount(userID uint) (int64, error) {
 
 	return count, nil
 }
+
+// resolveVariant memastikan variant yang dipilih milik product. Product yang memiliki
+// variant wajib memilih salah satunya, product tanpa variant tidak boleh memilih variant.
+func (s *CartService) resolveVariant(product *models.Product, variantID *uint) (*models.ProductVariant, error) {
+	if variantID == nil {
+		if len(product.Variants) > 0 {
+			return nil, errors.New("variant_id is required for this product")
+		}
+		return nil, nil
+	}
+
+	variant, err := s.variantRepo.GetByIDAndProductID(*variantID, product.ID)
+	if err != nil {
+		return nil, errors.New("variant not found")
+	}
+	return variant, nil
+}
+
+// availableStock menghitung stok product (atau variant-nya) yang masih bisa dibeli user,
+// yaitu stok dikurangi reservasi aktif milik user lain
+func (s *CartService) availableStock(product *models.Product, variant *models.ProductVariant, userID uint) (int, error) {
+	stock := product.Stock
+	var variantID *uint
+	if variant != nil {
+		stock = variant.Stock
+		variantID = &variant.ID
+	}
+
+	reserved, err := s.reservationRepo.GetReservedQuantity(product.ID, variantID, userID, time.Now())
+	if err != nil {
+		return 0, errors.New("failed to check stock reservation")
+	}
+	return stock - reserved, nil
+}
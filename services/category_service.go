@@ -2,20 +2,31 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"tokogo/config"
 	"tokogo/models"
 	"tokogo/repositories"
 	"tokogo/requests"
 	"tokogo/responses"
+
+	"gorm.io/gorm"
 )
 
+// ErrCategoryInUse dikembalikan jika category yang akan dihapus masih dipakai product atau sub category
+var ErrCategoryInUse = errors.New("category is still in use")
+
 type CategoryService struct {
+	db           *gorm.DB
 	categoryRepo *repositories.CategoryRepository
+	slugRepo     *repositories.SlugRepository
 }
 
 // NewCategoryService membuat instance baru CategoryService
 func NewCategoryService() *CategoryService {
 	return &CategoryService{
+		db:           config.DB,
 		categoryRepo: repositories.NewCategoryRepository(),
+		slugRepo:     repositories.NewSlugRepository(config.DB),
 	}
 }
 
@@ -30,14 +41,48 @@ func (s *CategoryService) CreateCategory(req requests.CreateCategoryRequest) (*r
 		return nil, errors.New("category name already exists")
 	}
 
+	// Cek apakah parent category ada
+	if req.ParentID != nil {
+		if _, err := s.categoryRepo.GetCategoryByID(*req.ParentID); err != nil {
+			return nil, errors.New("parent category not found")
+		}
+	}
+
 	// Buat category baru
 	category := &models.Category{
-		Name: req.Name,
+		Name:     req.Name,
+		ParentID: req.ParentID,
 	}
 
-	// Simpan ke database
-	if err := s.categoryRepo.CreateCategory(category); err != nil {
-		return nil, errors.New("failed to create category")
+	// Slug, category, dan catatan slug disimpan dalam satu transaction
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		slugRepo := s.slugRepo.WithTx(tx)
+
+		// Parent dikunci agar tidak terhapus bersamaan dengan pembuatan sub category
+		if category.ParentID != nil {
+			if _, err := s.categoryRepo.WithTx(tx).GetCategoryByIDForShare(*category.ParentID); err != nil {
+				return errors.New("parent category not found")
+			}
+		}
+
+		// Buat slug unik dari nama
+		slugs := categorySlugChange(0, "")
+		slug, err := slugs.resolve(slugRepo, category.Name)
+		if err != nil {
+			return errors.New("failed to generate category slug")
+		}
+		category.Slug = slug
+
+		if err := s.categoryRepo.WithTx(tx).CreateCategory(category); err != nil {
+			return errors.New("failed to create category")
+		}
+		if err := slugs.record(slugRepo, category.ID, category.Slug); err != nil {
+			return errors.New("failed to save category slug")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Return response
@@ -45,6 +90,7 @@ func (s *CategoryService) CreateCategory(req requests.CreateCategoryRequest) (*r
 		ID:        category.ID,
 		Name:      category.Name,
 		Slug:      category.Slug,
+		ParentID:  category.ParentID,
 		CreatedAt: category.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt: category.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}, nil
@@ -61,6 +107,7 @@ func (s *CategoryService) GetCategoryByID(id uint) (*responses.CategoryResponse,
 		ID:        category.ID,
 		Name:      category.Name,
 		Slug:      category.Slug,
+		ParentID:  category.ParentID,
 		CreatedAt: category.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt: category.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}, nil
@@ -81,6 +128,25 @@ func (s *CategoryService) GetAllCategories(page, limit int) (*responses.Category
 	}, nil
 }
 
+// GetAllCategoriesByCursor mengambil categories dengan pagination berbasis cursor
+func (s *CategoryService) GetAllCategoriesByCursor(cursor string, limit int) (*responses.CategoryListResponse, error) {
+	limit = normalizeCursorLimit(limit)
+
+	categories, next, err := s.categoryRepo.GetAllCategoriesByCursor(cursor, limit)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCursor) {
+			return nil, err
+		}
+		return nil, errors.New("failed to get categories")
+	}
+
+	return &responses.CategoryListResponse{
+		Categories: responses.ConvertCategoriesToResponse(categories),
+		Limit:      limit,
+		NextCursor: next,
+	}, nil
+}
+
 // UpdateCategory mengupdate category berdasarkan ID
 func (s *CategoryService) UpdateCategory(id uint, req requests.UpdateCategoryRequest) (*responses.CategoryResponse, error) {
 	// Cek apakah category ada
@@ -98,10 +164,43 @@ func (s *CategoryService) UpdateCategory(id uint, req requests.UpdateCategoryReq
 		return nil, errors.New("category name already exists")
 	}
 
+	// Cek parent baru agar tidak membentuk siklus
+	if err := s.validateParent(id, req.ParentID); err != nil {
+		return nil, err
+	}
+
 	// Update category
 	existingCategory.Name = req.Name
-	if err := s.categoryRepo.UpdateCategory(id, existingCategory); err != nil {
-		return nil, errors.New("failed to update category")
+	existingCategory.ParentID = req.ParentID
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		slugRepo := s.slugRepo.WithTx(tx)
+
+		// Parent dikunci agar tidak terhapus bersamaan dengan pemindahan category
+		if existingCategory.ParentID != nil {
+			if _, err := s.categoryRepo.WithTx(tx).GetCategoryByIDForShare(*existingCategory.ParentID); err != nil {
+				return errors.New("parent category not found")
+			}
+		}
+
+		// Slug baru dibuat jika nama berubah, slug lama tetap diarahkan ke category ini
+		slugs := categorySlugChange(id, existingCategory.Slug)
+		slug, err := slugs.resolve(slugRepo, existingCategory.Name)
+		if err != nil {
+			return errors.New("failed to generate category slug")
+		}
+		existingCategory.Slug = slug
+
+		if err := s.categoryRepo.WithTx(tx).UpdateCategory(id, existingCategory); err != nil {
+			return errors.New("failed to update category")
+		}
+		if err := slugs.record(slugRepo, id, existingCategory.Slug); err != nil {
+			return errors.New("failed to save category slug")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Get updated category
@@ -114,23 +213,192 @@ func (s *CategoryService) UpdateCategory(id uint, req requests.UpdateCategoryReq
 		ID:        updatedCategory.ID,
 		Name:      updatedCategory.Name,
 		Slug:      updatedCategory.Slug,
+		ParentID:  updatedCategory.ParentID,
 		CreatedAt: updatedCategory.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt: updatedCategory.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}, nil
 }
 
-// DeleteCategory menghapus category berdasarkan ID
-func (s *CategoryService) DeleteCategory(id uint) error {
+// DeleteCategory menghapus category berdasarkan ID. Category yang masih dipakai product
+// hanya bisa dihapus jika reassignTo diisi, product-nya lalu dipindah ke category tersebut.
+func (s *CategoryService) DeleteCategory(id uint, reassignTo *uint) error {
 	// Cek apakah category ada
 	_, err := s.categoryRepo.GetCategoryByID(id)
 	if err != nil {
 		return err
 	}
 
-	// Hapus category
-	if err := s.categoryRepo.DeleteCategory(id); err != nil {
-		return errors.New("failed to delete category")
+	// Cek category tujuan jika product akan dipindah
+	if reassignTo != nil {
+		if *reassignTo == id {
+			return errors.New("cannot reassign products to the category being deleted")
+		}
+		if _, err := s.categoryRepo.GetCategoryByID(*reassignTo); err != nil {
+			return errors.New("target category not found")
+		}
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		categoryRepo := s.categoryRepo.WithTx(tx)
+
+		// Kunci category lebih dulu agar product dan sub category baru menunggu sampai penghapusan selesai
+		if _, err := categoryRepo.GetCategoryByIDForUpdate(id); err != nil {
+			return err
+		}
+		if reassignTo != nil {
+			if _, err := categoryRepo.GetCategoryByIDForShare(*reassignTo); err != nil {
+				return errors.New("target category not found")
+			}
+		}
+
+		// Sub category harus dipindah atau dihapus lebih dulu agar tidak kehilangan parent
+		children, err := categoryRepo.CountChildren(id)
+		if err != nil {
+			return errors.New("failed to check sub categories")
+		}
+		if children > 0 {
+			return fmt.Errorf("%w: %d sub categories still belong to this category", ErrCategoryInUse, children)
+		}
+
+		products, err := categoryRepo.CountProducts(id)
+		if err != nil {
+			return errors.New("failed to check category products")
+		}
+		if products > 0 {
+			if reassignTo == nil {
+				return fmt.Errorf("%w: %d products still use this category, move them first or pass reassign_to", ErrCategoryInUse, products)
+			}
+			if err := categoryRepo.ReassignProducts(id, *reassignTo); err != nil {
+				return errors.New("failed to reassign products")
+			}
+		}
+
+		// Hapus category
+		if err := categoryRepo.DeleteCategory(id); err != nil {
+			return errors.New("failed to delete category")
+		}
+		return nil
+	})
+}
+
+// RestoreCategory mengembalikan category yang sudah dihapus. Jika parent-nya juga sudah
+// dihapus, category dikembalikan sebagai root.
+func (s *CategoryService) RestoreCategory(id uint) (*responses.CategoryResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		categoryRepo := s.categoryRepo.WithTx(tx)
+		slugRepo := s.slugRepo.WithTx(tx)
+
+		category, err := categoryRepo.GetDeletedCategoryByID(id)
+		if err != nil {
+			return err
+		}
+
+		// Nama bisa saja sudah dipakai category lain setelah category ini dihapus
+		exists, err := categoryRepo.CheckCategoryExists(category.Name, id)
+		if err != nil {
+			return errors.New("failed to check category existence")
+		}
+		if exists {
+			return errors.New("category name already exists")
+		}
+
+		parentID := category.ParentID
+		if parentID != nil {
+			if _, err := categoryRepo.GetCategoryByIDForShare(*parentID); err != nil {
+				parentID = nil
+			}
+		}
+
+		// Slug dicek ulang, redirect yang kini memakai slug tersebut dilepas
+		slugs := categorySlugChange(id, category.Slug)
+		slug, err := slugs.resolve(slugRepo, category.Name)
+		if err != nil {
+			return errors.New("failed to generate category slug")
+		}
+
+		if err := categoryRepo.RestoreCategory(id, parentID, slug); err != nil {
+			return errors.New("failed to restore category")
+		}
+		if err := slugs.record(slugRepo, id, slug); err != nil {
+			return errors.New("failed to save category slug")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := s.categoryRepo.GetCategoryByID(id)
+	if err != nil {
+		return nil, errors.New("failed to get restored category")
+	}
+
+	response := responses.ConvertCategoryToResponse(*restored)
+	return &response, nil
+}
+
+// GetCategoryTree mengambil seluruh category dalam bentuk tree
+func (s *CategoryService) GetCategoryTree() ([]responses.CategoryTreeResponse, error) {
+	categories, err := s.categoryRepo.GetAllCategoriesForTree()
+	if err != nil {
+		return nil, errors.New("failed to get category tree")
+	}
+
+	return responses.BuildCategoryTree(categories), nil
+}
+
+// validateParent memastikan parent ada dan bukan category itu sendiri atau turunannya
+func (s *CategoryService) validateParent(id uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == id {
+		return errors.New("category cannot be its own parent")
+	}
+
+	if _, err := s.categoryRepo.GetCategoryByID(*parentID); err != nil {
+		return errors.New("parent category not found")
+	}
+
+	isDescendant, err := s.categoryRepo.IsDescendantOf(*parentID, id)
+	if err != nil {
+		return errors.New("failed to check category hierarchy")
+	}
+	if isDescendant {
+		return errors.New("category cannot be moved under its own descendant")
 	}
 
 	return nil
 }
+
+// GetCategoryBySlugPublic mengambil category berdasarkan slug. Jika slug adalah slug lama
+// dari category yang sudah di-rename, slug terbaru dikembalikan sebagai redirectSlug.
+func (s *CategoryService) GetCategoryBySlugPublic(slug string) (response *responses.CategoryResponse, redirectSlug string, err error) {
+	if category, err := s.categoryRepo.GetCategoryBySlug(slug); err == nil {
+		converted := responses.ConvertCategoryToResponse(*category)
+		return &converted, "", nil
+	}
+
+	redirect, err := s.slugRepo.FindRedirect(models.SlugEntityCategory, slug)
+	if err != nil {
+		return nil, "", errors.New("category not found")
+	}
+	category, err := s.categoryRepo.GetCategoryByID(redirect.EntityID)
+	if err != nil {
+		return nil, "", errors.New("category not found")
+	}
+	return nil, category.Slug, nil
+}
+
+// categorySlugChange menyiapkan perubahan slug untuk category. Slug "tree" dicadangkan
+// karena bertabrakan dengan route /categories/tree.
+func categorySlugChange(id uint, current string) slugChange {
+	return slugChange{
+		entityType: models.SlugEntityCategory,
+		model:      &models.Category{},
+		entityID:   id,
+		current:    current,
+		fallback:   "category",
+		reserved:   []string{"tree"},
+	}
+}
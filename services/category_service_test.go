@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"tokogo/models"
+	"tokogo/repositories"
+	"tokogo/requests"
+)
+
+func TestRenameCategoryKeepsOldSlugAsRedirect(t *testing.T) {
+	db := openTestDB(t)
+	service := NewCategoryService()
+
+	created, err := service.CreateCategory(requests.CreateCategoryRequest{Name: "Sepatu"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.Slug != "sepatu" {
+		t.Fatalf("slug = %s, want sepatu", created.Slug)
+	}
+
+	updated, err := service.UpdateCategory(created.ID, requests.UpdateCategoryRequest{Name: "Sepatu Lari"})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if updated.Slug != "sepatu-lari" {
+		t.Errorf("slug = %s, want sepatu-lari", updated.Slug)
+	}
+
+	var redirect models.SlugRedirect
+	err = db.Where("entity_type = ? AND slug = ?", models.SlugEntityCategory, "sepatu").First(&redirect).Error
+	if err != nil || redirect.EntityID != created.ID {
+		t.Errorf("redirect for old slug = %+v, %v, want category %d", redirect, err, created.ID)
+	}
+}
+
+func TestDeleteCategoryWaitsForProductBeingCreated(t *testing.T) {
+	db := openTestDB(t)
+	service := NewCategoryService()
+
+	created, err := service.CreateCategory(requests.CreateCategoryRequest{Name: "Sepatu"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// Transaction pembuatan product memegang shared lock pada category
+	tx := db.Begin()
+	if _, err := repositories.NewCategoryRepository().WithTx(tx).GetCategoryByIDForShare(created.ID); err != nil {
+		tx.Rollback()
+		t.Fatalf("lock category: %v", err)
+	}
+
+	deleted := make(chan error, 1)
+	go func() { deleted <- service.DeleteCategory(created.ID, nil) }()
+
+	select {
+	case err := <-deleted:
+		tx.Rollback()
+		t.Fatalf("delete finished while the category was locked: %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	product := models.Product{Name: "Sepatu Lari", Slug: "sepatu-lari", PurchasePrice: 50000, SellingPrice: 80000, CategoryID: created.ID, Status: models.ProductStatusPublished}
+	if err := tx.Create(&product).Error; err != nil {
+		tx.Rollback()
+		t.Fatalf("create product: %v", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	if err := <-deleted; !errors.Is(err, ErrCategoryInUse) {
+		t.Errorf("delete err = %v, want ErrCategoryInUse", err)
+	}
+}
+
+func TestRestoreCategoryReleasesRedirectForItsSlug(t *testing.T) {
+	db := openTestDB(t)
+	service := NewCategoryService()
+
+	created, err := service.CreateCategory(requests.CreateCategoryRequest{Name: "Sepatu"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	other, err := service.CreateCategory(requests.CreateCategoryRequest{Name: "Sandal"})
+	if err != nil {
+		t.Fatalf("create other: %v", err)
+	}
+	if err := service.DeleteCategory(created.ID, nil); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	// Redirect untuk slug category yang dihapus sempat diarahkan ke category lain
+	redirect := models.SlugRedirect{EntityType: models.SlugEntityCategory, Slug: "sepatu", EntityID: other.ID}
+	if err := db.Create(&redirect).Error; err != nil {
+		t.Fatalf("create redirect: %v", err)
+	}
+
+	restored, err := service.RestoreCategory(created.ID)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if restored.Slug != "sepatu" {
+		t.Errorf("slug = %s, want sepatu", restored.Slug)
+	}
+
+	var redirects int64
+	db.Model(&models.SlugRedirect{}).Where("entity_type = ? AND slug = ?", models.SlugEntityCategory, "sepatu").Count(&redirects)
+	if redirects != 0 {
+		t.Errorf("redirects for restored slug = %d, want 0", redirects)
+	}
+}
@@ -3,133 +3,335 @@ package services
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 	"tokogo/config"
+	"tokogo/helpers"
 	"tokogo/models"
 	"tokogo/repositories"
 	"tokogo/requests"
 	"tokogo/responses"
+
+	"gorm.io/gorm"
 )
 
 type CheckoutService struct {
-	cartRepo        *repositories.CartRepository
-	productRepo     *repositories.ProductRepository
-	transactionRepo *repositories.TransactionRepository
+	db               *gorm.DB
+	cartRepo         *repositories.CartRepository
+	productRepo      *repositories.ProductRepository
+	variantRepo      *repositories.ProductVariantRepository
+	transactionRepo  *repositories.TransactionRepository
+	reservationRepo  *repositories.StockReservationRepository
+	voucherRepo      *repositories.VoucherRepository
+	categoryRepo     *repositories.CategoryRepository
+	promotionRepo    *repositories.PromotionRepository
+	inventory        *InventoryService
+	paymentGateway   PaymentGateway
+	lowStockNotifier LowStockNotifier
 }
 
 func NewCheckoutService() *CheckoutService {
 	return &CheckoutService{
-		cartRepo:        repositories.NewCartRepository(config.DB),
-		productRepo:     repositories.NewProductRepository(config.DB),
-		transactionRepo: repositories.NewTransactionRepository(config.DB),
+		db:               config.DB,
+		cartRepo:         repositories.NewCartRepository(config.DB),
+		productRepo:      repositories.NewProductRepository(config.DB),
+		variantRepo:      repositories.NewProductVariantRepository(config.DB),
+		transactionRepo:  repositories.NewTransactionRepository(config.DB),
+		reservationRepo:  repositories.NewStockReservationRepository(config.DB),
+		voucherRepo:      repositories.NewVoucherRepository(config.DB),
+		categoryRepo:     repositories.NewCategoryRepository(),
+		promotionRepo:    repositories.NewPromotionRepository(config.DB),
+		inventory:        NewInventoryService(),
+		paymentGateway:   DefaultPaymentGateway(),
+		lowStockNotifier: DefaultLowStockNotifier(),
 	}
 }
 
 func (s *CheckoutService) GetCheckoutSummary(userID uint, req requests.CheckoutRequest) (*responses.CheckoutSummaryResponse, error) {
-	// Get user's cart
-	carts, err := s.cartRepo.GetByUserID(userID)
-	if err != nil {
-		return nil, errors.New("failed to get cart")
-	}
+	var carts []models.Cart
+	reservedUntil := time.Now().Add(stockReservationTTL())
+
+	// Reserve stock for every cart item while the user completes checkout
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		cartRepo := s.cartRepo.WithTx(tx)
+		productRepo := s.productRepo.WithTx(tx)
+		variantRepo := s.variantRepo.WithTx(tx)
+		reservationRepo := s.reservationRepo.WithTx(tx)
+
+		// Get user's cart
+		var err error
+		carts, err = cartRepo.GetByUserID(userID)
+		if err != nil {
+			return errors.New("failed to get cart")
+		}
+
+		if len(carts) == 0 {
+			return errors.New("cart is empty")
+		}
+
+		// Replace any reservation from a previous summary
+		if err := reservationRepo.DeleteByUserID(userID); err != nil {
+			return errors.New("failed to release previous stock reservation")
+		}
+
+		// Lock product rows in a stable order to avoid deadlocks between concurrent checkouts
+		sortCartLines(carts)
+
+		for i, cart := range carts {
+			product, variant, err := lockCartLine(productRepo, variantRepo, cart)
+			if err != nil {
+				return err
+			}
+
+			if err := s.checkAvailableStock(reservationRepo, product, variant, userID, cart.Quantity); err != nil {
+				return err
+			}
+
+			// Use the locked rows so the summary shows the current price
+			carts[i].Product = *product
+			carts[i].Variant = variant
+
+			reservation := &models.StockReservation{
+				UserID:    userID,
+				ProductID: cart.ProductID,
+				VariantID: cart.VariantID,
+				Quantity:  cart.Quantity,
+				ExpiresAt: reservedUntil,
+			}
+			if err := reservationRepo.Create(reservation); err != nil {
+				return errors.New("failed to reserve stock")
+			}
+		}
 
-	if len(carts) == 0 {
-		return nil, errors.New("cart is empty")
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Calculate shipping cost (simple logic - can be enhanced)
 	shippingCost := s.calculateShippingCost(carts)
 
+	// Promosi otomatis dihitung dengan harga pada waktu yang sama dengan ringkasan
+	pricedAt := time.Now()
+	promotions, err := s.promotionRepo.GetRunning(pricedAt)
+	if err != nil {
+		return nil, errors.New("failed to get promotions")
+	}
+	applied := evaluatePromotions(promotions, carts, pricedAt)
+
 	// Create checkout summary
-	summary := responses.CreateCheckoutSummaryResponse(carts, shippingCost, req.PaymentMethod, req.ShippingAddress)
+	summary := responses.CreateCheckoutSummaryResponse(carts, applied.Lines, shippingCost, req.PaymentMethod, req.ShippingAddress, pricedAt)
+	summary.ReservedUntil = reservedUntil.Format("2006-01-02 15:04:05")
+
+	// Voucher hanya diperiksa di sini, kuotanya baru dipakai saat checkout diproses
+	if req.VoucherCode != "" {
+		voucher, err := s.voucherRepo.GetByCode(normalizeVoucherCode(req.VoucherCode))
+		if err != nil {
+			return nil, errors.New("voucher not found")
+		}
+		discount, err := quoteVoucher(s.voucherRepo, s.categoryRepo, voucher, userID, carts, applied.LineDiscounts(), shippingCost, pricedAt)
+		if err != nil {
+			return nil, err
+		}
+		summary.VoucherCode = voucher.Code
+		summary.DiscountAmount = discount
+		summary.GrandTotal -= discount
+	}
 
 	return &summary, nil
 }
 
 func (s *CheckoutService) ProcessCheckout(userID uint, req requests.CheckoutRequest) (*responses.CheckoutResponse, error) {
-	// Get user's cart
-	carts, err := s.cartRepo.GetByUserID(userID)
-	if err != nil {
-		return nil, errors.New("failed to get cart")
-	}
+	var transactionID uint
+	var lowStockAlerts []LowStockAlert
+
+	// Seluruh proses checkout berjalan dalam satu database transaction
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		cartRepo := s.cartRepo.WithTx(tx)
+		productRepo := s.productRepo.WithTx(tx)
+		variantRepo := s.variantRepo.WithTx(tx)
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		reservationRepo := s.reservationRepo.WithTx(tx)
+		voucherRepo := s.voucherRepo.WithTx(tx)
+		inventory := s.inventory.WithTx(tx)
+
+		// Get user's cart
+		carts, err := cartRepo.GetByUserID(userID)
+		if err != nil {
+			return errors.New("failed to get cart")
+		}
 
-	if len(carts) == 0 {
-		return nil, errors.New("cart is empty")
-	}
+		if len(carts) == 0 {
+			return errors.New("cart is empty")
+		}
 
-	// Validate stock for all items
-	for _, cart := range carts {
-		product, err := s.productRepo.GetByID(cart.ProductID)
-		if err != nil {
-			return nil, fmt.Errorf("product with ID %d not found", cart.ProductID)
+		// Lock product rows in a stable order to avoid deadlocks between concurrent checkouts
+		sortCartLines(carts)
+
+		// Validate stock for all items
+		for i, cart := range carts {
+			product, variant, err := lockCartLine(productRepo, variantRepo, cart)
+			if err != nil {
+				return err
+			}
+
+			// Stock reserved by other shoppers is not available, our own reservation is converted below
+			if err := s.checkAvailableStock(reservationRepo, product, variant, userID, cart.Quantity); err != nil {
+				return err
+			}
+
+			// Use the locked rows so price and stock reflect the current state
+			carts[i].Product = *product
+			carts[i].Variant = variant
 		}
 
-		if product.Stock < cart.Quantity {
-			return nil, fmt.Errorf("insufficient stock for product %s (available: %d, requested: %d)",
-				product.Name, product.Stock, cart.Quantity)
+		// Calculate total amount, harga sale yang berlaku saat order dibuat ikut dikunci di detail
+		var totalAmount float64
+		pricedAt := time.Now()
+		for _, cart := range carts {
+			totalAmount += float64(cart.Quantity) * cart.UnitPrice(pricedAt)
 		}
-	}
 
-	// Calculate total amount
-	var totalAmount float64
-	for _, cart := range carts {
-		totalAmount += float64(cart.Quantity) * cart.Product.SellingPrice
-	}
+		// Promosi otomatis dihitung ulang dari harga yang sudah dikunci
+		promotions, err := s.promotionRepo.WithTx(tx).GetRunning(pricedAt)
+		if err != nil {
+			return errors.New("failed to get promotions")
+		}
+		applied := evaluatePromotions(promotions, carts, pricedAt)
+		lineDiscounts := applied.LineDiscounts()
+		totalAmount -= applied.Total
+
+		// Add shipping cost
+		shippingCost := s.calculateShippingCost(carts)
+		totalAmount += shippingCost
+
+		// Voucher dikunci dan kuotanya dipakai di transaction yang sama dengan pembuatan order
+		var voucher *models.Voucher
+		var discount float64
+		if req.VoucherCode != "" {
+			voucher, discount, err = redeemVoucher(voucherRepo, s.categoryRepo.WithTx(tx), req.VoucherCode, userID, carts, lineDiscounts, shippingCost, pricedAt)
+			if err != nil {
+				return err
+			}
+			totalAmount -= discount
+		}
 
-	// Add shipping cost
-	shippingCost := s.calculateShippingCost(carts)
-	totalAmount += shippingCost
+		// Create transaction
+		transaction := &models.Transaction{
+			UserID:            userID,
+			Status:            models.TransactionStatusPending,
+			TotalAmount:       totalAmount,
+			DiscountAmount:    discount,
+			PromotionDiscount: applied.Total,
+			ShippingAddress:   req.ShippingAddress,
+			PaymentMethod:     req.PaymentMethod,
+			Notes:             req.Notes,
+		}
+		// Order non-COD harus dibayar sebelum batas waktu, setelah itu di-expire
+		if req.PaymentMethod != models.PaymentMethodCOD {
+			dueAt := pricedAt.Add(paymentWindow())
+			transaction.PaymentDueAt = &dueAt
+		}
+		if voucher != nil {
+			transaction.VoucherID = &voucher.ID
+			transaction.VoucherCode = voucher.Code
+		}
 
-	// Create transaction
-	transaction := &models.Transaction{
-		UserID:          userID,
-		Status:          "pending",
-		TotalAmount:     totalAmount,
-		ShippingAddress: req.ShippingAddress,
-		PaymentMethod:   req.PaymentMethod,
-		Notes:           req.Notes,
-	}
+		// Save transaction
+		if err := transactionRepo.Create(transaction); err != nil {
+			return errors.New("failed to create transaction")
+		}
 
-	// Save transaction
-	if err := s.transactionRepo.Create(transaction); err != nil {
-		return nil, errors.New("failed to create transaction")
-	}
+		if voucher != nil {
+			redemption := &models.VoucherRedemption{
+				VoucherID:     voucher.ID,
+				UserID:        userID,
+				TransactionID: transaction.ID,
+				Discount:      discount,
+			}
+			if err := voucherRepo.CreateRedemption(redemption); err != nil {
+				return errors.New("failed to record voucher usage")
+			}
+		}
 
-	// Create transaction details
-	for _, cart := range carts {
-		detail := &models.TransactionDetail{
-			TransactionID: transaction.ID,
-			ProductID:     cart.ProductID,
-			Quantity:      cart.Quantity,
-			Price:         cart.Product.SellingPrice,
+		if err := recordStatusHistory(transactionRepo, transaction.ID, "", transaction.Status, userID, models.ActorRoleCustomer, "Order created"); err != nil {
+			return err
 		}
 
-		if err := s.transactionRepo.CreateTransactionDetail(detail); err != nil {
-			return nil, errors.New("failed to create transaction detail")
+		// Create transaction details
+		for i, cart := range carts {
+			detail := &models.TransactionDetail{
+				TransactionID: transaction.ID,
+				ProductID:     cart.ProductID,
+				VariantID:     cart.VariantID,
+				Quantity:      cart.Quantity,
+				Price:         cart.UnitPrice(pricedAt),
+				Discount:      lineDiscounts[i],
+				Promotions:    applied.Lines[i],
+			}
+
+			if err := transactionRepo.CreateTransactionDetail(detail); err != nil {
+				return errors.New("failed to create transaction detail")
+			}
+
+			// Update product stock
+			err := inventory.AdjustStock(StockAdjustment{
+				ProductID:   cart.ProductID,
+				VariantID:   cart.VariantID,
+				Delta:       -cart.Quantity,
+				Reason:      models.StockMovementSale,
+				ReferenceID: transaction.ID,
+				ActorID:     userID,
+			})
+			if err != nil {
+				if errors.Is(err, repositories.ErrInsufficientStock) {
+					return fmt.Errorf("insufficient stock for product %s", cart.Product.Name)
+				}
+				return errors.New("failed to update product stock")
+			}
+
+			// Alert admins when this sale pushes the product (or variant) to its low stock threshold
+			if alert, ok := lowStockAlertForSale(cart, transaction.ID); ok {
+				lowStockAlerts = append(lowStockAlerts, alert)
+			}
 		}
 
-		// Update product stock
-		product, _ := s.productRepo.GetByID(cart.ProductID)
-		product.Stock -= cart.Quantity
-		if err := s.productRepo.Update(product); err != nil {
-			return nil, errors.New("failed to update product stock")
+		// Clear user's cart
+		if err := cartRepo.ClearCart(userID); err != nil {
+			return errors.New("failed to clear cart")
 		}
-	}
 
-	// Generate payment URL (mock implementation)
-	paymentURL := s.generatePaymentURL(transaction.ID, req.PaymentMethod)
-	transaction.PaymentURL = paymentURL
+		// Reservation has been converted into a real stock deduction
+		if err := reservationRepo.DeleteByUserID(userID); err != nil {
+			return errors.New("failed to release stock reservation")
+		}
 
-	if err := s.transactionRepo.Update(transaction); err != nil {
-		return nil, errors.New("failed to update payment URL")
+		transactionID = transaction.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Clear user's cart
-	if err := s.cartRepo.ClearCart(userID); err != nil {
-		// Log error but don't fail the checkout
-		fmt.Printf("Warning: Failed to clear cart for user %d: %v\n", userID, err)
+	// Create charge on payment gateway (COD doesn't need payment). Tagihan dibuat setelah commit
+	// agar gateway yang lambat tidak menahan lock row product yang dipakai checkout lain.
+	if req.PaymentMethod != models.PaymentMethodCOD {
+		if err := s.createPaymentCharge(transactionID); err != nil {
+			s.failUnchargedCheckout(userID, transactionID)
+			return nil, err
+		}
+	}
+
+	// Notify only after the stock change has been committed
+	for _, alert := range lowStockAlerts {
+		if err := s.lowStockNotifier.NotifyLowStock(alert); err != nil {
+			fmt.Printf("Warning: Failed to send low stock alert for product %d: %v\n", alert.ProductID, err)
+		}
 	}
 
 	// Get transaction with details for response
-	createdTransaction, err := s.transactionRepo.GetByID(transaction.ID)
+	createdTransaction, err := s.transactionRepo.GetByID(transactionID)
 	if err != nil {
 		return nil, errors.New("failed to retrieve transaction")
 	}
@@ -138,30 +340,92 @@ func (s *CheckoutService) ProcessCheckout(userID uint, req requests.CheckoutRequ
 	return &response, nil
 }
 
-func (s *CheckoutService) ConfirmPayment(userID uint, transactionID uint, req requests.ConfirmPaymentRequest) (*responses.CheckoutResponse, error) {
-	// Get transaction
-	transaction, err := s.transactionRepo.GetByID(transactionID)
+// ConfirmPayment stores the uploaded bank transfer receipt and queues the
+// transaction for admin verification. proofPath is the stored image path;
+// it is removed again if the transaction cannot be updated.
+func (s *CheckoutService) ConfirmPayment(userID uint, transactionID uint, req requests.ConfirmPaymentRequest, proofPath string) (*responses.CheckoutResponse, error) {
+	var previousProof string
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+
+		// Get transaction
+		transaction, err := transactionRepo.GetByIDForUpdate(transactionID)
+		if err != nil {
+			return errors.New("transaction not found")
+		}
+
+		// Check if transaction belongs to user
+		if transaction.UserID != userID {
+			return errors.New("unauthorized access to transaction")
+		}
+
+		// Card and e-wallet payments are confirmed by the payment gateway webhook
+		if transaction.PaymentMethod != models.PaymentMethodBankTransfer {
+			return errors.New("payment confirmation is only available for bank transfer")
+		}
+
+		// Check if transaction is in pending status
+		if transaction.Status != models.TransactionStatusPending {
+			return errors.New("transaction is not in pending status")
+		}
+
+		// Save payment proof
+		previousProof = transaction.PaymentProof
+		transaction.PaymentProof = proofPath
+		transaction.PaymentRejectionNote = ""
+		transaction.Notes = req.Notes
+		if err := transactionRepo.Update(transaction); err != nil {
+			return errors.New("failed to update transaction")
+		}
+
+		// Wait for admin to verify the transfer
+		return changeTransactionStatus(transactionRepo, transaction, models.TransactionStatusAwaitingVerification, userID, models.ActorRoleCustomer, "Payment proof uploaded")
+	})
 	if err != nil {
-		return nil, errors.New("transaction not found")
+		helpers.DeleteFile(proofPath)
+		return nil, err
 	}
 
-	// Check if transaction belongs to user
-	if transaction.UserID != userID {
-		return nil, errors.New("unauthorized access to transaction")
+	// Remove proof from a previously rejected upload
+	if previousProof != "" && previousProof != proofPath {
+		helpers.DeleteFile(previousProof)
 	}
 
-	// Check if transaction is in pending status
-	if transaction.Status != "pending" {
-		return nil, errors.New("transaction is not in pending status")
+	transaction, err := s.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve transaction")
 	}
 
-	// Update transaction status to paid
-	transaction.Status = "paid"
-	transaction.PaymentProof = req.PaymentProof
-	transaction.Notes = req.Notes
+	response := responses.ConvertTransactionToCheckoutResponse(*transaction)
+	return &response, nil
+}
+
+func (s *CheckoutService) CancelTransaction(userID uint, transactionID uint, req requests.CancelTransactionRequest) (*responses.CheckoutResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		inventory := s.inventory.WithTx(tx)
+
+		// Get transaction
+		transaction, err := transactionRepo.GetByIDForUpdate(transactionID)
+		if err != nil {
+			return errors.New("transaction not found")
+		}
 
-	if err := s.transactionRepo.Update(transaction); err != nil {
-		return nil, errors.New("failed to update transaction status")
+		// Check if transaction belongs to user
+		if transaction.UserID != userID {
+			return errors.New("unauthorized access to transaction")
+		}
+
+		return cancelTransaction(transactionRepo, inventory, transaction, userID, models.ActorRoleCustomer, req.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve transaction")
 	}
 
 	response := responses.ConvertTransactionToCheckoutResponse(*transaction)
@@ -199,6 +463,175 @@ func (s *CheckoutService) GetTransactionByID(userID uint, transactionID uint) (*
 }
 
 // Helper methods
+
+// createPaymentCharge membuat tagihan di payment gateway untuk transaksi yang sudah tersimpan
+func (s *CheckoutService) createPaymentCharge(transactionID uint) error {
+	transaction, err := s.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return errors.New("failed to retrieve transaction")
+	}
+
+	charge, err := s.paymentGateway.CreateCharge(*transaction)
+	if err != nil {
+		return errors.New("failed to create payment")
+	}
+
+	if err := s.transactionRepo.UpdatePaymentCharge(transactionID, charge.Reference, charge.PaymentURL); err != nil {
+		return errors.New("failed to update payment URL")
+	}
+	return nil
+}
+
+// failUnchargedCheckout menandai order yang tagihannya gagal dibuat sebagai failed, mengembalikan stok
+// dan voucher-nya, lalu mengembalikan item ke cart agar customer bisa mengulang checkout.
+// Bila gagal, order tetap pending dan akan dilepas oleh job expiry.
+func (s *CheckoutService) failUnchargedCheckout(userID uint, transactionID uint) {
+	note := "Payment could not be created"
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		cartRepo := s.cartRepo.WithTx(tx)
+		inventory := s.inventory.WithTx(tx)
+
+		transaction, err := transactionRepo.GetByIDForUpdate(transactionID)
+		if err != nil {
+			return err
+		}
+		if transaction.Status != models.TransactionStatusPending {
+			return nil
+		}
+
+		if err := releaseTransaction(transactionRepo, inventory, transaction.ID, 0, note); err != nil {
+			return err
+		}
+		if err := changeTransactionStatus(transactionRepo, transaction, models.TransactionStatusFailed, 0, models.ActorRoleSystem, note); err != nil {
+			return err
+		}
+
+		details, err := transactionRepo.GetDetailsByTransactionID(transaction.ID)
+		if err != nil {
+			return err
+		}
+		for _, detail := range details {
+			cart, err := cartRepo.GetByUserIDAndProductID(userID, detail.ProductID, detail.VariantID)
+			if err == nil {
+				cart.Quantity += detail.Quantity
+				if err := cartRepo.Update(cart); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := cartRepo.Create(&models.Cart{
+				UserID:    userID,
+				ProductID: detail.ProductID,
+				VariantID: detail.VariantID,
+				Quantity:  detail.Quantity,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to release checkout %d after payment charge failed: %v\n", transactionID, err)
+	}
+}
+
+func (s *CheckoutService) checkAvailableStock(reservationRepo *repositories.StockReservationRepository, product *models.Product, variant *models.ProductVariant, userID uint, quantity int) error {
+	name := product.Name
+	stock := product.Stock
+	var variantID *uint
+	if variant != nil {
+		name = fmt.Sprintf("%s (%s)", product.Name, variant.SKU)
+		stock = variant.Stock
+		variantID = &variant.ID
+	}
+
+	reserved, err := reservationRepo.GetReservedQuantity(product.ID, variantID, userID, time.Now())
+	if err != nil {
+		return errors.New("failed to check stock reservation")
+	}
+
+	available := stock - reserved
+	if available < quantity {
+		return fmt.Errorf("insufficient stock for product %s (available: %d, requested: %d)",
+			name, max(available, 0), quantity)
+	}
+
+	return nil
+}
+
+// sortCartLines mengurutkan item cart berdasarkan product lalu variant agar row dikunci dengan urutan yang sama
+func sortCartLines(carts []models.Cart) {
+	sort.Slice(carts, func(i, j int) bool {
+		if carts[i].ProductID != carts[j].ProductID {
+			return carts[i].ProductID < carts[j].ProductID
+		}
+		return variantSortKey(carts[i].VariantID) < variantSortKey(carts[j].VariantID)
+	})
+}
+
+func variantSortKey(variantID *uint) uint {
+	if variantID == nil {
+		return 0
+	}
+	return *variantID
+}
+
+// lockCartLine mengunci row product dan, bila item memakai variant, row variant-nya.
+// Variant yang sudah dihapus atau tidak lagi milik product dianggap tidak tersedia.
+func lockCartLine(productRepo *repositories.ProductRepository, variantRepo *repositories.ProductVariantRepository, cart models.Cart) (*models.Product, *models.ProductVariant, error) {
+	product, err := productRepo.GetByIDForUpdate(cart.ProductID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("product with ID %d not found", cart.ProductID)
+	}
+	if !product.IsVisible(time.Now()) {
+		return nil, nil, fmt.Errorf("product %s is no longer available", product.Name)
+	}
+
+	if cart.VariantID == nil {
+		return product, nil, nil
+	}
+
+	variant, err := variantRepo.GetByIDForUpdate(*cart.VariantID)
+	if err != nil || variant.ProductID != product.ID {
+		return nil, nil, fmt.Errorf("variant with ID %d of product %s is no longer available", *cart.VariantID, product.Name)
+	}
+
+	return product, variant, nil
+}
+
+// lowStockAlertForSale membuat alert bila penjualan item cart membuat stok product
+// atau variant-nya turun sampai batas low stock product
+func lowStockAlertForSale(cart models.Cart, transactionID uint) (LowStockAlert, bool) {
+	product := cart.Product
+	if cart.Variant != nil {
+		// Variant memakai threshold product terhadap stoknya sendiri
+		product.Stock = cart.Variant.Stock
+	}
+
+	if product.IsLowStock() {
+		return LowStockAlert{}, false
+	}
+	product.Stock -= cart.Quantity
+	if !product.IsLowStock() {
+		return LowStockAlert{}, false
+	}
+
+	alert := LowStockAlert{
+		ProductID:     product.ID,
+		ProductName:   product.Name,
+		Stock:         product.Stock,
+		Threshold:     product.LowStockThreshold,
+		TransactionID: transactionID,
+		TriggeredAt:   time.Now(),
+	}
+	if cart.Variant != nil {
+		alert.VariantID = cart.VariantID
+		alert.VariantSKU = cart.Variant.SKU
+	}
+	return alert, true
+}
+
 func (s *CheckoutService) calculateShippingCost(carts []models.Cart) float64 {
 	// Simple shipping calculation - can be enhanced with more complex logic
 	var totalWeight float64
@@ -215,21 +648,3 @@ func (s *CheckoutService) calculateShippingCost(carts []models.Cart) float64 {
 
 	return shippingCost
 }
-
-func (s *CheckoutService) generatePaymentURL(transactionID uint, paymentMethod string) string {
-	// Mock payment URL generation - in real implementation, integrate with payment gateway
-	baseURL := "https://payment.example.com"
-
-	switch paymentMethod {
-	case "bank_transfer":
-		return fmt.Sprintf("%s/bank-transfer?transaction_id=%d", baseURL, transactionID)
-	case "credit_card":
-		return fmt.Sprintf("%s/credit-card?transaction_id=%d", baseURL, transactionID)
-	case "e_wallet":
-		return fmt.Sprintf("%s/e-wallet?transaction_id=%d", baseURL, transactionID)
-	case "cod":
-		return "" // COD doesn't need payment URL
-	default:
-		return fmt.Sprintf("%s/payment?transaction_id=%d", baseURL, transactionID)
-	}
-}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"tokogo/models"
+	"tokogo/requests"
+
+	"gorm.io/gorm"
+)
+
+// seedCheckoutProduct membuat satu product dengan stok tertentu dan sejumlah customer
+// yang masing-masing menaruh product itu di cart-nya
+func seedCheckoutProduct(t *testing.T, db *gorm.DB, stock, buyers int) (models.Product, []models.User) {
+	t.Helper()
+
+	category := models.Category{Name: "Test", Slug: "test"}
+	if err := db.Create(&category).Error; err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	product := models.Product{
+		Name:          "Last Unit",
+		Slug:          "last-unit",
+		PurchasePrice: 5000,
+		SellingPrice:  10000,
+		Stock:         stock,
+		CategoryID:    category.ID,
+		Status:        models.ProductStatusPublished,
+	}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	users := make([]models.User, buyers)
+	for i := range users {
+		users[i] = models.User{Name: fmt.Sprintf("Buyer %d", i), Email: fmt.Sprintf("buyer%d@example.com", i), Password: "secret", Role: "customer"}
+		if err := db.Create(&users[i]).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		cart := models.Cart{UserID: users[i].ID, ProductID: product.ID, Quantity: 1}
+		if err := db.Create(&cart).Error; err != nil {
+			t.Fatalf("create cart: %v", err)
+		}
+	}
+	return product, users
+}
+
+func TestProcessCheckoutConcurrentLastUnit(t *testing.T) {
+	db := openTestDB(t)
+	product, users := seedCheckoutProduct(t, db, 1, 8)
+	service := NewCheckoutService()
+
+	var wg sync.WaitGroup
+	results := make([]error, len(users))
+	start := make(chan struct{})
+	for i, user := range users {
+		wg.Add(1)
+		go func(i int, userID uint) {
+			defer wg.Done()
+			<-start
+			_, results[i] = service.ProcessCheckout(userID, requests.CheckoutRequest{
+				ShippingAddress: "Jl. Test 1",
+				PaymentMethod:   "e_wallet",
+			})
+		}(i, user.ID)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range results {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("succeeded checkouts = %d, want 1 (errors: %v)", succeeded, results)
+	}
+
+	var stock int
+	db.Model(&models.Product{}).Where("id = ?", product.ID).Pluck("stock", &stock)
+	if stock != 0 {
+		t.Errorf("stock = %d, want 0", stock)
+	}
+
+	var orders int64
+	db.Model(&models.Transaction{}).Count(&orders)
+	if orders != 1 {
+		t.Errorf("transactions = %d, want 1", orders)
+	}
+
+	var sold int64
+	db.Model(&models.StockMovement{}).Where("product_id = ? AND reason = ?", product.ID, models.StockMovementSale).Count(&sold)
+	if sold != 1 {
+		t.Errorf("sale movements = %d, want 1", sold)
+	}
+}
+
+// failingChargeGateway menolak setiap pembuatan tagihan
+type failingChargeGateway struct {
+	PaymentGateway
+}
+
+func (failingChargeGateway) CreateCharge(models.Transaction) (*PaymentCharge, error) {
+	return nil, errors.New("gateway unavailable")
+}
+
+func TestProcessCheckoutChargeFailureReleasesOrder(t *testing.T) {
+	db := openTestDB(t)
+	product, users := seedCheckoutProduct(t, db, 1, 1)
+	service := NewCheckoutService()
+	service.paymentGateway = failingChargeGateway{PaymentGateway: service.paymentGateway}
+
+	_, err := service.ProcessCheckout(users[0].ID, requests.CheckoutRequest{
+		ShippingAddress: "Jl. Test 1",
+		PaymentMethod:   "e_wallet",
+	})
+	if err == nil {
+		t.Fatal("checkout succeeded without a payment charge")
+	}
+
+	var transaction models.Transaction
+	if err := db.First(&transaction).Error; err != nil {
+		t.Fatalf("load transaction: %v", err)
+	}
+	if transaction.Status != models.TransactionStatusFailed {
+		t.Errorf("status = %s, want %s", transaction.Status, models.TransactionStatusFailed)
+	}
+
+	var stock int
+	db.Model(&models.Product{}).Where("id = ?", product.ID).Pluck("stock", &stock)
+	if stock != 1 {
+		t.Errorf("stock = %d, want 1 after release", stock)
+	}
+
+	var cart models.Cart
+	if err := db.Where("user_id = ? AND product_id = ?", users[0].ID, product.ID).First(&cart).Error; err != nil || cart.Quantity != 1 {
+		t.Errorf("cart not restored: %+v, %v", cart, err)
+	}
+}
+
+func TestSortCartLinesLocksInProductAndVariantOrder(t *testing.T) {
+	variant := func(id uint) *uint { return &id }
+	carts := []models.Cart{
+		{ProductID: 3, VariantID: variant(9)},
+		{ProductID: 1, VariantID: variant(5)},
+		{ProductID: 3},
+		{ProductID: 1, VariantID: variant(2)},
+		{ProductID: 2},
+	}
+
+	sortCartLines(carts)
+
+	want := []struct {
+		productID uint
+		variantID uint
+	}{{1, 2}, {1, 5}, {2, 0}, {3, 0}, {3, 9}}
+	for i, cart := range carts {
+		if cart.ProductID != want[i].productID || variantSortKey(cart.VariantID) != want[i].variantID {
+			t.Errorf("line %d = product %d variant %d, want product %d variant %d", i, cart.ProductID, variantSortKey(cart.VariantID), want[i].productID, want[i].variantID)
+		}
+	}
+}
+
+func TestLowStockAlertForSale(t *testing.T) {
+	product := models.Product{ID: 1, Name: "Kaos", Stock: 8, LowStockThreshold: 5}
+
+	tests := []struct {
+		name      string
+		cart      models.Cart
+		want      bool
+		wantStock int
+	}{
+		{name: "stays above threshold", cart: models.Cart{ProductID: 1, Product: product, Quantity: 2}, want: false},
+		{name: "crosses threshold", cart: models.Cart{ProductID: 1, Product: product, Quantity: 3}, want: true, wantStock: 5},
+		// Alert hanya dikirim sekali saat stok pertama kali menyentuh batas
+		{name: "already low", cart: models.Cart{ProductID: 1, Product: models.Product{ID: 1, Stock: 4, LowStockThreshold: 5}, Quantity: 1}, want: false},
+		// Variant memakai threshold product terhadap stoknya sendiri
+		{name: "variant crosses threshold", cart: models.Cart{ProductID: 1, Product: models.Product{ID: 1, LowStockThreshold: 5}, Variant: &models.ProductVariant{ID: 7, SKU: "KAOS-M", Stock: 6}, Quantity: 2}, want: true, wantStock: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert, ok := lowStockAlertForSale(tt.cart, 10)
+			if ok != tt.want {
+				t.Fatalf("alert = %v, want %v", ok, tt.want)
+			}
+			if ok && (alert.Stock != tt.wantStock || alert.Threshold != 5 || alert.TransactionID != 10) {
+				t.Errorf("alert = %+v, want stock %d threshold 5", alert, tt.wantStock)
+			}
+			if ok && tt.cart.Variant != nil && alert.VariantSKU != tt.cart.Variant.SKU {
+				t.Errorf("alert variant = %q, want %q", alert.VariantSKU, tt.cart.Variant.SKU)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+	"tokogo/config"
+	"tokogo/repositories"
+)
+
+type IdempotencyKeyService struct {
+	idempotencyRepo *repositories.IdempotencyRepository
+	interval        time.Duration
+}
+
+// NewIdempotencyKeyService membuat instance baru IdempotencyKeyService.
+// Interval pembersihan dibaca dari IDEMPOTENCY_KEY_CLEANUP_INTERVAL_SECONDS.
+func NewIdempotencyKeyService() *IdempotencyKeyService {
+	return &IdempotencyKeyService{
+		idempotencyRepo: repositories.NewIdempotencyRepository(config.DB),
+		interval:        time.Duration(getEnvInt("IDEMPOTENCY_KEY_CLEANUP_INTERVAL_SECONDS", 3600)) * time.Second,
+	}
+}
+
+// Start menghapus idempotency key kedaluwarsa secara berkala sampai ctx dibatalkan
+func (s *IdempotencyKeyService) Start(ctx context.Context) {
+	startPeriodicJob(ctx, s.interval, func() {
+		purged, err := s.PurgeExpired()
+		if err != nil {
+			log.Printf("Idempotency key cleanup failed: %v", err)
+		}
+		if purged > 0 {
+			log.Printf("Purged %d expired idempotency key(s)", purged)
+		}
+	})
+}
+
+// PurgeExpired menghapus idempotency key yang sudah melewati masa berlakunya.
+// Key kedaluwarsa sudah diperlakukan sebagai key baru oleh middleware, job ini hanya membersihkan row-nya.
+func (s *IdempotencyKeyService) PurgeExpired() (int64, error) {
+	return s.idempotencyRepo.DeleteExpired(time.Now())
+}
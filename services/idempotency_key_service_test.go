@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"tokogo/models"
+)
+
+func TestPurgeExpiredIdempotencyKeys(t *testing.T) {
+	db := openTestDB(t)
+
+	keys := []models.IdempotencyKey{
+		{UserID: 1, Key: "expired", Method: "POST", Path: "/api/checkout", RequestHash: "a", ExpiresAt: time.Now().Add(-time.Minute)},
+		{UserID: 1, Key: "active", Method: "POST", Path: "/api/checkout", RequestHash: "b", ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	if err := db.Create(&keys).Error; err != nil {
+		t.Fatalf("create keys: %v", err)
+	}
+
+	purged, err := NewIdempotencyKeyService().PurgeExpired()
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("purged = %d, want 1", purged)
+	}
+
+	var remaining []string
+	db.Model(&models.IdempotencyKey{}).Pluck("`key`", &remaining)
+	if len(remaining) != 1 || remaining[0] != "active" {
+		t.Errorf("remaining keys = %v, want [active]", remaining)
+	}
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"tokogo/config"
+	"tokogo/models"
+	"tokogo/repositories"
+	"tokogo/requests"
+	"tokogo/responses"
+
+	"gorm.io/gorm"
+)
+
+// StockAdjustment menjelaskan satu perubahan stok yang akan dicatat di ledger.
+// VariantID nil berarti stok product itu sendiri, selain itu stok variant-nya.
+type StockAdjustment struct {
+	ProductID   uint
+	VariantID   *uint
+	Delta       int
+	Reason      string
+	ReferenceID uint
+	ActorID     uint
+	Note        string
+}
+
+// InventoryService adalah satu-satunya jalan untuk mengubah Product.Stock dan ProductVariant.Stock.
+// Setiap perubahan stok selalu ditulis ke ledger stock_movements.
+type InventoryService struct {
+	db           *gorm.DB
+	productRepo  *repositories.ProductRepository
+	variantRepo  *repositories.ProductVariantRepository
+	movementRepo *repositories.StockMovementRepository
+}
+
+// NewInventoryService membuat instance baru InventoryService
+func NewInventoryService() *InventoryService {
+	return &InventoryService{
+		db:           config.DB,
+		productRepo:  repositories.NewProductRepository(config.DB),
+		variantRepo:  repositories.NewProductVariantRepository(config.DB),
+		movementRepo: repositories.NewStockMovementRepository(config.DB),
+	}
+}
+
+// WithTx mengembalikan InventoryService yang berjalan di dalam database transaction tx
+func (s *InventoryService) WithTx(tx *gorm.DB) *InventoryService {
+	return &InventoryService{
+		db:           tx,
+		productRepo:  s.productRepo.WithTx(tx),
+		variantRepo:  s.variantRepo.WithTx(tx),
+		movementRepo: s.movementRepo.WithTx(tx),
+	}
+}
+
+// AdjustStock mengubah stok product dan mencatatnya di ledger secara atomik.
+// Pengurangan stok gagal dengan repositories.ErrInsufficientStock bila stok tidak cukup.
+func (s *InventoryService) AdjustStock(adjustment StockAdjustment) error {
+	if adjustment.Delta == 0 {
+		return nil
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		movementRepo := s.movementRepo.WithTx(tx)
+
+		if err := s.applyStockDelta(tx, adjustment); err != nil {
+			return err
+		}
+
+		movement := &models.StockMovement{
+			ProductID: adjustment.ProductID,
+			VariantID: adjustment.VariantID,
+			Delta:     adjustment.Delta,
+			Reason:    adjustment.Reason,
+			Note:      adjustment.Note,
+		}
+		if adjustment.ReferenceID != 0 {
+			movement.ReferenceID = &adjustment.ReferenceID
+		}
+		if adjustment.ActorID != 0 {
+			movement.ActorID = &adjustment.ActorID
+		}
+
+		if err := movementRepo.Create(movement); err != nil {
+			return errors.New("failed to record stock movement")
+		}
+
+		return nil
+	})
+}
+
+// applyStockDelta mengubah kolom stok product atau variant sesuai adjustment
+func (s *InventoryService) applyStockDelta(tx *gorm.DB, adjustment StockAdjustment) error {
+	if adjustment.VariantID != nil {
+		variantRepo := s.variantRepo.WithTx(tx)
+		if adjustment.Delta < 0 {
+			return variantRepo.DecrementStock(*adjustment.VariantID, -adjustment.Delta)
+		}
+		return variantRepo.IncrementStock(*adjustment.VariantID, adjustment.Delta)
+	}
+
+	productRepo := s.productRepo.WithTx(tx)
+	if adjustment.Delta < 0 {
+		return productRepo.DecrementStock(adjustment.ProductID, -adjustment.Delta)
+	}
+	return productRepo.IncrementStock(adjustment.ProductID, adjustment.Delta)
+}
+
+// AdjustProductStock melakukan penyesuaian stok manual oleh admin
+func (s *InventoryService) AdjustProductStock(productID uint, actorID uint, req requests.AdjustStockRequest) (*responses.StockReconciliationResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	if req.VariantID != nil {
+		if _, err := s.variantRepo.GetByIDAndProductID(*req.VariantID, productID); err != nil {
+			return nil, errors.New("variant not found")
+		}
+	}
+
+	err := s.AdjustStock(StockAdjustment{
+		ProductID: productID,
+		VariantID: req.VariantID,
+		Delta:     req.Delta,
+		Reason:    req.Reason,
+		ActorID:   actorID,
+		Note:      req.Note,
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrInsufficientStock) {
+			return nil, errors.New("stock cannot become negative")
+		}
+		return nil, err
+	}
+
+	return s.ReconcileStock(productID)
+}
+
+// GetStockMovements mengambil riwayat pergerakan stok product
+func (s *InventoryService) GetStockMovements(productID uint, page, limit int) (*responses.StockMovementListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	movements, total, err := s.movementRepo.GetByProductID(productID, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to get stock movements")
+	}
+
+	return &responses.StockMovementListResponse{
+		Movements: responses.ConvertStockMovementsToResponse(movements),
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	}, nil
+}
+
+// ReconcileStock membandingkan stok product dan setiap variant-nya dengan jumlah ledger masing-masing
+func (s *InventoryService) ReconcileStock(productID uint) (*responses.StockReconciliationResponse, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	ledgerStock, err := s.movementRepo.SumDeltaByProductID(productID, nil)
+	if err != nil {
+		return nil, errors.New("failed to sum stock movements")
+	}
+
+	response := &responses.StockReconciliationResponse{
+		ProductID:    product.ID,
+		ProductName:  product.Name,
+		CurrentStock: product.Stock,
+		LedgerStock:  ledgerStock,
+		Difference:   product.Stock - ledgerStock,
+		InSync:       product.Stock == ledgerStock,
+	}
+
+	for _, variant := range product.Variants {
+		variantLedger, err := s.movementRepo.SumDeltaByProductID(productID, &variant.ID)
+		if err != nil {
+			return nil, errors.New("failed to sum stock movements")
+		}
+
+		response.Variants = append(response.Variants, responses.VariantStockReconciliationResponse{
+			VariantID:    variant.ID,
+			SKU:          variant.SKU,
+			CurrentStock: variant.Stock,
+			LedgerStock:  variantLedger,
+			Difference:   variant.Stock - variantLedger,
+			InSync:       variant.Stock == variantLedger,
+		})
+		if variant.Stock != variantLedger {
+			response.InSync = false
+		}
+	}
+
+	return response, nil
+}
+
+// BackfillOpeningBalances mencatat saldo awal ledger untuk stok product dan variant yang sudah ada
+// sebelum ledger diperkenalkan, sehingga ReconcileStock tidak melaporkannya sebagai selisih
+func (s *InventoryService) BackfillOpeningBalances() error {
+	for {
+		productIDs, err := s.movementRepo.GetProductIDsWithoutOpeningBalance(100)
+		if err != nil {
+			return err
+		}
+		if len(productIDs) == 0 {
+			break
+		}
+
+		for _, productID := range productIDs {
+			if err := s.recordOpeningBalance(productID, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	for {
+		variants, err := s.movementRepo.GetVariantsWithoutOpeningBalance(100)
+		if err != nil || len(variants) == 0 {
+			return err
+		}
+
+		for _, variant := range variants {
+			if err := s.recordOpeningBalance(variant.ProductID, &variant.ID); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// recordOpeningBalance menulis selisih stok dengan jumlah ledger sebagai saldo awal. Row product
+// atau variant dikunci agar checkout yang berjalan bersamaan tidak mengubah stok di tengah perhitungan.
+func (s *InventoryService) recordOpeningBalance(productID uint, variantID *uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		movementRepo := s.movementRepo.WithTx(tx)
+
+		var stock int
+		if variantID != nil {
+			variant, err := s.variantRepo.WithTx(tx).GetByIDForUpdate(*variantID)
+			if err != nil {
+				return err
+			}
+			stock = variant.Stock
+		} else {
+			product, err := s.productRepo.WithTx(tx).GetByIDForUpdate(productID)
+			if err != nil {
+				return err
+			}
+			stock = product.Stock
+		}
+
+		exists, err := movementRepo.HasOpeningBalance(productID, variantID)
+		if err != nil || exists {
+			return err
+		}
+
+		ledgerStock, err := movementRepo.SumDeltaByProductID(productID, variantID)
+		if err != nil {
+			return err
+		}
+		if stock == ledgerStock {
+			return nil
+		}
+
+		return movementRepo.Create(&models.StockMovement{
+			ProductID: productID,
+			VariantID: variantID,
+			Delta:     stock - ledgerStock,
+			Reason:    models.StockMovementOpeningBalance,
+			Note:      "Opening balance for stock recorded before the stock ledger",
+		})
+	})
+}
+
+// releaseTransaction mengembalikan quantity setiap detail transaksi ke stok product (atau variant-nya)
+// dan mencatatnya di ledger, lalu mengembalikan kuota voucher yang dipakai transaksi tersebut.
+// Repository dan inventory harus terikat pada database transaction milik pemanggil.
+func releaseTransaction(transactionRepo *repositories.TransactionRepository, inventory *InventoryService, transactionID uint, actorID uint, note string) error {
+	details, err := transactionRepo.GetDetailsByTransactionID(transactionID)
+	if err != nil {
+		return errors.New("failed to get transaction details")
+	}
+
+	for _, detail := range details {
+		err := inventory.AdjustStock(StockAdjustment{
+			ProductID:   detail.ProductID,
+			VariantID:   detail.VariantID,
+			Delta:       detail.Quantity,
+			Reason:      models.StockMovementCancellation,
+			ReferenceID: transactionID,
+			ActorID:     actorID,
+			Note:        note,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to restore stock for product %d", detail.ProductID)
+		}
+	}
+
+	if err := transactionRepo.ReleaseVoucherRedemption(transactionID); err != nil {
+		return errors.New("failed to release voucher usage")
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+
+	"tokogo/models"
+)
+
+func TestBackfillOpeningBalancesReconcilesLegacyStock(t *testing.T) {
+	db := openTestDB(t)
+
+	category := models.Category{Name: "Test", Slug: "test"}
+	if err := db.Create(&category).Error; err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+
+	// Product lama: stok 10 tanpa ledger, lalu satu penjualan 2 unit tercatat setelah ledger ada
+	legacy := models.Product{Name: "Legacy", Slug: "legacy", PurchasePrice: 1000, SellingPrice: 2000, Stock: 8, CategoryID: category.ID}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	variant := models.ProductVariant{ProductID: legacy.ID, SKU: "LEGACY-M", Options: map[string]string{"size": "M"}, Stock: 5}
+	if err := db.Create(&variant).Error; err != nil {
+		t.Fatalf("create variant: %v", err)
+	}
+	if err := db.Create(&models.StockMovement{ProductID: legacy.ID, Delta: -2, Reason: models.StockMovementSale}).Error; err != nil {
+		t.Fatalf("create movement: %v", err)
+	}
+
+	service := NewInventoryService()
+	for i := 0; i < 2; i++ {
+		if err := service.BackfillOpeningBalances(); err != nil {
+			t.Fatalf("backfill: %v", err)
+		}
+	}
+
+	var openings []models.StockMovement
+	db.Where("reason = ?", models.StockMovementOpeningBalance).Order("id ASC").Find(&openings)
+	if len(openings) != 2 {
+		t.Fatalf("opening balances = %d, want 2 (one for the product, one for the variant)", len(openings))
+	}
+	if openings[0].VariantID != nil || openings[0].Delta != 10 {
+		t.Errorf("product opening balance = %+v, want delta 10", openings[0])
+	}
+	if openings[1].VariantID == nil || *openings[1].VariantID != variant.ID || openings[1].Delta != 5 {
+		t.Errorf("variant opening balance = %+v, want delta 5", openings[1])
+	}
+
+	reconciliation, err := service.ReconcileStock(legacy.ID)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if !reconciliation.InSync {
+		t.Errorf("stock not in sync after backfill: %+v", reconciliation)
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"tokogo/config"
+)
+
+// LowStockAlert berisi informasi product yang stoknya turun sampai batas low stock
+type LowStockAlert struct {
+	ProductID     uint      `json:"product_id"`
+	ProductName   string    `json:"product_name"`
+	VariantID     *uint     `json:"variant_id,omitempty"`
+	VariantSKU    string    `json:"variant_sku,omitempty"`
+	Stock         int       `json:"stock"`
+	Threshold     int       `json:"threshold"`
+	TransactionID uint      `json:"transaction_id,omitempty"`
+	TriggeredAt   time.Time `json:"triggered_at"`
+}
+
+// LowStockNotifier adalah kontrak untuk mengirim peringatan low stock ke admin
+type LowStockNotifier interface {
+	NotifyLowStock(alert LowStockAlert) error
+}
+
+var (
+	defaultLowStockNotifier     LowStockNotifier
+	defaultLowStockNotifierOnce sync.Once
+)
+
+// DefaultLowStockNotifier mengembalikan notifier yang dipilih melalui LOW_STOCK_NOTIFIER (log atau file)
+func DefaultLowStockNotifier() LowStockNotifier {
+	defaultLowStockNotifierOnce.Do(func() {
+		switch config.GetEnv("LOW_STOCK_NOTIFIER", "log") {
+		case "file":
+			defaultLowStockNotifier = NewFileLowStockNotifier(config.GetEnv("LOW_STOCK_ALERT_FILE", "./logs/low_stock_alerts.log"))
+		default:
+			defaultLowStockNotifier = NewLogLowStockNotifier()
+		}
+	})
+	return defaultLowStockNotifier
+}
+
+// LogLowStockNotifier menulis peringatan low stock ke log aplikasi
+type LogLowStockNotifier struct{}
+
+// NewLogLowStockNotifier membuat instance baru LogLowStockNotifier
+func NewLogLowStockNotifier() *LogLowStockNotifier {
+	return &LogLowStockNotifier{}
+}
+
+func (n *LogLowStockNotifier) NotifyLowStock(alert LowStockAlert) error {
+	if alert.VariantSKU != "" {
+		log.Printf("Low stock alert: product %d (%s) variant %s stock %d is at or below threshold %d",
+			alert.ProductID, alert.ProductName, alert.VariantSKU, alert.Stock, alert.Threshold)
+		return nil
+	}
+	log.Printf("Low stock alert: product %d (%s) stock %d is at or below threshold %d",
+		alert.ProductID, alert.ProductName, alert.Stock, alert.Threshold)
+	return nil
+}
+
+// FileLowStockNotifier menambahkan peringatan low stock sebagai JSON per baris ke sebuah file
+type FileLowStockNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileLowStockNotifier membuat instance baru FileLowStockNotifier
+func NewFileLowStockNotifier(path string) *FileLowStockNotifier {
+	return &FileLowStockNotifier{path: path}
+}
+
+func (n *FileLowStockNotifier) NotifyLowStock(alert LowStockAlert) error {
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode low stock alert: %v", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(n.path), 0755); err != nil {
+		return fmt.Errorf("failed to create alert directory: %v", err)
+	}
+
+	file, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open alert file: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write low stock alert: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"os"
+	"testing"
+
+	"tokogo/config"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+func TestMain(m *testing.M) {
+	// Service yang dibuat di test memakai fake gateway kecuali environment sudah mengaturnya
+	if os.Getenv("PAYMENT_GATEWAY") == "" {
+		os.Setenv("PAYMENT_GATEWAY", "fake")
+		os.Setenv("PAYMENT_WEBHOOK_SECRET", "test-webhook-secret")
+	}
+	if err := InitPaymentGateway(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// openTestDB membuka database MySQL dari TEST_DATABASE_DSN, memigrasi dan mengosongkan seluruh tabel,
+// lalu memasangnya sebagai config.DB. Test di-skip bila TEST_DATABASE_DSN tidak diisi.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{SingularTable: true},
+		Logger:         logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("connect test database: %v", err)
+	}
+	if err := config.AutoMigrate(db); err != nil {
+		t.Fatalf("migrate test database: %v", err)
+	}
+
+	tables, err := db.Migrator().GetTables()
+	if err != nil {
+		t.Fatalf("list tables: %v", err)
+	}
+	// Foreign key check hanya dimatikan pada satu koneksi yang dipakai untuk truncate
+	err = db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SET FOREIGN_KEY_CHECKS = 0").Error; err != nil {
+			return err
+		}
+		for _, table := range tables {
+			if err := conn.Exec("TRUNCATE TABLE `" + table + "`").Error; err != nil {
+				return err
+			}
+		}
+		return conn.Exec("SET FOREIGN_KEY_CHECKS = 1").Error
+	})
+	if err != nil {
+		t.Fatalf("truncate test database: %v", err)
+	}
+
+	previous := config.DB
+	config.DB = db
+	t.Cleanup(func() {
+		config.DB = previous
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
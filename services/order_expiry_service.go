@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+	"tokogo/config"
+	"tokogo/models"
+	"tokogo/repositories"
+
+	"gorm.io/gorm"
+)
+
+// orderExpiryBatchSize adalah jumlah maksimal order yang diambil per query
+const orderExpiryBatchSize = 100
+
+type OrderExpiryService struct {
+	db              *gorm.DB
+	transactionRepo *repositories.TransactionRepository
+	inventory       *InventoryService
+	paymentWindow   time.Duration
+	interval        time.Duration
+}
+
+// NewOrderExpiryService membuat instance baru OrderExpiryService.
+// Payment window dan interval dibaca dari PAYMENT_WINDOW_MINUTES dan ORDER_EXPIRY_INTERVAL_SECONDS.
+func NewOrderExpiryService() *OrderExpiryService {
+	return &OrderExpiryService{
+		db:              config.DB,
+		transactionRepo: repositories.NewTransactionRepository(config.DB),
+		inventory:       NewInventoryService(),
+		paymentWindow:   paymentWindow(),
+		interval:        time.Duration(getEnvInt("ORDER_EXPIRY_INTERVAL_SECONDS", 60)) * time.Second,
+	}
+}
+
+// Start menjalankan job expiry secara berkala sampai ctx dibatalkan
+func (s *OrderExpiryService) Start(ctx context.Context) {
+	startPeriodicJob(ctx, s.interval, func() {
+		expired, err := s.ExpirePendingOrders()
+		if err != nil {
+			log.Printf("Order expiry job failed: %v", err)
+		}
+		if expired > 0 {
+			log.Printf("Order expiry job expired %d order(s)", expired)
+		}
+	})
+}
+
+// ExpirePendingOrders meng-expire order pending non-COD yang melewati batas pembayarannya
+// dan mengembalikan stoknya. Setiap order diproses dalam database transaction sendiri
+// dengan row lock, sehingga aman dijalankan bersamaan di beberapa instance. Order yang gagal
+// di-expire dilewati agar tidak menahan order lain, dan dicoba lagi di putaran berikutnya.
+func (s *OrderExpiryService) ExpirePendingOrders() (int, error) {
+	now := time.Now()
+
+	expired := 0
+	var lastID uint
+	for {
+		ids, err := s.transactionRepo.GetExpiredPendingIDs(now, now.Add(-s.paymentWindow), lastID, orderExpiryBatchSize)
+		if err != nil {
+			return expired, err
+		}
+
+		for _, id := range ids {
+			lastID = id
+			ok, err := s.expireOrder(id, now)
+			if err != nil {
+				log.Printf("Failed to expire transaction %d: %v", id, err)
+				continue
+			}
+			if ok {
+				expired++
+			}
+		}
+
+		if len(ids) < orderExpiryBatchSize {
+			return expired, nil
+		}
+	}
+}
+
+// expireOrder meng-expire satu order. Mengembalikan false bila order sudah diproses pihak lain.
+func (s *OrderExpiryService) expireOrder(id uint, now time.Time) (bool, error) {
+	expired := false
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		inventory := s.inventory.WithTx(tx)
+
+		transaction, err := transactionRepo.GetByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		// Cek ulang setelah row terkunci, instance lain atau customer mungkin sudah mengubahnya
+		if transaction.Status != models.TransactionStatusPending ||
+			transaction.PaymentMethod == models.PaymentMethodCOD ||
+			!transaction.IsPaymentOverdue(now, s.paymentWindow) {
+			return nil
+		}
+
+		if err := releaseTransaction(transactionRepo, inventory, transaction.ID, 0, "Order expired"); err != nil {
+			return err
+		}
+
+		if err := changeTransactionStatus(transactionRepo, transaction, models.TransactionStatusExpired, 0, models.ActorRoleSystem, "Payment window elapsed"); err != nil {
+			return err
+		}
+
+		expired = true
+		return nil
+	})
+
+	return expired, err
+}
+
+// paymentWindow mengembalikan lama waktu pembayaran order non-COD dari PAYMENT_WINDOW_MINUTES
+func paymentWindow() time.Duration {
+	return time.Duration(getEnvInt("PAYMENT_WINDOW_MINUTES", 60)) * time.Minute
+}
+
+// getEnvInt membaca environment variable bertipe integer positif
+func getEnvInt(key string, defaultVal int) int {
+	value, err := strconv.Atoi(config.GetEnv(key, strconv.Itoa(defaultVal)))
+	if err != nil || value < 1 {
+		return defaultVal
+	}
+	return value
+}
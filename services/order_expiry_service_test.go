@@ -0,0 +1,97 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"tokogo/models"
+	"tokogo/requests"
+)
+
+func TestRejectedPaymentProofRestartsPaymentWindow(t *testing.T) {
+	db := openTestDB(t)
+
+	user := models.User{Name: "Buyer", Email: "buyer@example.com", Password: "secret", Role: "customer"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	// Bukti diunggah mendekati akhir window, lalu admin menolaknya setelah window habis
+	dueAt := time.Now().Add(-time.Hour)
+	transaction := models.Transaction{
+		UserID:          user.ID,
+		Status:          models.TransactionStatusAwaitingVerification,
+		TotalAmount:     10000,
+		ShippingAddress: "Jl. Test 1",
+		PaymentMethod:   models.PaymentMethodBankTransfer,
+		PaymentDueAt:    &dueAt,
+		CreatedAt:       time.Now().Add(-2 * time.Hour),
+	}
+	if err := db.Create(&transaction).Error; err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+
+	if _, err := NewTransactionService().RejectPaymentProof(transaction.ID, 1, requests.RejectPaymentProofRequest{Note: "Nominal tidak sesuai"}); err != nil {
+		t.Fatalf("reject payment proof: %v", err)
+	}
+
+	expiry := NewOrderExpiryService()
+	expired, err := expiry.ExpirePendingOrders()
+	if err != nil {
+		t.Fatalf("expire: %v", err)
+	}
+	if expired != 0 {
+		t.Fatalf("expired = %d, want 0 right after the payment proof was rejected", expired)
+	}
+
+	var reloaded models.Transaction
+	db.First(&reloaded, transaction.ID)
+	if reloaded.Status != models.TransactionStatusPending || reloaded.PaymentDueAt == nil || !reloaded.PaymentDueAt.After(time.Now()) {
+		t.Fatalf("transaction after rejection = status %s, due %v", reloaded.Status, reloaded.PaymentDueAt)
+	}
+
+	// Setelah window baru habis, order di-expire
+	db.Model(&reloaded).Update("payment_due_at", time.Now().Add(-time.Minute))
+	if expired, err := expiry.ExpirePendingOrders(); err != nil || expired != 1 {
+		t.Errorf("expired = %d, %v, want 1", expired, err)
+	}
+}
+
+func TestExpirePendingOrdersPagesPastFirstBatch(t *testing.T) {
+	db := openTestDB(t)
+
+	user := models.User{Name: "Buyer", Email: "buyer@example.com", Password: "secret", Role: "customer"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	dueAt := time.Now().Add(-time.Hour)
+	transactions := make([]models.Transaction, orderExpiryBatchSize+1)
+	for i := range transactions {
+		transactions[i] = models.Transaction{
+			UserID:          user.ID,
+			Status:          models.TransactionStatusPending,
+			TotalAmount:     10000,
+			ShippingAddress: "Jl. Test 1",
+			PaymentMethod:   "e_wallet",
+			PaymentDueAt:    &dueAt,
+		}
+	}
+	if err := db.CreateInBatches(&transactions, 50).Error; err != nil {
+		t.Fatalf("create transactions: %v", err)
+	}
+
+	expired, err := NewOrderExpiryService().ExpirePendingOrders()
+	if err != nil {
+		t.Fatalf("expire: %v", err)
+	}
+	if expired != len(transactions) {
+		t.Errorf("expired = %d, want %d", expired, len(transactions))
+	}
+
+	var pending int64
+	db.Model(&models.Transaction{}).Where("status = ?", models.TransactionStatusPending).Count(&pending)
+	if pending != 0 {
+		t.Errorf("pending transactions = %d, want 0", pending)
+	}
+}
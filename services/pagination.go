@@ -0,0 +1,20 @@
+package services
+
+import "tokogo/repositories"
+
+// ErrInvalidCursor dikembalikan jika token next_cursor dari client tidak valid
+var ErrInvalidCursor = repositories.ErrInvalidCursor
+
+// maxCursorLimit membatasi jumlah baris per halaman pada listing berbasis cursor
+const maxCursorLimit = 100
+
+// normalizeCursorLimit memberi nilai default dan batas atas limit untuk listing berbasis cursor
+func normalizeCursorLimit(limit int) int {
+	if limit < 1 {
+		return 10
+	}
+	if limit > maxCursorLimit {
+		return maxCursorLimit
+	}
+	return limit
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"tokogo/config"
+	"tokogo/models"
+)
+
+// ErrInvalidSignature dikembalikan ketika signature notifikasi tidak valid
+var ErrInvalidSignature = errors.New("invalid payment notification signature")
+
+// PaymentCharge adalah hasil pembuatan tagihan di payment gateway
+type PaymentCharge struct {
+	Reference  string
+	PaymentURL string
+}
+
+// PaymentNotification adalah notifikasi dari payment gateway yang sudah diverifikasi
+type PaymentNotification struct {
+	EventID       string
+	Reference     string
+	TransactionID uint
+	Status        string
+	Amount        float64
+}
+
+// PaymentGateway adalah kontrak yang harus dipenuhi setiap provider pembayaran
+type PaymentGateway interface {
+	// Name mengembalikan nama provider
+	Name() string
+	// CreateCharge membuat tagihan untuk transaksi
+	CreateCharge(transaction models.Transaction) (*PaymentCharge, error)
+	// VerifyNotification memverifikasi signature dan mem-parsing payload webhook
+	VerifyNotification(payload []byte, signature string) (*PaymentNotification, error)
+	// QueryStatus menanyakan status pembayaran terbaru ke provider
+	QueryStatus(reference string) (string, error)
+	// Refund mengembalikan dana untuk tagihan. Harus idempotent per reference: refund yang diulang
+	// untuk tagihan yang sudah di-refund tidak boleh mengembalikan dana dua kali.
+	Refund(reference string, amount float64) error
+}
+
+var (
+	defaultPaymentGateway     PaymentGateway
+	defaultPaymentGatewayErr  error
+	defaultPaymentGatewayOnce sync.Once
+)
+
+// InitPaymentGateway memilih payment gateway melalui PAYMENT_GATEWAY dan memastikan secret webhook-nya
+// diisi. Tidak ada provider default, gateway fake hanya dipakai bila dipilih secara eksplisit.
+// Dipanggil saat aplikasi start agar konfigurasi yang salah langsung menghentikan aplikasi.
+func InitPaymentGateway() error {
+	defaultPaymentGatewayOnce.Do(func() {
+		defaultPaymentGateway, defaultPaymentGatewayErr = newPaymentGatewayFromEnv()
+	})
+	return defaultPaymentGatewayErr
+}
+
+// DefaultPaymentGateway mengembalikan payment gateway yang sudah diinisialisasi oleh InitPaymentGateway
+func DefaultPaymentGateway() PaymentGateway {
+	if err := InitPaymentGateway(); err != nil {
+		panic(fmt.Sprintf("payment gateway is not configured: %v", err))
+	}
+	return defaultPaymentGateway
+}
+
+func newPaymentGatewayFromEnv() (PaymentGateway, error) {
+	// Secret tidak punya default karena siapa pun yang tahu secret bisa memalsukan webhook
+	secret := config.GetEnv("PAYMENT_WEBHOOK_SECRET", "")
+	if secret == "" {
+		return nil, errors.New("PAYMENT_WEBHOOK_SECRET must be set")
+	}
+
+	provider := config.GetEnv("PAYMENT_GATEWAY", "")
+	switch provider {
+	case "fake":
+		log.Println("Warning: using fake payment gateway, do not use in production")
+		return NewFakePaymentGateway(secret), nil
+	case "":
+		return nil, errors.New("PAYMENT_GATEWAY must be set")
+	default:
+		return nil, fmt.Errorf("unknown PAYMENT_GATEWAY %q", provider)
+	}
+}
+
+// fakeNotificationPayload adalah format payload webhook FakePaymentGateway
+type fakeNotificationPayload struct {
+	EventID       string  `json:"event_id"`
+	Reference     string  `json:"reference"`
+	TransactionID uint    `json:"transaction_id"`
+	Status        string  `json:"status"`
+	Amount        float64 `json:"amount"`
+}
+
+// FakePaymentGateway adalah provider pembayaran in-memory untuk development dan testing.
+// Webhook ditandatangani dengan HMAC-SHA256 memakai secret yang sama.
+type FakePaymentGateway struct {
+	secret   []byte
+	mu       sync.Mutex
+	statuses map[string]string
+}
+
+// NewFakePaymentGateway membuat instance baru FakePaymentGateway
+func NewFakePaymentGateway(secret string) *FakePaymentGateway {
+	return &FakePaymentGateway{
+		secret:   []byte(secret),
+		statuses: make(map[string]string),
+	}
+}
+
+func (g *FakePaymentGateway) Name() string {
+	return "fake"
+}
+
+func (g *FakePaymentGateway) CreateCharge(transaction models.Transaction) (*PaymentCharge, error) {
+	reference := fmt.Sprintf("FAKE-%d", transaction.ID)
+
+	g.mu.Lock()
+	g.statuses[reference] = models.TransactionStatusPending
+	g.mu.Unlock()
+
+	baseURL := "https://payment.example.com"
+	var paymentURL string
+	switch transaction.PaymentMethod {
+	case "bank_transfer":
+		paymentURL = fmt.Sprintf("%s/bank-transfer?reference=%s", baseURL, reference)
+	case "credit_card":
+		paymentURL = fmt.Sprintf("%s/credit-card?reference=%s", baseURL, reference)
+	case "e_wallet":
+		paymentURL = fmt.Sprintf("%s/e-wallet?reference=%s", baseURL, reference)
+	default:
+		paymentURL = fmt.Sprintf("%s/payment?reference=%s", baseURL, reference)
+	}
+
+	return &PaymentCharge{
+		Reference:  reference,
+		PaymentURL: paymentURL,
+	}, nil
+}
+
+func (g *FakePaymentGateway) VerifyNotification(payload []byte, signature string) (*PaymentNotification, error) {
+	expected := g.Sign(payload)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var body fakeNotificationPayload
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, errors.New("invalid payment notification payload")
+	}
+	if body.EventID == "" || body.Reference == "" || body.TransactionID == 0 {
+		return nil, errors.New("payment notification is missing required fields")
+	}
+
+	g.mu.Lock()
+	g.statuses[body.Reference] = body.Status
+	g.mu.Unlock()
+
+	return &PaymentNotification{
+		EventID:       body.EventID,
+		Reference:     body.Reference,
+		TransactionID: body.TransactionID,
+		Status:        body.Status,
+		Amount:        body.Amount,
+	}, nil
+}
+
+func (g *FakePaymentGateway) QueryStatus(reference string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	status, ok := g.statuses[reference]
+	if !ok {
+		return "", errors.New("payment reference not found")
+	}
+	return status, nil
+}
+
+func (g *FakePaymentGateway) Refund(reference string, amount float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.statuses[reference]; !ok {
+		return errors.New("payment reference not found")
+	}
+	g.statuses[reference] = models.TransactionStatusRefunded
+	return nil
+}
+
+// Sign menghasilkan signature HMAC-SHA256 (hex) untuk payload webhook
+func (g *FakePaymentGateway) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,50 @@
+package services
+
+import "testing"
+
+func TestNewPaymentGatewayFromEnvFailsClosed(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		secret   string
+		wantErr  bool
+	}{
+		{name: "no provider", provider: "", secret: "secret", wantErr: true},
+		{name: "unknown provider", provider: "midtrans-typo", secret: "secret", wantErr: true},
+		{name: "no webhook secret", provider: "fake", secret: "", wantErr: true},
+		{name: "explicit fake gateway", provider: "fake", secret: "secret", wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PAYMENT_GATEWAY", tt.provider)
+			t.Setenv("PAYMENT_WEBHOOK_SECRET", tt.secret)
+
+			gateway, err := newPaymentGatewayFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && gateway.Name() != tt.provider {
+				t.Errorf("gateway = %s, want %s", gateway.Name(), tt.provider)
+			}
+		})
+	}
+}
+
+func TestFakePaymentGatewayRejectsForgedNotification(t *testing.T) {
+	gateway := NewFakePaymentGateway("real-secret")
+	payload := []byte(`{"event_id":"evt-1","reference":"FAKE-1","transaction_id":1,"status":"paid","amount":10000}`)
+
+	forged := NewFakePaymentGateway("your-webhook-secret").Sign(payload)
+	if _, err := gateway.VerifyNotification(payload, forged); err != ErrInvalidSignature {
+		t.Errorf("forged signature err = %v, want ErrInvalidSignature", err)
+	}
+
+	notification, err := gateway.VerifyNotification(payload, gateway.Sign(payload))
+	if err != nil {
+		t.Fatalf("valid signature: %v", err)
+	}
+	if notification.EventID != "evt-1" || notification.TransactionID != 1 {
+		t.Errorf("unexpected notification %+v", notification)
+	}
+}
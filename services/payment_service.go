@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"tokogo/config"
+	"tokogo/models"
+	"tokogo/repositories"
+
+	"gorm.io/gorm"
+)
+
+// errDuplicatePaymentEvent menandakan event gateway sudah pernah diproses
+var errDuplicatePaymentEvent = errors.New("duplicate event")
+
+// ErrLatePayment dikembalikan ketika pembayaran diterima untuk order yang sudah dibatalkan, gagal
+// atau kedaluwarsa. Transaksinya ditandai agar dana dikembalikan lewat refund oleh admin.
+var ErrLatePayment = errors.New("payment received for a closed order, flagged for refund")
+
+type PaymentService struct {
+	db               *gorm.DB
+	gateway          PaymentGateway
+	transactionRepo  *repositories.TransactionRepository
+	inventory        *InventoryService
+	notificationRepo *repositories.PaymentNotificationRepository
+}
+
+// NewPaymentService membuat instance baru PaymentService
+func NewPaymentService() *PaymentService {
+	return &PaymentService{
+		db:               config.DB,
+		gateway:          DefaultPaymentGateway(),
+		transactionRepo:  repositories.NewTransactionRepository(config.DB),
+		inventory:        NewInventoryService(),
+		notificationRepo: repositories.NewPaymentNotificationRepository(config.DB),
+	}
+}
+
+// maxUnverifiedPayloadLog adalah panjang maksimal payload yang disimpan dari notifikasi dengan signature tidak valid
+const maxUnverifiedPayloadLog = 1024
+
+// HandleNotification memverifikasi dan memproses webhook dari payment gateway.
+// Setiap notifikasi selalu dicatat, payload notifikasi yang signature-nya tidak valid hanya disimpan
+// sebagian. Notifikasi yang sama hanya diproses sekali.
+func (s *PaymentService) HandleNotification(payload []byte, signature string) error {
+	record := &models.PaymentNotification{
+		Gateway:   s.gateway.Name(),
+		Payload:   string(payload),
+		Signature: signature,
+	}
+
+	notification, err := s.gateway.VerifyNotification(payload, signature)
+	if err != nil {
+		if len(payload) > maxUnverifiedPayloadLog {
+			record.Payload = string(payload[:maxUnverifiedPayloadLog])
+		}
+		if len(record.Signature) > 255 {
+			record.Signature = record.Signature[:255]
+		}
+		record.Error = err.Error()
+		s.notificationRepo.Create(record)
+		return err
+	}
+
+	record.SignatureValid = true
+	record.EventID = notification.EventID
+	record.TransactionID = &notification.TransactionID
+	record.Status = notification.Status
+	if err := s.notificationRepo.Create(record); err != nil {
+		return errors.New("failed to log payment notification")
+	}
+
+	if err := s.applyNotification(notification); err != nil {
+		record.Error = err.Error()
+		// Pembayaran terlambat sudah ditandai pada transaksinya sehingga event tetap dianggap diproses
+		record.Processed = errors.Is(err, ErrLatePayment)
+		s.notificationRepo.Update(record)
+		if errors.Is(err, errDuplicatePaymentEvent) {
+			return nil
+		}
+		return err
+	}
+
+	record.Processed = true
+	if err := s.notificationRepo.Update(record); err != nil {
+		return errors.New("failed to update payment notification")
+	}
+
+	return nil
+}
+
+// applyNotification mengupdate status transaksi berdasarkan notifikasi. Event dicatat sebagai sudah
+// diproses di transaction yang sama, sehingga pengiriman ulang mengembalikan errDuplicatePaymentEvent
+// dan event yang gagal diproses boleh dikirim ulang. Status yang sudah sesuai dibiarkan apa adanya.
+// Pembayaran untuk order yang sudah ditutup ditandai pada transaksinya dan mengembalikan ErrLatePayment.
+func (s *PaymentService) applyNotification(notification *PaymentNotification) error {
+	latePayment := false
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		inventory := s.inventory.WithTx(tx)
+
+		marked, err := s.notificationRepo.WithTx(tx).MarkEventProcessed(s.gateway.Name(), notification.EventID, notification.TransactionID)
+		if err != nil {
+			return errors.New("failed to record payment notification")
+		}
+		if !marked {
+			return errDuplicatePaymentEvent
+		}
+
+		transaction, err := transactionRepo.GetByIDForUpdate(notification.TransactionID)
+		if err != nil {
+			return errors.New("transaction not found")
+		}
+
+		if transaction.PaymentReference != notification.Reference {
+			return errors.New("payment reference does not match transaction")
+		}
+
+		switch notification.Status {
+		case models.TransactionStatusPaid:
+			switch transaction.Status {
+			case models.TransactionStatusCancelled, models.TransactionStatusFailed, models.TransactionStatusExpired:
+				// Stok order sudah dilepas, dana customer harus dikembalikan
+				if err := transactionRepo.MarkLatePayment(transaction.ID); err != nil {
+					return errors.New("failed to flag late payment")
+				}
+				latePayment = true
+				return nil
+			}
+			// Order bank transfer bisa sudah menunggu verifikasi bukti transfer saat gateway mengonfirmasi pembayaran
+			if transaction.Status != models.TransactionStatusPending && transaction.Status != models.TransactionStatusAwaitingVerification {
+				return nil
+			}
+			if math.Abs(notification.Amount-transaction.TotalAmount) > 0.005 {
+				return fmt.Errorf("paid amount %.2f does not match transaction total %.2f", notification.Amount, transaction.TotalAmount)
+			}
+			return changeTransactionStatus(transactionRepo, transaction, models.TransactionStatusPaid, 0, models.ActorRoleSystem, "Payment confirmed by "+s.gateway.Name())
+
+		case models.TransactionStatusFailed, models.TransactionStatusExpired:
+			if transaction.Status != models.TransactionStatusPending {
+				return nil
+			}
+			if err := releaseTransaction(transactionRepo, inventory, transaction.ID, 0, "Order "+notification.Status); err != nil {
+				return err
+			}
+			return changeTransactionStatus(transactionRepo, transaction, notification.Status, 0, models.ActorRoleSystem, "Payment "+notification.Status+" reported by "+s.gateway.Name())
+
+		case models.TransactionStatusPending:
+			return nil
+		}
+
+		return fmt.Errorf("unsupported payment status %s", notification.Status)
+	})
+	if err != nil {
+		return err
+	}
+	if latePayment {
+		return ErrLatePayment
+	}
+	return nil
+}
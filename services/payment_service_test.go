@@ -0,0 +1,110 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"tokogo/models"
+	"tokogo/requests"
+)
+
+func TestLatePaymentAfterExpiryIsFlaggedForRefund(t *testing.T) {
+	db := openTestDB(t)
+
+	service := NewPaymentService()
+	gateway, ok := service.gateway.(*FakePaymentGateway)
+	if !ok {
+		t.Skip("late payment test needs the fake payment gateway")
+	}
+
+	user := models.User{Name: "Buyer", Email: "buyer@example.com", Password: "secret", Role: "customer"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	transaction := models.Transaction{
+		UserID:           user.ID,
+		Status:           models.TransactionStatusExpired,
+		TotalAmount:      10000,
+		ShippingAddress:  "Jl. Test 1",
+		PaymentMethod:    "e_wallet",
+		PaymentReference: "FAKE-LATE",
+		CreatedAt:        time.Now().Add(-2 * time.Hour),
+	}
+	if err := db.Create(&transaction).Error; err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+
+	payload := []byte(fmt.Sprintf(`{"event_id":"evt-late","reference":"FAKE-LATE","transaction_id":%d,"status":"paid","amount":10000}`, transaction.ID))
+	if err := service.HandleNotification(payload, gateway.Sign(payload)); !errors.Is(err, ErrLatePayment) {
+		t.Fatalf("err = %v, want ErrLatePayment", err)
+	}
+
+	var reloaded models.Transaction
+	db.First(&reloaded, transaction.ID)
+	if reloaded.Status != models.TransactionStatusExpired || reloaded.LatePaymentAt == nil {
+		t.Fatalf("status = %s, late payment = %v, want expired and flagged", reloaded.Status, reloaded.LatePaymentAt)
+	}
+
+	var notification models.PaymentNotification
+	db.Where("event_id = ?", "evt-late").First(&notification)
+	if !notification.Processed || notification.Error == "" {
+		t.Errorf("notification processed = %v, error = %q, want processed with the late payment recorded", notification.Processed, notification.Error)
+	}
+
+	// Dana yang terlanjur diterima dikembalikan lewat refund oleh admin
+	transactions := NewTransactionService()
+	transactions.paymentGateway = &refundGateway{PaymentGateway: transactions.paymentGateway}
+	if _, err := transactions.UpdateTransactionStatus(transaction.ID, 1, requests.UpdateTransactionStatusRequest{Status: models.TransactionStatusRefunded}); err != nil {
+		t.Fatalf("refund late payment: %v", err)
+	}
+	reloaded = models.Transaction{}
+	db.First(&reloaded, transaction.ID)
+	if reloaded.Status != models.TransactionStatusRefunded || reloaded.RefundStatus != models.RefundStatusSucceeded {
+		t.Errorf("status = %s, refund status = %s, want refunded and succeeded", reloaded.Status, reloaded.RefundStatus)
+	}
+}
+
+func TestGatewayPaymentConfirmsOrderAwaitingVerification(t *testing.T) {
+	db := openTestDB(t)
+
+	service := NewPaymentService()
+	gateway, ok := service.gateway.(*FakePaymentGateway)
+	if !ok {
+		t.Skip("awaiting verification test needs the fake payment gateway")
+	}
+
+	user := models.User{Name: "Buyer", Email: "buyer@example.com", Password: "secret", Role: "customer"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	transaction := models.Transaction{
+		UserID:           user.ID,
+		Status:           models.TransactionStatusAwaitingVerification,
+		TotalAmount:      10000,
+		ShippingAddress:  "Jl. Test 1",
+		PaymentMethod:    models.PaymentMethodBankTransfer,
+		PaymentReference: "FAKE-TRANSFER",
+	}
+	if err := db.Create(&transaction).Error; err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+
+	// Nominal yang tidak cocok ditolak dan event boleh dikirim ulang
+	payload := []byte(fmt.Sprintf(`{"event_id":"evt-short","reference":"FAKE-TRANSFER","transaction_id":%d,"status":"paid","amount":9000}`, transaction.ID))
+	if err := service.HandleNotification(payload, gateway.Sign(payload)); err == nil {
+		t.Fatal("notification with a short amount succeeded, want error")
+	}
+
+	payload = []byte(fmt.Sprintf(`{"event_id":"evt-transfer","reference":"FAKE-TRANSFER","transaction_id":%d,"status":"paid","amount":10000}`, transaction.ID))
+	if err := service.HandleNotification(payload, gateway.Sign(payload)); err != nil {
+		t.Fatalf("notification: %v", err)
+	}
+
+	var reloaded models.Transaction
+	db.First(&reloaded, transaction.ID)
+	if reloaded.Status != models.TransactionStatusPaid {
+		t.Errorf("status = %s, want %s", reloaded.Status, models.TransactionStatusPaid)
+	}
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"tokogo/config"
+	"tokogo/helpers"
+	"tokogo/models"
+	"tokogo/repositories"
+	"tokogo/requests"
+	"tokogo/responses"
+
+	"gorm.io/gorm"
+)
+
+type ProductImageService struct {
+	db          *gorm.DB
+	productRepo *repositories.ProductRepository
+	imageRepo   *repositories.ProductImageRepository
+}
+
+// NewProductImageService membuat instance baru ProductImageService
+func NewProductImageService() *ProductImageService {
+	return &ProductImageService{
+		db:          config.DB,
+		productRepo: repositories.NewProductRepository(config.DB),
+		imageRepo:   repositories.NewProductImageRepository(config.DB),
+	}
+}
+
+// GetImages mengambil galeri product
+func (s *ProductImageService) GetImages(productID uint) ([]responses.ProductImageResponse, error) {
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	images, err := s.imageRepo.GetByProductID(productID)
+	if err != nil {
+		return nil, errors.New("failed to get product images")
+	}
+
+	return responses.ConvertProductImagesToResponse(images), nil
+}
+
+// AddImages menambahkan gambar yang sudah diupload ke akhir galeri. Bila galeri
+// belum punya gambar primary, gambar pertama otomatis menjadi primary.
+// File yang sudah diupload dihapus lagi bila penyimpanan gagal.
+func (s *ProductImageService) AddImages(productID uint, uploads []helpers.ProcessedImage) ([]responses.ProductImageResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		productRepo := s.productRepo.WithTx(tx)
+		imageRepo := s.imageRepo.WithTx(tx)
+
+		// Kunci product agar posisi gambar dari upload bersamaan tidak bentrok
+		product, err := productRepo.GetByIDForUpdate(productID)
+		if err != nil {
+			return errors.New("product not found")
+		}
+
+		images, err := imageRepo.GetByProductID(product.ID)
+		if err != nil {
+			return errors.New("failed to get product images")
+		}
+
+		position := len(images)
+		hasPrimary := false
+		for _, image := range images {
+			position = max(position, image.Position+1)
+			hasPrimary = hasPrimary || image.IsPrimary
+		}
+
+		for _, upload := range uploads {
+			image := newProductImage(productID, upload)
+			image.Position = position
+			image.IsPrimary = !hasPrimary
+			if err := imageRepo.Create(&image); err != nil {
+				return errors.New("failed to save product image")
+			}
+
+			if image.IsPrimary {
+				if err := productRepo.UpdateImageURL(productID, image.ImageURL); err != nil {
+					return errors.New("failed to update primary image")
+				}
+				hasPrimary = true
+			}
+			position++
+		}
+
+		return nil
+	})
+	if err != nil {
+		for _, upload := range uploads {
+			helpers.DeleteFiles(upload.Paths()...)
+		}
+		return nil, err
+	}
+
+	return s.GetImages(productID)
+}
+
+// ReorderImages mengatur ulang urutan galeri. image_ids harus berisi semua gambar product.
+func (s *ProductImageService) ReorderImages(productID uint, req requests.ReorderProductImagesRequest) ([]responses.ProductImageResponse, error) {
+	// Validasi request
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		imageRepo := s.imageRepo.WithTx(tx)
+
+		if _, err := s.productRepo.WithTx(tx).GetByIDForUpdate(productID); err != nil {
+			return errors.New("product not found")
+		}
+
+		images, err := imageRepo.GetByProductID(productID)
+		if err != nil {
+			return errors.New("failed to get product images")
+		}
+
+		if len(req.ImageIDs) != len(images) {
+			return errors.New("image_ids must contain every image of the product")
+		}
+
+		owned := make(map[uint]bool, len(images))
+		for _, image := range images {
+			owned[image.ID] = true
+		}
+
+		for position, imageID := range req.ImageIDs {
+			if !owned[imageID] {
+				return fmt.Errorf("image %d does not belong to this product", imageID)
+			}
+			if err := imageRepo.UpdatePosition(imageID, position); err != nil {
+				return errors.New("failed to reorder product images")
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetImages(productID)
+}
+
+// SetPrimaryImage menjadikan satu gambar sebagai gambar utama product
+func (s *ProductImageService) SetPrimaryImage(productID, imageID uint) ([]responses.ProductImageResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		productRepo := s.productRepo.WithTx(tx)
+		imageRepo := s.imageRepo.WithTx(tx)
+
+		if _, err := productRepo.GetByIDForUpdate(productID); err != nil {
+			return errors.New("product not found")
+		}
+
+		image, err := imageRepo.GetByIDAndProductID(imageID, productID)
+		if err != nil {
+			return errors.New("product image not found")
+		}
+
+		if err := imageRepo.SetPrimary(productID, image.ID); err != nil {
+			return errors.New("failed to set primary image")
+		}
+
+		if err := productRepo.UpdateImageURL(productID, image.ImageURL); err != nil {
+			return errors.New("failed to update primary image")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetImages(productID)
+}
+
+// DeleteImage menghapus gambar dari galeri beserta file-nya. Bila gambar primary
+// yang dihapus, gambar pertama yang tersisa menjadi primary.
+func (s *ProductImageService) DeleteImage(productID, imageID uint) error {
+	var deletedPaths []string
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		productRepo := s.productRepo.WithTx(tx)
+		imageRepo := s.imageRepo.WithTx(tx)
+
+		if _, err := productRepo.GetByIDForUpdate(productID); err != nil {
+			return errors.New("product not found")
+		}
+
+		image, err := imageRepo.GetByIDAndProductID(imageID, productID)
+		if err != nil {
+			return errors.New("product image not found")
+		}
+
+		if err := imageRepo.Delete(image.ID); err != nil {
+			return errors.New("failed to delete product image")
+		}
+		deletedPaths = image.Paths()
+
+		if !image.IsPrimary {
+			return nil
+		}
+
+		// Promosikan gambar berikutnya menjadi primary
+		remaining, err := imageRepo.GetByProductID(productID)
+		if err != nil {
+			return errors.New("failed to get product images")
+		}
+
+		primaryURL := ""
+		if len(remaining) > 0 {
+			if err := imageRepo.SetPrimary(productID, remaining[0].ID); err != nil {
+				return errors.New("failed to set primary image")
+			}
+			primaryURL = remaining[0].ImageURL
+		}
+
+		if err := productRepo.UpdateImageURL(productID, primaryURL); err != nil {
+			return errors.New("failed to update primary image")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// File hanya dihapus setelah perubahan database berhasil di-commit
+	if err := helpers.DeleteFiles(deletedPaths...); err != nil {
+		fmt.Printf("Warning: Failed to delete product image files: %v\n", err)
+	}
+
+	return nil
+}
+
+// BackfillLegacyImages memindahkan ImageURL product lama yang dibuat sebelum galeri diperkenalkan
+// ke galeri sebagai gambar primary, sehingga semua operasi galeri melihat gambar yang sama
+func (s *ProductImageService) BackfillLegacyImages() error {
+	for {
+		products, err := s.productRepo.GetWithoutGallery(100)
+		if err != nil || len(products) == 0 {
+			return err
+		}
+
+		for _, product := range products {
+			legacy := models.ProductImage{
+				ProductID: product.ID,
+				ImageURL:  product.ImageURL,
+				IsPrimary: true,
+			}
+			if err := s.imageRepo.Create(&legacy); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// newProductImage membuat ProductImage dari hasil upload beserta rendition-nya
+func newProductImage(productID uint, upload helpers.ProcessedImage) models.ProductImage {
+	return models.ProductImage{
+		ProductID:    productID,
+		ImageURL:     upload.Original,
+		ThumbnailURL: upload.Renditions["thumbnail"],
+		MediumURL:    upload.Renditions["medium"],
+		LargeURL:     upload.Renditions["large"],
+	}
+}
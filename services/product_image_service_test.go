@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+
+	"tokogo/models"
+	"tokogo/requests"
+)
+
+func TestLegacyImageIsBackfilledIntoGallery(t *testing.T) {
+	db := openTestDB(t)
+
+	category := models.Category{Name: "Test", Slug: "test"}
+	if err := db.Create(&category).Error; err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	// Product lama hanya punya ImageURL tanpa galeri
+	product := models.Product{Name: "Kemeja", Slug: "kemeja", PurchasePrice: 50000, SellingPrice: 80000, CategoryID: category.ID, Status: models.ProductStatusPublished, ImageURL: "products/kemeja.jpg"}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	service := NewProductImageService()
+	if err := service.BackfillLegacyImages(); err != nil {
+		t.Fatalf("backfill: %v", err)
+	}
+	// Backfill yang dijalankan ulang tidak menduplikasi gambar
+	if err := service.BackfillLegacyImages(); err != nil {
+		t.Fatalf("second backfill: %v", err)
+	}
+
+	images, err := service.GetImages(product.ID)
+	if err != nil {
+		t.Fatalf("get images: %v", err)
+	}
+	if len(images) != 1 || !images[0].IsPrimary {
+		t.Fatalf("gallery = %+v, want one primary image", images)
+	}
+
+	// Reorder pertama memakai ID gambar yang sama dengan yang dikembalikan GetImages
+	reordered, err := service.ReorderImages(product.ID, requests.ReorderProductImagesRequest{ImageIDs: []uint{images[0].ID}})
+	if err != nil {
+		t.Fatalf("reorder: %v", err)
+	}
+	if len(reordered) != 1 || reordered[0].ID != images[0].ID {
+		t.Errorf("reordered gallery = %+v, want the backfilled image", reordered)
+	}
+}
@@ -2,29 +2,46 @@ package services
 
 import (
 	"errors"
+	"strconv"
+	"strings"
+	"time"
 	"tokogo/config"
 	"tokogo/helpers"
 	"tokogo/models"
 	"tokogo/repositories"
 	"tokogo/requests"
 	"tokogo/responses"
+
+	"gorm.io/gorm"
 )
 
 type ProductService struct {
+	db           *gorm.DB
 	productRepo  *repositories.ProductRepository
+	imageRepo    *repositories.ProductImageRepository
 	categoryRepo *repositories.CategoryRepository
+	slugRepo     *repositories.SlugRepository
+	cartRepo     *repositories.CartRepository
+	priceRepo    *repositories.PriceHistoryRepository
+	inventory    *InventoryService
 }
 
 // NewProductService membuat instance baru ProductService
 func NewProductService() *ProductService {
 	return &ProductService{
+		db:           config.DB,
 		productRepo:  repositories.NewProductRepository(config.DB),
+		imageRepo:    repositories.NewProductImageRepository(config.DB),
 		categoryRepo: repositories.NewCategoryRepository(),
+		slugRepo:     repositories.NewSlugRepository(config.DB),
+		cartRepo:     repositories.NewCartRepository(config.DB),
+		priceRepo:    repositories.NewPriceHistoryRepository(config.DB),
+		inventory:    NewInventoryService(),
 	}
 }
 
 // CreateProduct membuat product baru
-func (s *ProductService) CreateProduct(req requests.CreateProductRequest, imagePath string) (*responses.ProductResponse, error) {
+func (s *ProductService) CreateProduct(req requests.CreateProductRequest, upload *helpers.ProcessedImage, actorID uint) (*responses.ProductResponse, error) {
 	// Cek apakah category ada
 	_, err := s.categoryRepo.GetCategoryByID(req.CategoryID)
 	if err != nil {
@@ -33,30 +50,129 @@ func (s *ProductService) CreateProduct(req requests.CreateProductRequest, imageP
 
 	// Buat product baru
 	product := &models.Product{
-		Name:          req.Name,
-		Description:   req.Description,
-		PurchasePrice: req.PurchasePrice,
-		SellingPrice:  req.SellingPrice,
-		Stock:         req.Stock,
-		CategoryID:    req.CategoryID,
-		ImageURL:      imagePath,
+		Name:              req.Name,
+		Description:       req.Description,
+		PurchasePrice:     req.PurchasePrice,
+		SellingPrice:      req.SellingPrice,
+		LowStockThreshold: req.LowStockThreshold,
+		CategoryID:        req.CategoryID,
+		Status:            req.Status,
+		PublishAt:         req.PublishAt,
+		UnpublishAt:       req.UnpublishAt,
+		CompareAtPrice:    req.CompareAtPrice,
+		SalePrice:         req.SalePrice,
+		SaleStartsAt:      req.SaleStartsAt,
+		SaleEndsAt:        req.SaleEndsAt,
+	}
+	if product.Status == "" {
+		product.Status = models.ProductStatusPublished
 	}
+	if upload != nil {
+		product.ImageURL = upload.Original
+	}
+
+	// Simpan ke database, stok awal dicatat sebagai restock di ledger
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Category dikunci agar tidak terhapus bersamaan dengan pembuatan product
+		if _, err := s.categoryRepo.WithTx(tx).GetCategoryByIDForShare(product.CategoryID); err != nil {
+			return errors.New("category not found")
+		}
+
+		slugRepo := s.slugRepo.WithTx(tx)
+		slugs := productSlugChange(0, "")
+		slug, err := slugs.resolve(slugRepo, product.Name)
+		if err != nil {
+			return err
+		}
+		product.Slug = slug
+
+		if err := s.productRepo.WithTx(tx).Create(product); err != nil {
+			return err
+		}
+		if err := slugs.record(slugRepo, product.ID, product.Slug); err != nil {
+			return err
+		}
+
+		// Harga awal menjadi catatan pertama di riwayat harga
+		history := models.NewPriceHistory(*product, actorID)
+		if err := s.priceRepo.WithTx(tx).Create(&history); err != nil {
+			return err
+		}
+
+		// Gambar dari form create menjadi gambar primary pertama di galeri
+		if upload != nil {
+			image := newProductImage(product.ID, *upload)
+			image.IsPrimary = true
+			if err := s.imageRepo.WithTx(tx).Create(&image); err != nil {
+				return err
+			}
+			product.Images = []models.ProductImage{image}
+		}
 
-	// Simpan ke database
-	if err := s.productRepo.Create(product); err != nil {
+		return s.inventory.WithTx(tx).AdjustStock(StockAdjustment{
+			ProductID: product.ID,
+			Delta:     req.Stock,
+			Reason:    models.StockMovementRestock,
+			ActorID:   actorID,
+			Note:      "Initial stock",
+		})
+	})
+	if err != nil {
 		// If database save fails, delete uploaded file
-		if imagePath != "" {
-			helpers.DeleteFile(imagePath)
+		if upload != nil {
+			helpers.DeleteFiles(upload.Paths()...)
 		}
 		return nil, errors.New("failed to create product")
 	}
 
 	// Return response
+	product.Stock = req.Stock
 	response := responses.ConvertProductToResponse(*product)
 	return &response, nil
 }
 
-func (s *ProductService) GetAllProducts(page, limit int) (*responses.ProductListResponse, error) {
+// GetAllProducts mengambil product untuk admin, status kosong berarti semua status
+func (s *ProductService) GetAllProducts(page, limit int, status string) (*responses.ProductListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	products, total, err := s.productRepo.GetAll(page, limit, repositories.ProductFilter{Status: status})
+	if err != nil {
+		return nil, err
+	}
+
+	productResponses := responses.ConvertProductsToResponse(products)
+
+	return &responses.ProductListResponse{
+		Products: productResponses,
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	}, nil
+}
+
+// GetAllProductsByCursor mengambil product dengan pagination berbasis cursor
+func (s *ProductService) GetAllProductsByCursor(cursor string, limit int, status string) (*responses.ProductListResponse, error) {
+	limit = normalizeCursorLimit(limit)
+
+	products, next, err := s.productRepo.GetAllByCursor(cursor, limit, repositories.ProductFilter{Status: status})
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.ProductListResponse{
+		Products:   responses.ConvertProductsToResponse(products),
+		Limit:      limit,
+		NextCursor: next,
+	}, nil
+}
+
+// GetLowStockProducts mengambil product yang stoknya sudah mencapai low stock threshold
+func (s *ProductService) GetLowStockProducts(page, limit int) (*responses.ProductListResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -64,7 +180,7 @@ func (s *ProductService) GetAllProducts(page, limit int) (*responses.ProductList
 		limit = 10
 	}
 
-	products, total, err := s.productRepo.GetAll(page, limit)
+	products, total, err := s.productRepo.GetLowStock(page, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +205,7 @@ func (s *ProductService) GetProductByID(id uint) (*responses.ProductResponse, er
 	return &response, nil
 }
 
-func (s *ProductService) UpdateProduct(id uint, req requests.UpdateProductRequest) (*responses.ProductResponse, error) {
+func (s *ProductService) UpdateProduct(id uint, req requests.UpdateProductRequest, actorID uint) (*responses.ProductResponse, error) {
 	// Get existing product
 	product, err := s.productRepo.GetByID(id)
 	if err != nil {
@@ -102,16 +218,96 @@ func (s *ProductService) UpdateProduct(id uint, req requests.UpdateProductReques
 		return nil, errors.New("category not found")
 	}
 
+	// Simpan harga lama untuk dibandingkan setelah update
+	previousPrices := models.NewPriceHistory(*product, actorID)
+
 	// Update product fields
 	product.Name = req.Name
 	product.Description = req.Description
 	product.PurchasePrice = req.PurchasePrice
 	product.SellingPrice = req.SellingPrice
-	product.Stock = req.Stock
+	// Batas low stock dipertahankan jika tidak dikirim
+	if req.LowStockThreshold != nil {
+		product.LowStockThreshold = *req.LowStockThreshold
+	}
 	product.CategoryID = req.CategoryID
+	// Jadwal publish dipertahankan jika tidak dikirim, kecuali diminta untuk dikosongkan
+	if req.ClearPublishSchedule {
+		product.PublishAt = nil
+		product.UnpublishAt = nil
+	}
+	if req.PublishAt != nil {
+		product.PublishAt = req.PublishAt
+	}
+	if req.UnpublishAt != nil {
+		product.UnpublishAt = req.UnpublishAt
+	}
+	if err := requests.ValidatePublishWindow(product.PublishAt, product.UnpublishAt); err != nil {
+		return nil, err
+	}
+	// Harga coret dan sale dipertahankan jika tidak dikirim, kecuali diminta untuk dikosongkan
+	if req.ClearSale {
+		product.CompareAtPrice = nil
+		product.SalePrice = nil
+		product.SaleStartsAt = nil
+		product.SaleEndsAt = nil
+	}
+	if req.CompareAtPrice != nil {
+		product.CompareAtPrice = req.CompareAtPrice
+	}
+	if req.SalePrice != nil {
+		product.SalePrice = req.SalePrice
+	}
+	if req.SaleStartsAt != nil {
+		product.SaleStartsAt = req.SaleStartsAt
+	}
+	if req.SaleEndsAt != nil {
+		product.SaleEndsAt = req.SaleEndsAt
+	}
+	if err := requests.ValidateSalePrice(product.SellingPrice, product.CompareAtPrice, product.SalePrice, product.SaleStartsAt, product.SaleEndsAt); err != nil {
+		return nil, err
+	}
+	// Status dipertahankan jika tidak dikirim
+	if req.Status != "" {
+		product.Status = req.Status
+	}
 	// ImageURL is not updated via request - handled separately
 
-	if err := s.productRepo.Update(product); err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		productRepo := s.productRepo.WithTx(tx)
+		slugRepo := s.slugRepo.WithTx(tx)
+
+		// Category dikunci agar tidak terhapus bersamaan dengan pemindahan product
+		if _, err := s.categoryRepo.WithTx(tx).GetCategoryByIDForShare(product.CategoryID); err != nil {
+			return errors.New("category not found")
+		}
+
+		// Slug baru dibuat jika nama berubah, slug lama tetap diarahkan ke product ini
+		slugs := productSlugChange(id, product.Slug)
+		slug, err := slugs.resolve(slugRepo, product.Name)
+		if err != nil {
+			return err
+		}
+		product.Slug = slug
+
+		if err := productRepo.Update(product); err != nil {
+			return err
+		}
+		if err := slugs.record(slugRepo, id, product.Slug); err != nil {
+			return err
+		}
+
+		// Riwayat harga hanya bertambah jika ada harga yang berubah
+		if !previousPrices.SamePrices(*product) {
+			history := models.NewPriceHistory(*product, actorID)
+			if err := s.priceRepo.WithTx(tx).Create(&history); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -119,6 +315,8 @@ func (s *ProductService) UpdateProduct(id uint, req requests.UpdateProductReques
 	return &response, nil
 }
 
+// DeleteProduct mengarsipkan product. Product hilang dari listing public dan tidak bisa
+// dimasukkan ke cart, item cart yang mereferensikannya ikut dihapus.
 func (s *ProductService) DeleteProduct(id uint) error {
 	// Check if product exists
 	_, err := s.productRepo.GetByID(id)
@@ -126,10 +324,20 @@ func (s *ProductService) DeleteProduct(id uint) error {
 		return err
 	}
 
-	return s.productRepo.Delete(id)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.cartRepo.WithTx(tx).DeleteByProductID(id); err != nil {
+			return errors.New("failed to remove product from carts")
+		}
+
+		if err := s.productRepo.WithTx(tx).Delete(id); err != nil {
+			return errors.New("failed to archive product")
+		}
+		return nil
+	})
 }
 
-func (s *ProductService) GetProductsByCategory(categoryID uint, page, limit int) (*responses.ProductListResponse, error) {
+// GetArchivedProducts mengambil product yang sudah diarsipkan
+func (s *ProductService) GetArchivedProducts(page, limit int) (*responses.ProductListResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -137,7 +345,80 @@ func (s *ProductService) GetProductsByCategory(categoryID uint, page, limit int)
 		limit = 10
 	}
 
-	products, total, err := s.productRepo.GetByCategoryID(categoryID, page, limit)
+	products, total, err := s.productRepo.GetArchived(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses.ProductListResponse{
+		Products: responses.ConvertProductsToResponse(products),
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	}, nil
+}
+
+// RestoreProduct mengembalikan product yang sudah diarsipkan
+func (s *ProductService) RestoreProduct(id uint) (*responses.ProductResponse, error) {
+	product, err := s.productRepo.GetArchivedByID(id)
+	if err != nil {
+		return nil, errors.New("archived product not found")
+	}
+
+	// Category bisa saja ikut dihapus selama product diarsipkan
+	if _, err := s.categoryRepo.GetCategoryByID(product.CategoryID); err != nil {
+		return nil, errors.New("product category no longer exists, restore the category first")
+	}
+
+	if err := s.productRepo.Restore(id); err != nil {
+		return nil, errors.New("failed to restore product")
+	}
+
+	return s.GetProductByID(id)
+}
+
+// GetPriceHistory mengambil riwayat perubahan harga product, termasuk product yang diarsipkan
+func (s *ProductService) GetPriceHistory(productID uint, page, limit int) (*responses.PriceHistoryListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		if _, err := s.productRepo.GetArchivedByID(productID); err != nil {
+			return nil, errors.New("product not found")
+		}
+	}
+
+	histories, total, err := s.priceRepo.GetByProductID(productID, page, limit)
+	if err != nil {
+		return nil, errors.New("failed to get price history")
+	}
+
+	return &responses.PriceHistoryListResponse{
+		Histories: responses.ConvertPriceHistoriesToResponse(histories),
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	}, nil
+}
+
+func (s *ProductService) GetProductsByCategory(categoryID uint, page, limit int, includeDescendants bool) (*responses.ProductListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	categoryIDs, err := s.categoryScope([]uint{categoryID}, includeDescendants)
+	if err != nil {
+		return nil, err
+	}
+
+	products, total, err := s.productRepo.GetAll(page, limit, repositories.ProductFilter{CategoryIDs: categoryIDs})
 	if err != nil {
 		return nil, err
 	}
@@ -153,7 +434,7 @@ func (s *ProductService) GetProductsByCategory(categoryID uint, page, limit int)
 }
 
 // Public methods (tanpa purchase_price)
-func (s *ProductService) GetAllProductsPublic(page, limit int) (*responses.PublicProductListResponse, error) {
+func (s *ProductService) GetAllProductsPublic(page, limit int, query string, listQuery requests.ProductListQuery) (*responses.PublicProductListResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -161,32 +442,166 @@ func (s *ProductService) GetAllProductsPublic(page, limit int) (*responses.Publi
 		limit = 10
 	}
 
-	products, total, err := s.productRepo.GetAll(page, limit)
+	filter, err := s.buildProductFilter(listQuery)
 	if err != nil {
 		return nil, err
 	}
 
+	// Dengan query q, product diurutkan berdasarkan relevansi pencarian
+	if query = strings.TrimSpace(query); query != "" {
+		return s.searchProductsPublic(query, page, limit, filter)
+	}
+
+	return s.listProductsPublic(page, limit, filter)
+}
+
+// searchProductsPublic mencari product dan menyertakan skor serta highlight pada setiap hasil
+func (s *ProductService) searchProductsPublic(query string, page, limit int, filter repositories.ProductFilter) (*responses.PublicProductListResponse, error) {
+	hits, total, err := s.productRepo.Search(query, page, limit, filter)
+	if err != nil {
+		return nil, errors.New("failed to search products")
+	}
+
+	var productResponses []responses.PublicProductResponse
+	for _, hit := range hits {
+		response := responses.ConvertProductToPublicResponse(hit.Product)
+		response.Score = hit.Score
+		response.Highlights = hit.Highlights
+		productResponses = append(productResponses, response)
+	}
+
+	return &responses.PublicProductListResponse{
+		Products: productResponses,
+		Query:    query,
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	}, nil
+}
+
+// listProductsPublic mengambil product sesuai filter beserta facet untuk sidebar filter
+func (s *ProductService) listProductsPublic(page, limit int, filter repositories.ProductFilter) (*responses.PublicProductListResponse, error) {
+	products, total, err := s.productRepo.GetAll(page, limit, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	facets, err := s.productRepo.GetFacets(filter)
+	if err != nil {
+		return nil, errors.New("failed to load product facets")
+	}
+
 	productResponses := responses.ConvertProductsToPublicResponse(products)
 
 	return &responses.PublicProductListResponse{
 		Products: productResponses,
+		Facets:   convertProductFacets(facets),
 		Total:    total,
 		Page:     page,
 		Limit:    limit,
 	}, nil
 }
 
+// GetAllProductsPublicByCursor mengambil product public dengan filter dan pagination berbasis cursor.
+// Pencarian q tetap memakai page/limit karena urutannya berdasarkan skor relevansi.
+func (s *ProductService) GetAllProductsPublicByCursor(cursor string, limit int, listQuery requests.ProductListQuery) (*responses.PublicProductListResponse, error) {
+	limit = normalizeCursorLimit(limit)
+
+	filter, err := s.buildProductFilter(listQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	products, next, err := s.productRepo.GetAllByCursor(cursor, limit, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	facets, err := s.productRepo.GetFacets(filter)
+	if err != nil {
+		return nil, errors.New("failed to load product facets")
+	}
+
+	return &responses.PublicProductListResponse{
+		Products:   responses.ConvertProductsToPublicResponse(products),
+		Facets:     convertProductFacets(facets),
+		Limit:      limit,
+		NextCursor: next,
+	}, nil
+}
+
 func (s *ProductService) GetProductByIDPublic(id uint) (*responses.PublicProductResponse, error) {
 	product, err := s.productRepo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
+	// Draft, hidden, dan product di luar jadwal tayang tidak bisa diakses public
+	if !product.IsVisible(time.Now()) {
+		return nil, errors.New("product not found")
+	}
 
 	response := responses.ConvertProductToPublicResponse(*product)
 	return &response, nil
 }
 
-func (s *ProductService) GetProductsByCategoryPublic(categoryID uint, page, limit int) (*responses.PublicProductListResponse, error) {
+// GetProductBySlugPublic mengambil product berdasarkan slug. Jika slug adalah slug lama
+// dari product yang sudah di-rename, slug terbaru dikembalikan sebagai redirectSlug.
+func (s *ProductService) GetProductBySlugPublic(slug string) (response *responses.PublicProductResponse, redirectSlug string, err error) {
+	product, err := s.productRepo.GetBySlug(slug)
+	if err == nil {
+		if !product.IsVisible(time.Now()) {
+			return nil, "", errors.New("product not found")
+		}
+		converted := responses.ConvertProductToPublicResponse(*product)
+		return &converted, "", nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", err
+	}
+
+	redirect, err := s.slugRepo.FindRedirect(models.SlugEntityProduct, slug)
+	if err != nil {
+		return nil, "", errors.New("product not found")
+	}
+	product, err = s.productRepo.GetByID(redirect.EntityID)
+	if err != nil || !product.IsVisible(time.Now()) {
+		return nil, "", errors.New("product not found")
+	}
+	return nil, product.Slug, nil
+}
+
+// BackfillSlugs mengisi slug untuk product lama yang dibuat sebelum slug diperkenalkan
+func (s *ProductService) BackfillSlugs() error {
+	for {
+		products, err := s.productRepo.GetWithoutSlug(100)
+		if err != nil || len(products) == 0 {
+			return err
+		}
+
+		for _, product := range products {
+			slug, err := productSlugChange(product.ID, "").resolve(s.slugRepo, product.Name)
+			if err != nil {
+				return err
+			}
+			if err := s.productRepo.UpdateSlug(product.ID, slug); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// productSlugChange menyiapkan perubahan slug untuk product
+func productSlugChange(id uint, current string) slugChange {
+	return slugChange{
+		entityType: models.SlugEntityProduct,
+		model:      &models.Product{},
+		entityID:   id,
+		current:    current,
+		fallback:   "product",
+	}
+}
+
+func (s *ProductService) GetProductsByCategoryPublic(categoryID uint, page, limit int, listQuery requests.ProductListQuery) (*responses.PublicProductListResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -194,17 +609,79 @@ func (s *ProductService) GetProductsByCategoryPublic(categoryID uint, page, limi
 		limit = 10
 	}
 
-	products, total, err := s.productRepo.GetByCategoryID(categoryID, page, limit)
+	listQuery.CategoryIDs = strconv.FormatUint(uint64(categoryID), 10)
+	filter, err := s.buildProductFilter(listQuery)
 	if err != nil {
 		return nil, err
 	}
 
-	productResponses := responses.ConvertProductsToPublicResponse(products)
+	return s.listProductsPublic(page, limit, filter)
+}
 
-	return &responses.PublicProductListResponse{
-		Products: productResponses,
-		Total:    total,
-		Page:     page,
-		Limit:    limit,
+// buildProductFilter mengubah query parameter listing menjadi filter repository
+func (s *ProductService) buildProductFilter(listQuery requests.ProductListQuery) (repositories.ProductFilter, error) {
+	categoryIDs, err := listQuery.CategoryIDList()
+	if err != nil {
+		return repositories.ProductFilter{}, err
+	}
+	if categoryIDs, err = s.categoryScope(categoryIDs, listQuery.IncludeDescendants); err != nil {
+		return repositories.ProductFilter{}, err
+	}
+
+	// Listing public hanya menampilkan product yang sedang tayang
+	now := time.Now()
+	return repositories.ProductFilter{
+		MinPrice:    listQuery.MinPrice,
+		MaxPrice:    listQuery.MaxPrice,
+		InStock:     listQuery.InStock,
+		CategoryIDs: categoryIDs,
+		Sort:        listQuery.Sort,
+		VisibleAt:   &now,
 	}, nil
 }
+
+// categoryScope mengembalikan id category yang dicari, ditambah seluruh sub category jika diminta
+func (s *ProductService) categoryScope(categoryIDs []uint, includeDescendants bool) ([]uint, error) {
+	if !includeDescendants || len(categoryIDs) == 0 {
+		return categoryIDs, nil
+	}
+
+	seen := make(map[uint]bool)
+	var scope []uint
+	for _, categoryID := range categoryIDs {
+		ids, err := s.categoryRepo.GetDescendantIDs(categoryID)
+		if err != nil {
+			return nil, errors.New("failed to load sub categories")
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				scope = append(scope, id)
+			}
+		}
+	}
+	return scope, nil
+}
+
+// convertProductFacets mengkonversi facet dari repository ke response
+func convertProductFacets(facets *repositories.ProductFacets) *responses.ProductFacetsResponse {
+	response := &responses.ProductFacetsResponse{
+		Categories:  []responses.CategoryFacetResponse{},
+		PriceRanges: []responses.PriceFacetResponse{},
+	}
+	for _, category := range facets.Categories {
+		response.Categories = append(response.Categories, responses.CategoryFacetResponse{
+			CategoryID:   category.CategoryID,
+			CategoryName: category.CategoryName,
+			Count:        category.Count,
+		})
+	}
+	for _, price := range facets.PriceRanges {
+		response.PriceRanges = append(response.PriceRanges, responses.PriceFacetResponse{
+			Min:   price.Min,
+			Max:   price.Max,
+			Count: price.Count,
+		})
+	}
+	return response
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"tokogo/models"
+	"tokogo/requests"
+)
+
+func TestUpdateProductKeepsOmittedFields(t *testing.T) {
+	db := openTestDB(t)
+
+	category := models.Category{Name: "Test", Slug: "test"}
+	if err := db.Create(&category).Error; err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	product := models.Product{Name: "Kemeja", Slug: "kemeja", PurchasePrice: 50000, SellingPrice: 80000, Stock: 7, LowStockThreshold: 5, CategoryID: category.ID, Status: models.ProductStatusPublished}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	updated, err := NewProductService().UpdateProduct(product.ID, requests.UpdateProductRequest{
+		Name:          "Kemeja Flanel",
+		PurchasePrice: 50000,
+		SellingPrice:  90000,
+		CategoryID:    category.ID,
+	}, 1)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	// low_stock_threshold yang tidak dikirim dan stok tidak ikut berubah
+	if updated.LowStockThreshold != 5 || updated.Stock != 7 {
+		t.Errorf("threshold = %d, stock = %d, want 5 and 7", updated.LowStockThreshold, updated.Stock)
+	}
+	var reloaded models.Product
+	db.First(&reloaded, product.ID)
+	if reloaded.LowStockThreshold != 5 || reloaded.Stock != 7 {
+		t.Errorf("stored threshold = %d, stock = %d, want 5 and 7", reloaded.LowStockThreshold, reloaded.Stock)
+	}
+}
+
+func TestUpdateProductKeepsPublishScheduleUnlessCleared(t *testing.T) {
+	db := openTestDB(t)
+
+	category := models.Category{Name: "Test", Slug: "test"}
+	if err := db.Create(&category).Error; err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	publishAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	unpublishAt := publishAt.Add(7 * 24 * time.Hour)
+	product := models.Product{Name: "Kemeja", Slug: "kemeja", PurchasePrice: 50000, SellingPrice: 80000, CategoryID: category.ID, Status: models.ProductStatusPublished, PublishAt: &publishAt, UnpublishAt: &unpublishAt}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	service := NewProductService()
+	req := requests.UpdateProductRequest{
+		Name:          "Kemeja Flanel",
+		PurchasePrice: 50000,
+		SellingPrice:  90000,
+		CategoryID:    category.ID,
+	}
+	if _, err := service.UpdateProduct(product.ID, req, 1); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	// Jadwal publish yang tidak dikirim tetap tersimpan
+	var reloaded models.Product
+	db.First(&reloaded, product.ID)
+	if reloaded.PublishAt == nil || !reloaded.PublishAt.Equal(publishAt) || reloaded.UnpublishAt == nil || !reloaded.UnpublishAt.Equal(unpublishAt) {
+		t.Fatalf("publish_at = %v, unpublish_at = %v, want %v and %v", reloaded.PublishAt, reloaded.UnpublishAt, publishAt, unpublishAt)
+	}
+
+	// unpublish_at baru divalidasi terhadap publish_at yang tersimpan
+	earlier := publishAt.Add(-time.Hour)
+	req.UnpublishAt = &earlier
+	if _, err := service.UpdateProduct(product.ID, req, 1); err == nil {
+		t.Error("update with unpublish_at before stored publish_at succeeded, want error")
+	}
+
+	req.UnpublishAt = nil
+	req.ClearPublishSchedule = true
+	if _, err := service.UpdateProduct(product.ID, req, 1); err != nil {
+		t.Fatalf("clear schedule: %v", err)
+	}
+	reloaded = models.Product{}
+	db.First(&reloaded, product.ID)
+	if reloaded.PublishAt != nil || reloaded.UnpublishAt != nil {
+		t.Errorf("publish_at = %v, unpublish_at = %v, want both cleared", reloaded.PublishAt, reloaded.UnpublishAt)
+	}
+}
+
+func TestUpdateProductKeepsRunningSale(t *testing.T) {
+	db := openTestDB(t)
+
+	category := models.Category{Name: "Test", Slug: "test"}
+	if err := db.Create(&category).Error; err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	compareAt, salePrice := 100000.0, 70000.0
+	saleEndsAt := time.Now().Add(72 * time.Hour).UTC().Truncate(time.Second)
+	product := models.Product{Name: "Kemeja", Slug: "kemeja", PurchasePrice: 50000, SellingPrice: 80000, CategoryID: category.ID, Status: models.ProductStatusPublished, CompareAtPrice: &compareAt, SalePrice: &salePrice, SaleEndsAt: &saleEndsAt}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+
+	service := NewProductService()
+	req := requests.UpdateProductRequest{
+		Name:          "Kemeja Flanel",
+		PurchasePrice: 50000,
+		SellingPrice:  80000,
+		CategoryID:    category.ID,
+	}
+	updated, err := service.UpdateProduct(product.ID, req, 1)
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	// Sale yang sedang berjalan tidak berakhir dan riwayat harga tidak bertambah
+	if updated.SalePrice == nil || *updated.SalePrice != salePrice || updated.CompareAtPrice == nil || *updated.CompareAtPrice != compareAt {
+		t.Errorf("sale_price = %v, compare_at_price = %v, want %v and %v", updated.SalePrice, updated.CompareAtPrice, salePrice, compareAt)
+	}
+	var reloaded models.Product
+	db.First(&reloaded, product.ID)
+	if reloaded.SalePrice == nil || reloaded.SaleEndsAt == nil || !reloaded.SaleEndsAt.Equal(saleEndsAt) {
+		t.Errorf("stored sale_price = %v, sale_ends_at = %v, want sale kept", reloaded.SalePrice, reloaded.SaleEndsAt)
+	}
+	var histories int64
+	db.Model(&models.PriceHistory{}).Where("product_id = ?", product.ID).Count(&histories)
+	if histories != 0 {
+		t.Errorf("price history rows = %d, want 0", histories)
+	}
+
+	// Harga jual baru divalidasi terhadap harga sale yang tersimpan
+	req.SellingPrice = 65000
+	if _, err := service.UpdateProduct(product.ID, req, 1); err == nil {
+		t.Error("update with selling price below stored sale price succeeded, want error")
+	}
+
+	// Periode sale bisa diperpanjang tanpa mengirim ulang sale_price
+	req.SellingPrice = 80000
+	extendedEndsAt := saleEndsAt.Add(24 * time.Hour)
+	req.SaleEndsAt = &extendedEndsAt
+	if _, err := service.UpdateProduct(product.ID, req, 1); err != nil {
+		t.Fatalf("extend sale: %v", err)
+	}
+	reloaded = models.Product{}
+	db.First(&reloaded, product.ID)
+	if reloaded.SalePrice == nil || *reloaded.SalePrice != salePrice || reloaded.SaleEndsAt == nil || !reloaded.SaleEndsAt.Equal(extendedEndsAt) {
+		t.Errorf("sale_price = %v, sale_ends_at = %v, want %v until %v", reloaded.SalePrice, reloaded.SaleEndsAt, salePrice, extendedEndsAt)
+	}
+
+	req.SaleEndsAt = nil
+	req.ClearSale = true
+	if _, err := service.UpdateProduct(product.ID, req, 1); err != nil {
+		t.Fatalf("clear sale: %v", err)
+	}
+	reloaded = models.Product{}
+	db.First(&reloaded, product.ID)
+	if reloaded.SalePrice != nil || reloaded.CompareAtPrice != nil || reloaded.SaleEndsAt != nil {
+		t.Errorf("sale_price = %v, compare_at_price = %v, sale_ends_at = %v, want all cleared", reloaded.SalePrice, reloaded.CompareAtPrice, reloaded.SaleEndsAt)
+	}
+	db.Model(&models.PriceHistory{}).Where("product_id = ?", product.ID).Count(&histories)
+	if histories != 2 {
+		t.Errorf("price history rows = %d, want 2", histories)
+	}
+}
+
+func TestGetLowStockProductsUsesVariantStock(t *testing.T) {
+	db := openTestDB(t)
+
+	category := models.Category{Name: "Test", Slug: "test"}
+	if err := db.Create(&category).Error; err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	// Stok product dengan variant selalu 0, stok sebenarnya ada di variant
+	stocked := models.Product{Name: "Kaos", Slug: "kaos", PurchasePrice: 50000, SellingPrice: 80000, LowStockThreshold: 5, CategoryID: category.ID, Status: models.ProductStatusPublished}
+	running := models.Product{Name: "Kemeja", Slug: "kemeja", PurchasePrice: 50000, SellingPrice: 80000, LowStockThreshold: 5, CategoryID: category.ID, Status: models.ProductStatusPublished}
+	plain := models.Product{Name: "Topi", Slug: "topi", PurchasePrice: 20000, SellingPrice: 30000, Stock: 2, LowStockThreshold: 5, CategoryID: category.ID, Status: models.ProductStatusPublished}
+	for _, product := range []*models.Product{&stocked, &running, &plain} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("create product: %v", err)
+		}
+	}
+	variants := []models.ProductVariant{
+		{ProductID: stocked.ID, SKU: "KAOS-M", Options: map[string]string{"size": "M"}, Stock: 20},
+		{ProductID: stocked.ID, SKU: "KAOS-L", Options: map[string]string{"size": "L"}, Stock: 15},
+		{ProductID: running.ID, SKU: "KEMEJA-M", Options: map[string]string{"size": "M"}, Stock: 20},
+		{ProductID: running.ID, SKU: "KEMEJA-L", Options: map[string]string{"size": "L"}, Stock: 1},
+	}
+	if err := db.Create(&variants).Error; err != nil {
+		t.Fatalf("create variants: %v", err)
+	}
+
+	list, err := NewProductService().GetLowStockProducts(1, 10)
+	if err != nil {
+		t.Fatalf("low stock: %v", err)
+	}
+	if list.Total != 2 || len(list.Products) != 2 {
+		t.Fatalf("low stock products = %d (total %d), want 2", len(list.Products), list.Total)
+	}
+	// Paling kritis lebih dulu: variant dengan stok 1 sebelum product dengan stok 2
+	if list.Products[0].ID != running.ID || list.Products[1].ID != plain.ID {
+		t.Errorf("low stock order = [%d %d], want [%d %d]", list.Products[0].ID, list.Products[1].ID, running.ID, plain.ID)
+	}
+}
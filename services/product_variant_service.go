@@ -0,0 +1,160 @@
+package services
+
+import (
+	"errors"
+	"tokogo/config"
+	"tokogo/models"
+	"tokogo/repositories"
+	"tokogo/requests"
+	"tokogo/responses"
+
+	"gorm.io/gorm"
+)
+
+type ProductVariantService struct {
+	db          *gorm.DB
+	productRepo *repositories.ProductRepository
+	variantRepo *repositories.ProductVariantRepository
+	cartRepo    *repositories.CartRepository
+	inventory   *InventoryService
+}
+
+// NewProductVariantService membuat instance baru ProductVariantService
+func NewProductVariantService() *ProductVariantService {
+	return &ProductVariantService{
+		db:          config.DB,
+		productRepo: repositories.NewProductRepository(config.DB),
+		variantRepo: repositories.NewProductVariantRepository(config.DB),
+		cartRepo:    repositories.NewCartRepository(config.DB),
+		inventory:   NewInventoryService(),
+	}
+}
+
+// GetVariants mengambil semua variant milik product
+func (s *ProductVariantService) GetVariants(productID uint) ([]responses.ProductVariantResponse, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	return responses.ConvertProductVariantsToResponse(product.Variants, *product), nil
+}
+
+// CreateVariant membuat variant baru, stok awal dicatat sebagai restock di ledger
+func (s *ProductVariantService) CreateVariant(productID uint, req requests.CreateProductVariantRequest, actorID uint) (*responses.ProductVariantResponse, error) {
+	// Validasi request
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	if err := s.checkUnique(product, 0, req.SKU, req.Options); err != nil {
+		return nil, err
+	}
+
+	variant := &models.ProductVariant{
+		ProductID:     productID,
+		SKU:           req.SKU,
+		Options:       req.Options,
+		PriceOverride: req.PriceOverride,
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.variantRepo.WithTx(tx).Create(variant); err != nil {
+			return errors.New("failed to create variant")
+		}
+
+		return s.inventory.WithTx(tx).AdjustStock(StockAdjustment{
+			ProductID: productID,
+			VariantID: &variant.ID,
+			Delta:     req.Stock,
+			Reason:    models.StockMovementRestock,
+			ActorID:   actorID,
+			Note:      "Initial variant stock",
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	variant.Stock = req.Stock
+	response := responses.ConvertProductVariantToResponse(*variant, *product)
+	return &response, nil
+}
+
+// UpdateVariant mengupdate SKU, opsi dan harga variant. Stok tidak diubah di sini,
+// perubahan stok hanya lewat stock adjustment agar selalu tercatat di ledger.
+func (s *ProductVariantService) UpdateVariant(productID, variantID uint, req requests.UpdateProductVariantRequest) (*responses.ProductVariantResponse, error) {
+	// Validasi request
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	variant, err := s.variantRepo.GetByIDAndProductID(variantID, productID)
+	if err != nil {
+		return nil, errors.New("variant not found")
+	}
+
+	if err := s.checkUnique(product, variantID, req.SKU, req.Options); err != nil {
+		return nil, err
+	}
+
+	variant.SKU = req.SKU
+	variant.Options = req.Options
+	variant.PriceOverride = req.PriceOverride
+
+	if err := s.variantRepo.Update(variant); err != nil {
+		return nil, errors.New("failed to update variant")
+	}
+
+	response := responses.ConvertProductVariantToResponse(*variant, *product)
+	return &response, nil
+}
+
+// DeleteVariant menghapus variant dan mengeluarkannya dari cart semua user.
+// Variant di-soft delete sehingga detail transaksi lama tetap bisa menampilkannya.
+func (s *ProductVariantService) DeleteVariant(productID, variantID uint) error {
+	if _, err := s.variantRepo.GetByIDAndProductID(variantID, productID); err != nil {
+		return errors.New("variant not found")
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.cartRepo.WithTx(tx).DeleteByVariantID(variantID); err != nil {
+			return errors.New("failed to remove variant from carts")
+		}
+
+		if err := s.variantRepo.WithTx(tx).Delete(variantID); err != nil {
+			return errors.New("failed to delete variant")
+		}
+
+		return nil
+	})
+}
+
+// checkUnique memastikan SKU belum dipakai dan kombinasi opsi belum ada di product yang sama
+func (s *ProductVariantService) checkUnique(product *models.Product, excludeID uint, sku string, options map[string]string) error {
+	exists, err := s.variantRepo.CheckSKUExists(sku, excludeID)
+	if err != nil {
+		return errors.New("failed to check sku")
+	}
+	if exists {
+		return errors.New("sku already exists")
+	}
+
+	for _, variant := range product.Variants {
+		if variant.ID != excludeID && variant.SameOptions(options) {
+			return errors.New("variant with the same options already exists")
+		}
+	}
+
+	return nil
+}
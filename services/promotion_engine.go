@@ -0,0 +1,263 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+	"tokogo/models"
+)
+
+// promotionResult adalah hasil evaluasi promosi otomatis. Lines sejajar dengan urutan cart
+// yang dievaluasi dan berisi promosi yang berlaku pada setiap item.
+type promotionResult struct {
+	Lines [][]models.AppliedPromotion
+	Total float64
+}
+
+// LineDiscounts mengembalikan total diskon promosi setiap item cart
+func (r promotionResult) LineDiscounts() []float64 {
+	discounts := make([]float64, len(r.Lines))
+	for i, applied := range r.Lines {
+		for _, promotion := range applied {
+			discounts[i] += promotion.Discount
+		}
+		discounts[i] = roundMoney(discounts[i])
+	}
+	return discounts
+}
+
+// promotionUnit adalah satu unit item cart yang belum dipakai promosi lain
+type promotionUnit struct {
+	line  int
+	price float64
+}
+
+// evaluatePromotions menerapkan promosi yang sedang berjalan ke item cart dengan aturan:
+//   - promosi dievaluasi berdasarkan Priority tertinggi, bila sama ID terkecil lebih dulu
+//   - setiap unit item hanya bisa dipakai oleh satu promosi
+//   - promosi Exclusive dilewati bila promosi lain sudah berlaku, dan bila berlaku menghentikan evaluasi
+//
+// Harga setiap item diambil dari Cart.UnitPrice pada waktu now sehingga harga sale ikut diperhitungkan.
+func evaluatePromotions(promotions []models.Promotion, carts []models.Cart, now time.Time) promotionResult {
+	result := promotionResult{Lines: make([][]models.AppliedPromotion, len(carts))}
+
+	ordered := make([]models.Promotion, 0, len(promotions))
+	for _, promotion := range promotions {
+		if promotion.IsRunning(now) {
+			ordered = append(ordered, promotion)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority > ordered[j].Priority
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	available := make([]int, len(carts))
+	prices := make([]float64, len(carts))
+	for i, cart := range carts {
+		available[i] = cart.Quantity
+		prices[i] = cart.UnitPrice(now)
+	}
+
+	applied := false
+	for _, promotion := range ordered {
+		if promotion.Exclusive && applied {
+			continue
+		}
+
+		var discounts map[int]float64
+		switch promotion.Type {
+		case models.PromotionTypeBuyXGetY:
+			discounts = applyBuyXGetY(promotion, carts, prices, available)
+		case models.PromotionTypeQuantityTier:
+			discounts = applyQuantityTier(promotion, carts, prices, available)
+		case models.PromotionTypeBundle:
+			discounts = applyBundle(promotion, carts, prices, available)
+		}
+		if len(discounts) == 0 {
+			continue
+		}
+
+		for line := range carts {
+			discount, ok := discounts[line]
+			if !ok || discount <= 0 {
+				continue
+			}
+			result.Lines[line] = append(result.Lines[line], models.AppliedPromotion{
+				PromotionID: promotion.ID,
+				Name:        promotion.Name,
+				Discount:    discount,
+			})
+			result.Total += discount
+		}
+		result.Total = roundMoney(result.Total)
+
+		applied = true
+		if promotion.Exclusive {
+			break
+		}
+	}
+
+	return result
+}
+
+// applyBuyXGetY mengelompokkan unit yang memenuhi syarat dari yang termahal per BuyQuantity+GetQuantity
+// unit, lalu GetQuantity unit termurah di setiap kelompok mendapat diskon GetDiscountPercent
+func applyBuyXGetY(promotion models.Promotion, carts []models.Cart, prices []float64, available []int) map[int]float64 {
+	if promotion.BuyQuantity < 1 || promotion.GetQuantity < 1 || promotion.GetDiscountPercent <= 0 {
+		return nil
+	}
+
+	units := eligibleUnits(promotion, carts, prices, available)
+	sort.SliceStable(units, func(i, j int) bool {
+		return units[i].price > units[j].price
+	})
+
+	groupSize := promotion.BuyQuantity + promotion.GetQuantity
+	groups := len(units) / groupSize
+	if groups == 0 {
+		return nil
+	}
+
+	discounts := make(map[int]float64)
+	for _, unit := range units[:groups*groupSize] {
+		available[unit.line]--
+	}
+	for g := 0; g < groups; g++ {
+		group := units[g*groupSize : (g+1)*groupSize]
+		for _, unit := range group[promotion.BuyQuantity:] {
+			discounts[unit.line] += unit.price * promotion.GetDiscountPercent / 100
+		}
+	}
+	return roundDiscounts(discounts)
+}
+
+// applyQuantityTier memberi diskon tier tertinggi yang tercapai oleh jumlah unit yang memenuhi syarat
+func applyQuantityTier(promotion models.Promotion, carts []models.Cart, prices []float64, available []int) map[int]float64 {
+	units := eligibleUnits(promotion, carts, prices, available)
+
+	var tier *models.PromotionTier
+	for i := range promotion.Tiers {
+		candidate := &promotion.Tiers[i]
+		if candidate.MinQuantity <= len(units) && (tier == nil || candidate.MinQuantity > tier.MinQuantity) {
+			tier = candidate
+		}
+	}
+	if tier == nil || tier.DiscountPercent <= 0 {
+		return nil
+	}
+
+	discounts := make(map[int]float64)
+	for _, unit := range units {
+		discounts[unit.line] += unit.price * tier.DiscountPercent / 100
+		available[unit.line]--
+	}
+	return roundDiscounts(discounts)
+}
+
+// applyBundle membentuk sebanyak mungkin paket berisi seluruh Items dan menjual setiap paket seharga
+// BundlePrice. Diskon setiap paket dibagi ke item sebanding dengan harganya.
+func applyBundle(promotion models.Promotion, carts []models.Cart, prices []float64, available []int) map[int]float64 {
+	if promotion.BundlePrice == nil || len(promotion.Items) == 0 {
+		return nil
+	}
+
+	// Jumlah paket dibatasi oleh item yang paling sedikit tersedia
+	bundles := -1
+	for _, item := range promotion.Items {
+		if item.Quantity < 1 {
+			return nil
+		}
+		units := 0
+		for line, cart := range carts {
+			if cart.ProductID == item.ProductID {
+				units += available[line]
+			}
+		}
+		if count := units / item.Quantity; bundles < 0 || count < bundles {
+			bundles = count
+		}
+	}
+	if bundles <= 0 {
+		return nil
+	}
+
+	// Ambil unit untuk setiap paket sesuai urutan item cart
+	taken := make(map[int]int)
+	var normalPrice float64
+	for _, item := range promotion.Items {
+		needed := bundles * item.Quantity
+		for line, cart := range carts {
+			if needed == 0 {
+				break
+			}
+			if cart.ProductID != item.ProductID {
+				continue
+			}
+			take := min(available[line]-taken[line], needed)
+			taken[line] += take
+			needed -= take
+			normalPrice += float64(take) * prices[line]
+		}
+	}
+
+	totalDiscount := roundMoney(normalPrice - float64(bundles)**promotion.BundlePrice)
+	if totalDiscount <= 0 {
+		return nil
+	}
+
+	discounts := make(map[int]float64)
+	lines := make([]int, 0, len(taken))
+	for line := range taken {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+
+	// Sisa pembulatan dibebankan ke item terakhir agar total diskon tetap tepat
+	remaining := totalDiscount
+	for i, line := range lines {
+		available[line] -= taken[line]
+		if i == len(lines)-1 {
+			discounts[line] = roundMoney(remaining)
+			break
+		}
+		share := roundMoney(totalDiscount * float64(taken[line]) * prices[line] / normalPrice)
+		discounts[line] = share
+		remaining -= share
+	}
+	return discounts
+}
+
+// eligibleUnits mengumpulkan unit yang masih tersedia dari item yang termasuk dalam promosi.
+// Promosi tanpa Items berlaku untuk semua product.
+func eligibleUnits(promotion models.Promotion, carts []models.Cart, prices []float64, available []int) []promotionUnit {
+	products := make(map[uint]bool, len(promotion.Items))
+	for _, item := range promotion.Items {
+		products[item.ProductID] = true
+	}
+
+	var units []promotionUnit
+	for line, cart := range carts {
+		if len(products) > 0 && !products[cart.ProductID] {
+			continue
+		}
+		for i := 0; i < available[line]; i++ {
+			units = append(units, promotionUnit{line: line, price: prices[line]})
+		}
+	}
+	return units
+}
+
+func roundDiscounts(discounts map[int]float64) map[int]float64 {
+	for line, discount := range discounts {
+		discounts[line] = roundMoney(discount)
+	}
+	return discounts
+}
+
+// roundMoney membulatkan nominal uang ke dua angka desimal
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"tokogo/models"
+)
+
+func promotionCartLine(productID uint, price float64, quantity int) models.Cart {
+	return models.Cart{
+		ProductID: productID,
+		Quantity:  quantity,
+		Product:   models.Product{ID: productID, SellingPrice: price},
+	}
+}
+
+func buyXGetY(id uint, buy, get int, percent float64, productIDs ...uint) models.Promotion {
+	return models.Promotion{ID: id, Type: models.PromotionTypeBuyXGetY, IsActive: true, BuyQuantity: buy, GetQuantity: get, GetDiscountPercent: percent, Items: promotionItems(productIDs...)}
+}
+
+func quantityTier(id uint, tiers []models.PromotionTier, productIDs ...uint) models.Promotion {
+	return models.Promotion{ID: id, Type: models.PromotionTypeQuantityTier, IsActive: true, Tiers: tiers, Items: promotionItems(productIDs...)}
+}
+
+func promotionItems(productIDs ...uint) []models.PromotionItem {
+	items := make([]models.PromotionItem, len(productIDs))
+	for i, id := range productIDs {
+		items[i] = models.PromotionItem{ProductID: id, Quantity: 1}
+	}
+	return items
+}
+
+func TestEvaluatePromotions(t *testing.T) {
+	bundlePrice := 25000.0
+	tenPercent := []models.PromotionTier{{MinQuantity: 1, DiscountPercent: 10}}
+
+	withPriority := func(promotion models.Promotion, priority int, exclusive bool) models.Promotion {
+		promotion.Priority = priority
+		promotion.Exclusive = exclusive
+		return promotion
+	}
+
+	tests := []struct {
+		name       string
+		promotions []models.Promotion
+		carts      []models.Cart
+		want       []float64
+		wantTotal  float64
+	}{
+		{
+			name:       "buy 2 get 1 free discounts the cheapest unit",
+			promotions: []models.Promotion{buyXGetY(1, 2, 1, 100)},
+			carts:      []models.Cart{promotionCartLine(1, 10000, 2), promotionCartLine(2, 5000, 1)},
+			want:       []float64{0, 5000},
+			wantTotal:  5000,
+		},
+		{
+			name:       "buy 2 get 1 groups units from the most expensive",
+			promotions: []models.Promotion{buyXGetY(1, 2, 1, 100)},
+			carts:      []models.Cart{promotionCartLine(1, 10000, 3), promotionCartLine(2, 5000, 3)},
+			want:       []float64{10000, 5000},
+			wantTotal:  15000,
+		},
+		{
+			name:       "buy 1 get 1 half price ignores the incomplete group",
+			promotions: []models.Promotion{buyXGetY(1, 1, 1, 50)},
+			carts:      []models.Cart{promotionCartLine(1, 10000, 3)},
+			want:       []float64{5000},
+			wantTotal:  5000,
+		},
+		{
+			name:       "buy x get y only counts listed products",
+			promotions: []models.Promotion{buyXGetY(1, 1, 1, 100, 1)},
+			carts:      []models.Cart{promotionCartLine(1, 10000, 1), promotionCartLine(2, 5000, 1)},
+			want:       []float64{0, 0},
+			wantTotal:  0,
+		},
+		{
+			name: "highest reached quantity tier applies",
+			promotions: []models.Promotion{quantityTier(1, []models.PromotionTier{
+				{MinQuantity: 3, DiscountPercent: 5},
+				{MinQuantity: 5, DiscountPercent: 10},
+			})},
+			carts:     []models.Cart{promotionCartLine(1, 1000, 3), promotionCartLine(2, 2000, 2)},
+			want:      []float64{300, 400},
+			wantTotal: 700,
+		},
+		{
+			name: "lower quantity tier applies below the next threshold",
+			promotions: []models.Promotion{quantityTier(1, []models.PromotionTier{
+				{MinQuantity: 3, DiscountPercent: 5},
+				{MinQuantity: 5, DiscountPercent: 10},
+			})},
+			carts:     []models.Cart{promotionCartLine(1, 1000, 2), promotionCartLine(2, 2000, 2)},
+			want:      []float64{100, 200},
+			wantTotal: 300,
+		},
+		{
+			name: "bundle splits the discount by item price",
+			promotions: []models.Promotion{{
+				ID: 1, Type: models.PromotionTypeBundle, IsActive: true, BundlePrice: &bundlePrice,
+				Items: []models.PromotionItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 2}},
+			}},
+			// Dua paket: 2 unit product 1 dan 4 unit product 2 seharga 2 x 25000
+			carts:     []models.Cart{promotionCartLine(1, 10000, 3), promotionCartLine(2, 10000, 4)},
+			want:      []float64{3333.33, 6666.67},
+			wantTotal: 10000,
+		},
+		{
+			name: "incomplete bundle gets no discount",
+			promotions: []models.Promotion{{
+				ID: 1, Type: models.PromotionTypeBundle, IsActive: true, BundlePrice: &bundlePrice,
+				Items: []models.PromotionItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 2}},
+			}},
+			carts:     []models.Cart{promotionCartLine(1, 10000, 3), promotionCartLine(2, 10000, 1)},
+			want:      []float64{0, 0},
+			wantTotal: 0,
+		},
+		{
+			name: "higher priority consumes units before lower priority",
+			promotions: []models.Promotion{
+				quantityTier(1, tenPercent),
+				withPriority(buyXGetY(2, 1, 1, 100, 1), 10, false),
+			},
+			// Dua unit product 1 dipakai buy 1 get 1, sisa unit baru mendapat tier 10%
+			carts:     []models.Cart{promotionCartLine(1, 1000, 3), promotionCartLine(2, 2000, 1)},
+			want:      []float64{1100, 200},
+			wantTotal: 1300,
+		},
+		{
+			name: "same priority is evaluated by lowest id",
+			promotions: []models.Promotion{
+				quantityTier(2, []models.PromotionTier{{MinQuantity: 1, DiscountPercent: 20}}),
+				quantityTier(1, []models.PromotionTier{{MinQuantity: 1, DiscountPercent: 5}}),
+			},
+			carts:     []models.Cart{promotionCartLine(1, 1000, 2)},
+			want:      []float64{100},
+			wantTotal: 100,
+		},
+		{
+			name: "exclusive promotion stops evaluation",
+			promotions: []models.Promotion{
+				withPriority(quantityTier(1, tenPercent, 1), 10, true),
+				quantityTier(2, tenPercent),
+			},
+			carts:     []models.Cart{promotionCartLine(1, 1000, 1), promotionCartLine(2, 1000, 1)},
+			want:      []float64{100, 0},
+			wantTotal: 100,
+		},
+		{
+			name: "exclusive promotion is skipped after another applies",
+			promotions: []models.Promotion{
+				withPriority(quantityTier(1, tenPercent, 1), 10, false),
+				withPriority(quantityTier(2, tenPercent), 0, true),
+			},
+			carts:     []models.Cart{promotionCartLine(1, 1000, 1), promotionCartLine(2, 1000, 1)},
+			want:      []float64{100, 0},
+			wantTotal: 100,
+		},
+		{
+			name: "exclusive promotion that does not apply does not stop evaluation",
+			promotions: []models.Promotion{
+				withPriority(quantityTier(1, []models.PromotionTier{{MinQuantity: 5, DiscountPercent: 50}}), 10, true),
+				quantityTier(2, tenPercent),
+			},
+			carts:     []models.Cart{promotionCartLine(1, 1000, 1), promotionCartLine(2, 1000, 1)},
+			want:      []float64{100, 100},
+			wantTotal: 200,
+		},
+		{
+			name:       "inactive promotion is ignored",
+			promotions: []models.Promotion{{ID: 1, Type: models.PromotionTypeQuantityTier, Tiers: tenPercent}},
+			carts:      []models.Cart{promotionCartLine(1, 1000, 1)},
+			want:       []float64{0},
+			wantTotal:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := evaluatePromotions(tt.promotions, tt.carts, time.Now())
+
+			got := result.LineDiscounts()
+			if len(got) != len(tt.want) {
+				t.Fatalf("line discounts = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("line discounts = %v, want %v", got, tt.want)
+					break
+				}
+			}
+			if result.Total != tt.wantTotal {
+				t.Errorf("total = %v, want %v", result.Total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestEvaluatePromotionsRecordsStackingOrder(t *testing.T) {
+	promotions := []models.Promotion{
+		quantityTier(1, []models.PromotionTier{{MinQuantity: 1, DiscountPercent: 10}}),
+		{ID: 2, Type: models.PromotionTypeBuyXGetY, IsActive: true, Priority: 10, BuyQuantity: 1, GetQuantity: 1, GetDiscountPercent: 100},
+	}
+	result := evaluatePromotions(promotions, []models.Cart{promotionCartLine(1, 1000, 3)}, time.Now())
+
+	applied := result.Lines[0]
+	if len(applied) != 2 || applied[0].PromotionID != 2 || applied[1].PromotionID != 1 {
+		t.Fatalf("applied promotions = %+v, want buy x get y then tier", applied)
+	}
+	if applied[0].Discount != 1000 || applied[1].Discount != 100 {
+		t.Errorf("applied discounts = %+v", applied)
+	}
+}
+
+func TestVoucherDiscountStacksAfterPromotions(t *testing.T) {
+	carts := []models.Cart{promotionCartLine(1, 1000, 2)}
+	result := evaluatePromotions([]models.Promotion{buyXGetY(1, 1, 1, 50)}, carts, time.Now())
+
+	// Voucher dihitung dari subtotal setelah diskon promosi: (2000 - 500) x 10%
+	voucher := models.Voucher{Type: models.VoucherTypePercentage, Value: 10}
+	discount, err := calculateVoucherDiscount(voucher, carts, result.LineDiscounts(), 0, nil, time.Now())
+	if err != nil {
+		t.Fatalf("voucher discount: %v", err)
+	}
+	if discount != 150 {
+		t.Errorf("voucher discount = %v, want 150", discount)
+	}
+}
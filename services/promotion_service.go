@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"tokogo/config"
+	"tokogo/models"
+	"tokogo/repositories"
+	"tokogo/requests"
+	"tokogo/responses"
+
+	"gorm.io/gorm"
+)
+
+type PromotionService struct {
+	db            *gorm.DB
+	promotionRepo *repositories.PromotionRepository
+	productRepo   *repositories.ProductRepository
+}
+
+// NewPromotionService membuat instance baru PromotionService
+func NewPromotionService() *PromotionService {
+	return &PromotionService{
+		db:            config.DB,
+		promotionRepo: repositories.NewPromotionRepository(config.DB),
+		productRepo:   repositories.NewProductRepository(config.DB),
+	}
+}
+
+// CreatePromotion membuat promosi otomatis baru
+func (s *PromotionService) CreatePromotion(req requests.CreatePromotionRequest) (*responses.PromotionResponse, error) {
+	promotion := &models.Promotion{IsActive: true}
+	if err := s.fillPromotion(promotion, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.promotionRepo.Create(promotion); err != nil {
+		return nil, errors.New("failed to create promotion")
+	}
+
+	return s.GetPromotionByID(promotion.ID)
+}
+
+// GetAllPromotions mengambil semua promosi dengan pagination sesuai urutan evaluasi
+func (s *PromotionService) GetAllPromotions(page, limit int) (*responses.PromotionListResponse, error) {
+	promotions, total, err := s.promotionRepo.GetAll(page, limit)
+	if err != nil {
+		return nil, errors.New("failed to get promotions")
+	}
+
+	return &responses.PromotionListResponse{
+		Promotions: responses.ConvertPromotionsToResponse(promotions),
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+	}, nil
+}
+
+// GetPromotionByID mengambil promosi berdasarkan ID
+func (s *PromotionService) GetPromotionByID(id uint) (*responses.PromotionResponse, error) {
+	promotion, err := s.promotionRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("promotion not found")
+	}
+
+	response := responses.ConvertPromotionToResponse(*promotion)
+	return &response, nil
+}
+
+// UpdatePromotion mengupdate promosi berdasarkan ID. Transaksi yang sudah memakai promosi tidak berubah.
+func (s *PromotionService) UpdatePromotion(id uint, req requests.UpdatePromotionRequest) (*responses.PromotionResponse, error) {
+	promotion, err := s.promotionRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("promotion not found")
+	}
+
+	// Field request create dan update identik
+	if err := s.fillPromotion(promotion, requests.CreatePromotionRequest(req)); err != nil {
+		return nil, err
+	}
+
+	// Item dan tier lama diganti dalam satu transaction
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return s.promotionRepo.WithTx(tx).Update(promotion)
+	})
+	if err != nil {
+		return nil, errors.New("failed to update promotion")
+	}
+
+	return s.GetPromotionByID(id)
+}
+
+// DeletePromotion menghapus promosi sehingga tidak lagi dievaluasi saat checkout
+func (s *PromotionService) DeletePromotion(id uint) error {
+	if _, err := s.promotionRepo.GetByID(id); err != nil {
+		return errors.New("promotion not found")
+	}
+
+	if err := s.promotionRepo.Delete(id); err != nil {
+		return errors.New("failed to delete promotion")
+	}
+	return nil
+}
+
+// fillPromotion mengisi field promosi dari request dan memastikan product di items ada.
+// Field yang tidak dipakai jenis promosinya dikosongkan.
+func (s *PromotionService) fillPromotion(promotion *models.Promotion, req requests.CreatePromotionRequest) error {
+	items := make([]models.PromotionItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		if _, err := s.productRepo.GetByID(item.ProductID); err != nil {
+			return fmt.Errorf("product with ID %d not found", item.ProductID)
+		}
+		quantity := item.Quantity
+		if quantity < 1 {
+			quantity = 1
+		}
+		items = append(items, models.PromotionItem{ProductID: item.ProductID, Quantity: quantity})
+	}
+
+	tiers := make([]models.PromotionTier, 0, len(req.Tiers))
+	for _, tier := range req.Tiers {
+		tiers = append(tiers, models.PromotionTier{MinQuantity: tier.MinQuantity, DiscountPercent: tier.DiscountPercent})
+	}
+
+	promotion.Name = req.Name
+	promotion.Description = req.Description
+	promotion.Type = req.Type
+	promotion.Priority = req.Priority
+	promotion.Exclusive = req.Exclusive
+	promotion.StartsAt = req.StartsAt
+	promotion.EndsAt = req.EndsAt
+	promotion.Items = items
+	promotion.BuyQuantity = 0
+	promotion.GetQuantity = 0
+	promotion.GetDiscountPercent = 0
+	promotion.BundlePrice = nil
+	promotion.Tiers = nil
+	// Status aktif dipertahankan jika tidak dikirim
+	if req.IsActive != nil {
+		promotion.IsActive = *req.IsActive
+	}
+
+	switch req.Type {
+	case models.PromotionTypeBuyXGetY:
+		promotion.BuyQuantity = req.BuyQuantity
+		promotion.GetQuantity = req.GetQuantity
+		promotion.GetDiscountPercent = req.GetDiscountPercent
+	case models.PromotionTypeQuantityTier:
+		promotion.Tiers = tiers
+	case models.PromotionTypeBundle:
+		promotion.BundlePrice = req.BundlePrice
+	}
+
+	return nil
+}
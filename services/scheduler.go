@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// startPeriodicJob menjalankan job setiap interval di goroutine terpisah sampai ctx dibatalkan
+func startPeriodicJob(ctx context.Context, interval time.Duration, job func()) {
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				job()
+			}
+		}
+	}()
+}
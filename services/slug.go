@@ -0,0 +1,41 @@
+package services
+
+import (
+	"tokogo/helpers"
+	"tokogo/repositories"
+)
+
+// slugChange adalah hasil penentuan slug untuk entity yang dibuat atau di-rename
+type slugChange struct {
+	entityType string
+	model      interface{}
+	entityID   uint
+	current    string
+	fallback   string
+	reserved   []string
+}
+
+// resolve menghitung slug unik dari nama. Slug lama dipertahankan jika nama baru
+// menghasilkan slug dasar yang sama.
+func (c slugChange) resolve(slugRepo *repositories.SlugRepository, name string) (string, error) {
+	base := helpers.Slugify(name)
+	if base == "" {
+		base = c.fallback
+	}
+	if c.current != "" && helpers.IsSlugVariant(c.current, base) {
+		return c.current, nil
+	}
+	return slugRepo.UniqueSlug(c.model, base, c.entityID, c.reserved...)
+}
+
+// record mencatat slug lama sebagai redirect dan melepas redirect yang kini dipakai entity aktif.
+// entityID harus sudah terisi, jadi untuk entity baru dipanggil setelah create.
+func (c slugChange) record(slugRepo *repositories.SlugRepository, entityID uint, slug string) error {
+	if err := slugRepo.DeleteRedirect(c.entityType, slug); err != nil {
+		return err
+	}
+	if c.current == "" || c.current == slug {
+		return nil
+	}
+	return slugRepo.SaveRedirect(c.entityType, c.current, entityID)
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+	"tokogo/config"
+	"tokogo/repositories"
+)
+
+type StockReservationService struct {
+	reservationRepo *repositories.StockReservationRepository
+	interval        time.Duration
+}
+
+// NewStockReservationService membuat instance baru StockReservationService.
+// Interval pembersihan dibaca dari STOCK_RESERVATION_CLEANUP_INTERVAL_SECONDS.
+func NewStockReservationService() *StockReservationService {
+	return &StockReservationService{
+		reservationRepo: repositories.NewStockReservationRepository(config.DB),
+		interval:        time.Duration(getEnvInt("STOCK_RESERVATION_CLEANUP_INTERVAL_SECONDS", 60)) * time.Second,
+	}
+}
+
+// Start menghapus reservasi kedaluwarsa secara berkala sampai ctx dibatalkan
+func (s *StockReservationService) Start(ctx context.Context) {
+	startPeriodicJob(ctx, s.interval, func() {
+		released, err := s.ReleaseExpired()
+		if err != nil {
+			log.Printf("Stock reservation cleanup failed: %v", err)
+		}
+		if released > 0 {
+			log.Printf("Released %d expired stock reservation(s)", released)
+		}
+	})
+}
+
+// ReleaseExpired menghapus reservasi yang sudah melewati batas waktunya.
+// Reservasi kedaluwarsa sudah tidak dihitung sejak expires_at terlewati,
+// job ini hanya membersihkan row-nya.
+func (s *StockReservationService) ReleaseExpired() (int64, error) {
+	return s.reservationRepo.DeleteExpired(time.Now())
+}
+
+// stockReservationTTL mengembalikan lama reservasi stok dari STOCK_RESERVATION_MINUTES
+func stockReservationTTL() time.Duration {
+	return time.Duration(getEnvInt("STOCK_RESERVATION_MINUTES", 15)) * time.Minute
+}
@@ -1,20 +1,33 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	"time"
 	"tokogo/config"
+	"tokogo/helpers"
+	"tokogo/models"
 	"tokogo/repositories"
 	"tokogo/requests"
 	"tokogo/responses"
+
+	"gorm.io/gorm"
 )
 
 type TransactionService struct {
+	db              *gorm.DB
 	transactionRepo *repositories.TransactionRepository
+	inventory       *InventoryService
+	paymentGateway  PaymentGateway
 }
 
 // NewTransactionService membuat instance baru TransactionService
 func NewTransactionService() *TransactionService {
 	return &TransactionService{
+		db:              config.DB,
 		transactionRepo: repositories.NewTransactionRepository(config.DB),
+		inventory:       NewInventoryService(),
+		paymentGateway:  DefaultPaymentGateway(),
 	}
 }
 
@@ -34,19 +47,61 @@ func (s *TransactionService) GetAllTransactions(page, limit int, status string)
 		return nil, err
 	}
 
-	// Convert to response format
+	return convertTransactionsToListResponse(transactions, total, page, limit), nil
+}
+
+// GetAllTransactionsByCursor mengambil transaksi dengan pagination berbasis cursor
+func (s *TransactionService) GetAllTransactionsByCursor(cursor string, limit int, status string) (*responses.TransactionListResponse, error) {
+	limit = normalizeCursorLimit(limit)
+
+	transactions, next, err := s.transactionRepo.GetAllTransactionsByCursor(cursor, limit, status)
+	if err != nil {
+		return nil, err
+	}
+
+	response := convertTransactionsToListResponse(transactions, 0, 0, limit)
+	response.NextCursor = next
+	return response, nil
+}
+
+// GetPaymentVerificationQueue mengambil transaksi bank transfer yang menunggu verifikasi bukti pembayaran
+func (s *TransactionService) GetPaymentVerificationQueue(page, limit int) (*responses.TransactionListResponse, error) {
+	// Set default values
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	// Antrian diproses dari yang paling lama menunggu
+	transactions, total, err := s.transactionRepo.GetAwaitingVerification(page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTransactionsToListResponse(transactions, total, page, limit), nil
+}
+
+// convertTransactionsToListResponse mengkonversi list transaksi ke format response
+func convertTransactionsToListResponse(transactions []models.Transaction, total int64, page, limit int) *responses.TransactionListResponse {
 	var transactionResponses []responses.TransactionResponse
 	for _, transaction := range transactions {
 		transactionResponse := responses.TransactionResponse{
-			ID:          int64(transaction.ID),
-			UserID:      int64(transaction.UserID),
-			UserName:    transaction.User.Name,
-			UserEmail:   transaction.User.Email,
-			Status:      transaction.Status,
-			TotalAmount: transaction.TotalAmount,
-			PaymentURL:  transaction.PaymentURL,
-			CreatedAt:   transaction.CreatedAt,
-			UpdatedAt:   transaction.UpdatedAt,
+			ID:                int64(transaction.ID),
+			UserID:            int64(transaction.UserID),
+			UserName:          transaction.User.Name,
+			UserEmail:         transaction.User.Email,
+			Status:            transaction.Status,
+			TotalAmount:       transaction.TotalAmount,
+			VoucherCode:       transaction.VoucherCode,
+			DiscountAmount:    transaction.DiscountAmount,
+			PromotionDiscount: transaction.PromotionDiscount,
+			PaymentMethod:     transaction.PaymentMethod,
+			PaymentURL:        transaction.PaymentURL,
+			PaymentProof:      helpers.SignedURL(transaction.PaymentProof),
+			CreatedAt:         transaction.CreatedAt,
+			UpdatedAt:         transaction.UpdatedAt,
 		}
 		transactionResponses = append(transactionResponses, transactionResponse)
 	}
@@ -56,7 +111,7 @@ func (s *TransactionService) GetAllTransactions(page, limit int, status string)
 		Total:        int64(total),
 		Page:         page,
 		Limit:        limit,
-	}, nil
+	}
 }
 
 // GetTransactionByID mengambil transaksi berdasarkan ID dengan detail
@@ -75,46 +130,111 @@ func (s *TransactionService) GetTransactionByID(id uint) (*responses.Transaction
 			TransactionID: int64(detail.TransactionID),
 			ProductID:     int64(detail.ProductID),
 			ProductName:   detail.Product.Name,
-			ProductImage:  detail.Product.ImageURL,
+			ProductImage:  helpers.PublicURL(detail.Product.ImageURL),
+			VariantID:     detail.VariantID,
 			Quantity:      detail.Quantity,
 			Price:         detail.Price,
-			Subtotal:      float64(detail.Quantity) * detail.Price,
+			Discount:      detail.Discount,
+			Promotions:    detail.Promotions,
+			Subtotal:      float64(detail.Quantity)*detail.Price - detail.Discount,
+		}
+		if detail.Variant != nil {
+			detailResponse.VariantSKU = detail.Variant.SKU
+			detailResponse.VariantOptions = detail.Variant.Options
 		}
 		detailResponses = append(detailResponses, detailResponse)
 	}
 
 	// Convert to response format
 	transactionResponse := &responses.TransactionResponse{
-		ID:          int64(transaction.ID),
-		UserID:      int64(transaction.UserID),
-		UserName:    transaction.User.Name,
-		UserEmail:   transaction.User.Email,
-		Status:      transaction.Status,
-		TotalAmount: transaction.TotalAmount,
-		PaymentURL:  transaction.PaymentURL,
-		CreatedAt:   transaction.CreatedAt,
-		UpdatedAt:   transaction.UpdatedAt,
-		Details:     detailResponses,
+		ID:                   int64(transaction.ID),
+		UserID:               int64(transaction.UserID),
+		UserName:             transaction.User.Name,
+		UserEmail:            transaction.User.Email,
+		Status:               transaction.Status,
+		TotalAmount:          transaction.TotalAmount,
+		VoucherCode:          transaction.VoucherCode,
+		DiscountAmount:       transaction.DiscountAmount,
+		PromotionDiscount:    transaction.PromotionDiscount,
+		PaymentMethod:        transaction.PaymentMethod,
+		PaymentURL:           transaction.PaymentURL,
+		PaymentProof:         helpers.SignedURL(transaction.PaymentProof),
+		PaymentRejectionNote: transaction.PaymentRejectionNote,
+		PaymentDueAt:         transaction.PaymentDueAt,
+		CancellationReason:   transaction.CancellationReason,
+		RefundStatus:         transaction.RefundStatus,
+		RefundError:          transaction.RefundError,
+		RefundStartedAt:      transaction.RefundStartedAt,
+		RefundedAt:           transaction.RefundedAt,
+		LatePaymentAt:        transaction.LatePaymentAt,
+		CreatedAt:            transaction.CreatedAt,
+		UpdatedAt:            transaction.UpdatedAt,
+		Details:              detailResponses,
+		StatusHistory:        responses.ConvertStatusHistoriesToResponse(transaction.StatusHistories),
 	}
 
 	return transactionResponse, nil
 }
 
-// UpdateTransactionStatus mengupdate status transaksi
-func (s *TransactionService) UpdateTransactionStatus(id uint, req requests.UpdateTransactionStatusRequest) (*responses.TransactionResponse, error) {
+// UpdateTransactionStatus mengupdate status transaksi sesuai transition table
+func (s *TransactionService) UpdateTransactionStatus(id uint, actorID uint, req requests.UpdateTransactionStatusRequest) (*responses.TransactionResponse, error) {
 	// Validasi request
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 
-	// Cek apakah transaction ada
-	_, err := s.transactionRepo.GetTransactionByID(id)
-	if err != nil {
-		return nil, err
+	// Refund memanggil payment gateway sehingga diproses terpisah di luar row lock
+	if req.Status == models.TransactionStatusRefunded {
+		if err := s.refundTransaction(id, actorID, req.Note); err != nil {
+			return nil, err
+		}
+		return s.GetTransactionByID(id)
 	}
 
-	// Update transaction status
-	err = s.transactionRepo.UpdateTransactionStatus(id, req.Status)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+
+		inventory := s.inventory.WithTx(tx)
+
+		// Cek apakah transaction ada dan kunci row-nya
+		transaction, err := transactionRepo.GetByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		// Refund yang belum selesai harus diselesaikan lewat refund ulang, bukan perubahan status lain
+		if transaction.RefundStatus == models.RefundStatusPending {
+			return errors.New("a refund for this transaction is in progress, retry the refund to complete it")
+		}
+
+		// Penolakan bukti transfer harus mencatat alasan dan memulai ulang payment window
+		if transaction.Status == models.TransactionStatusAwaitingVerification && req.Status == models.TransactionStatusPending {
+			return errors.New("use the reject payment proof endpoint to return a transaction to pending")
+		}
+
+		// Pembayaran hanya dikonfirmasi oleh webhook payment gateway atau persetujuan bukti transfer
+		if req.Status == models.TransactionStatusPaid {
+			if transaction.Status == models.TransactionStatusAwaitingVerification {
+				return errors.New("use the approve payment proof endpoint to mark a transaction as paid")
+			}
+			return errors.New("transactions are marked as paid by the payment gateway")
+		}
+
+		switch req.Status {
+		case models.TransactionStatusCancelled:
+			return cancelTransaction(transactionRepo, inventory, transaction, actorID, models.ActorRoleAdmin, req.Note)
+		case models.TransactionStatusFailed, models.TransactionStatusExpired:
+			// Order yang gagal atau kedaluwarsa melepaskan stok yang sudah dipotong saat checkout
+			if err := transaction.CanTransitionTo(req.Status); err != nil {
+				return err
+			}
+			if err := releaseTransaction(transactionRepo, inventory, transaction.ID, actorID, "Order "+req.Status); err != nil {
+				return err
+			}
+		}
+
+		return changeTransactionStatus(transactionRepo, transaction, req.Status, actorID, models.ActorRoleAdmin, req.Note)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -127,3 +247,223 @@ func (s *TransactionService) UpdateTransactionStatus(id uint, req requests.Updat
 
 	return transactionResponse, nil
 }
+
+// refundRetryAfter adalah lama refund boleh berstatus pending sebelum dianggap terhenti dan boleh diulang
+const refundRetryAfter = 15 * time.Minute
+
+// refundTransaction mengembalikan dana order melalui payment gateway lalu memindahkan statusnya ke refunded.
+// Gateway dipanggil di luar database transaction agar row transaksi tidak terkunci selama menunggu gateway:
+// refund ditandai pending lebih dulu, lalu hasil dari gateway dicatat di transaction berikutnya.
+// Bila proses berhenti sebelum hasilnya dicatat, refund tetap pending dan bisa diulang setelah refundRetryAfter.
+// Order yang belum dikirim ikut mengembalikan stok dan voucher-nya.
+func (s *TransactionService) refundTransaction(id uint, actorID uint, note string) error {
+	var reference string
+	var amount float64
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+
+		transaction, err := transactionRepo.GetByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if err := transaction.CanTransitionTo(models.TransactionStatusRefunded); err != nil {
+			return err
+		}
+		// Refund pending yang sudah lama dianggap terhenti dan diulang, gateway refund idempotent per reference
+		if transaction.IsRefundInProgress(time.Now(), refundRetryAfter) {
+			return errors.New("a refund for this transaction is already in progress")
+		}
+
+		reference = transaction.PaymentReference
+		amount = transaction.TotalAmount
+		if reference == "" {
+			return nil
+		}
+		if err := transactionRepo.UpdateRefundResult(id, models.RefundStatusPending, ""); err != nil {
+			return errors.New("failed to start refund")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Dana dikembalikan melalui payment gateway bila order dibayar lewat gateway
+	if reference != "" {
+		if refundErr := s.paymentGateway.Refund(reference, amount); refundErr != nil {
+			if err := s.transactionRepo.UpdateRefundResult(id, models.RefundStatusFailed, refundErr.Error()); err != nil {
+				fmt.Printf("Warning: Failed to record refund failure for transaction %d: %v\n", id, err)
+			}
+			return fmt.Errorf("failed to refund payment: %v", refundErr)
+		}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		inventory := s.inventory.WithTx(tx)
+
+		transaction, err := transactionRepo.GetByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		if transaction.Status == models.TransactionStatusPaid || transaction.Status == models.TransactionStatusProcessing {
+			if err := releaseTransaction(transactionRepo, inventory, transaction.ID, actorID, "Order refunded"); err != nil {
+				return err
+			}
+		}
+
+		if reference != "" {
+			if err := transactionRepo.UpdateRefundResult(id, models.RefundStatusSucceeded, ""); err != nil {
+				return errors.New("failed to record refund")
+			}
+		}
+
+		return changeTransactionStatus(transactionRepo, transaction, models.TransactionStatusRefunded, actorID, models.ActorRoleAdmin, note)
+	})
+	if err != nil {
+		// Status refund tetap pending agar dana yang sudah dikembalikan tidak di-refund dua kali
+		return fmt.Errorf("payment was refunded but the transaction could not be updated: %v", err)
+	}
+
+	return nil
+}
+
+// ApprovePaymentProof menyetujui bukti transfer sehingga transaksi menjadi paid
+func (s *TransactionService) ApprovePaymentProof(id uint, actorID uint) (*responses.TransactionResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+
+		transaction, err := transactionRepo.GetByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		if transaction.Status != models.TransactionStatusAwaitingVerification {
+			return errors.New("transaction is not awaiting payment verification")
+		}
+
+		return changeTransactionStatus(transactionRepo, transaction, models.TransactionStatusPaid, actorID, models.ActorRoleAdmin, "Payment proof approved")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetTransactionByID(id)
+}
+
+// RejectPaymentProof menolak bukti transfer dan mengembalikan transaksi ke pending dengan catatan penolakan
+func (s *TransactionService) RejectPaymentProof(id uint, actorID uint, req requests.RejectPaymentProofRequest) (*responses.TransactionResponse, error) {
+	// Validasi request
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+
+		transaction, err := transactionRepo.GetByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		if transaction.Status != models.TransactionStatusAwaitingVerification {
+			return errors.New("transaction is not awaiting payment verification")
+		}
+
+		if err := transactionRepo.UpdatePaymentRejectionNote(transaction.ID, req.Note); err != nil {
+			return errors.New("failed to save rejection note")
+		}
+
+		// Customer mendapat payment window baru untuk mengunggah ulang bukti pembayaran
+		if err := transactionRepo.UpdatePaymentDueAt(transaction.ID, time.Now().Add(paymentWindow())); err != nil {
+			return errors.New("failed to reset payment deadline")
+		}
+
+		return changeTransactionStatus(transactionRepo, transaction, models.TransactionStatusPending, actorID, models.ActorRoleAdmin, req.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetTransactionByID(id)
+}
+
+// CancelTransaction membatalkan transaksi oleh admin atas nama customer dan mengembalikan stoknya
+func (s *TransactionService) CancelTransaction(id uint, actorID uint, req requests.CancelTransactionRequest) (*responses.TransactionResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		transactionRepo := s.transactionRepo.WithTx(tx)
+		inventory := s.inventory.WithTx(tx)
+
+		transaction, err := transactionRepo.GetByIDForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		return cancelTransaction(transactionRepo, inventory, transaction, actorID, models.ActorRoleAdmin, req.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetTransactionByID(id)
+}
+
+// cancelTransaction membatalkan transaksi yang sudah dikunci, mengembalikan stok,
+// dan mencatat alasan pembatalan. Repository dan inventory harus terikat pada database transaction milik pemanggil.
+func cancelTransaction(transactionRepo *repositories.TransactionRepository, inventory *InventoryService, transaction *models.Transaction, actorID uint, actorRole string, reason string) error {
+	if transaction.IsPaymentCollected() {
+		return errors.New("paid transaction cannot be cancelled, the payment must be refunded by admin")
+	}
+	if !transaction.IsCancellableBy(actorRole) {
+		return fmt.Errorf("transaction with status %s cannot be cancelled", transaction.Status)
+	}
+
+	if err := releaseTransaction(transactionRepo, inventory, transaction.ID, actorID, "Order cancelled: "+reason); err != nil {
+		return err
+	}
+
+	if err := transactionRepo.UpdateCancellationReason(transaction.ID, reason); err != nil {
+		return errors.New("failed to save cancellation reason")
+	}
+	transaction.CancellationReason = reason
+
+	return changeTransactionStatus(transactionRepo, transaction, models.TransactionStatusCancelled, actorID, actorRole, reason)
+}
+
+// changeTransactionStatus memindahkan status transaksi dan mencatat history-nya.
+// transactionRepo harus terikat pada database transaction milik pemanggil
+// dan row transaksi sebaiknya sudah dikunci.
+func changeTransactionStatus(transactionRepo *repositories.TransactionRepository, transaction *models.Transaction, status string, actorID uint, actorRole string, note string) error {
+	if err := transaction.CanTransitionTo(status); err != nil {
+		return err
+	}
+
+	fromStatus := transaction.Status
+	if err := transactionRepo.UpdateTransactionStatus(transaction.ID, status); err != nil {
+		return errors.New("failed to update transaction status")
+	}
+	transaction.Status = status
+
+	return recordStatusHistory(transactionRepo, transaction.ID, fromStatus, status, actorID, actorRole, note)
+}
+
+// recordStatusHistory menyimpan satu baris status history. actorID 0 berarti perubahan oleh sistem.
+func recordStatusHistory(transactionRepo *repositories.TransactionRepository, transactionID uint, fromStatus, toStatus string, actorID uint, actorRole string, note string) error {
+	history := &models.TransactionStatusHistory{
+		TransactionID: transactionID,
+		FromStatus:    fromStatus,
+		ToStatus:      toStatus,
+		ActorRole:     actorRole,
+		Note:          note,
+	}
+	if actorID != 0 {
+		history.ActorID = &actorID
+	}
+
+	if err := transactionRepo.CreateStatusHistory(history); err != nil {
+		return errors.New("failed to record transaction status history")
+	}
+	return nil
+}
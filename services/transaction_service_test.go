@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"tokogo/models"
+	"tokogo/requests"
+
+	"gorm.io/gorm"
+)
+
+// refundGateway mencatat setiap refund dan bisa diatur untuk menolaknya
+type refundGateway struct {
+	PaymentGateway
+	err   error
+	calls int
+}
+
+func (g *refundGateway) Refund(string, float64) error {
+	g.calls++
+	return g.err
+}
+
+// seedPaidTransaction membuat transaksi paid yang dibayar lewat payment gateway
+func seedPaidTransaction(t *testing.T, db *gorm.DB) models.Transaction {
+	t.Helper()
+
+	user := models.User{Name: "Buyer", Email: "buyer@example.com", Password: "secret", Role: "customer"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	transaction := models.Transaction{
+		UserID:           user.ID,
+		Status:           models.TransactionStatusPaid,
+		TotalAmount:      10000,
+		ShippingAddress:  "Jl. Test 1",
+		PaymentMethod:    "e_wallet",
+		PaymentReference: "FAKE-REFUND",
+	}
+	if err := db.Create(&transaction).Error; err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+	return transaction
+}
+
+func TestRefundGatewayFailureKeepsOrderPaid(t *testing.T) {
+	db := openTestDB(t)
+	transaction := seedPaidTransaction(t, db)
+
+	service := NewTransactionService()
+	gateway := &refundGateway{PaymentGateway: service.paymentGateway, err: errors.New("gateway unavailable")}
+	service.paymentGateway = gateway
+
+	refund := requests.UpdateTransactionStatusRequest{Status: models.TransactionStatusRefunded, Note: "Customer request"}
+	if _, err := service.UpdateTransactionStatus(transaction.ID, 1, refund); err == nil {
+		t.Fatal("refund succeeded while the gateway rejected it")
+	}
+
+	var reloaded models.Transaction
+	db.First(&reloaded, transaction.ID)
+	if reloaded.Status != models.TransactionStatusPaid {
+		t.Errorf("status = %s, want %s", reloaded.Status, models.TransactionStatusPaid)
+	}
+	if reloaded.RefundStatus != models.RefundStatusFailed || reloaded.RefundError == "" {
+		t.Errorf("refund status = %q, error = %q, want failed with the gateway error", reloaded.RefundStatus, reloaded.RefundError)
+	}
+
+	// Refund yang gagal bisa langsung diulang
+	gateway.err = nil
+	if _, err := service.UpdateTransactionStatus(transaction.ID, 1, refund); err != nil {
+		t.Fatalf("retry refund: %v", err)
+	}
+	reloaded = models.Transaction{}
+	db.First(&reloaded, transaction.ID)
+	if reloaded.Status != models.TransactionStatusRefunded || reloaded.RefundStatus != models.RefundStatusSucceeded {
+		t.Errorf("status = %s, refund status = %s, want refunded and succeeded", reloaded.Status, reloaded.RefundStatus)
+	}
+}
+
+func TestStalePendingRefundCanBeRetried(t *testing.T) {
+	db := openTestDB(t)
+	transaction := seedPaidTransaction(t, db)
+
+	service := NewTransactionService()
+	gateway := &refundGateway{PaymentGateway: service.paymentGateway}
+	service.paymentGateway = gateway
+	refund := requests.UpdateTransactionStatusRequest{Status: models.TransactionStatusRefunded}
+
+	// Refund yang baru dimulai tidak boleh dijalankan dua kali
+	db.Model(&transaction).Updates(map[string]interface{}{"refund_status": models.RefundStatusPending, "refund_started_at": time.Now()})
+	if _, err := service.UpdateTransactionStatus(transaction.ID, 1, refund); err == nil {
+		t.Fatal("refund started while another refund is in progress")
+	}
+	if gateway.calls != 0 {
+		t.Fatalf("gateway refund calls = %d, want 0", gateway.calls)
+	}
+
+	// Proses sebelumnya mati sebelum hasil refund dicatat
+	db.Model(&transaction).Update("refund_started_at", time.Now().Add(-time.Hour))
+	if _, err := service.UpdateTransactionStatus(transaction.ID, 1, refund); err != nil {
+		t.Fatalf("retry stale refund: %v", err)
+	}
+
+	var reloaded models.Transaction
+	db.First(&reloaded, transaction.ID)
+	if reloaded.Status != models.TransactionStatusRefunded || reloaded.RefundStatus != models.RefundStatusSucceeded {
+		t.Errorf("status = %s, refund status = %s, want refunded and succeeded", reloaded.Status, reloaded.RefundStatus)
+	}
+	if gateway.calls != 1 {
+		t.Errorf("gateway refund calls = %d, want 1", gateway.calls)
+	}
+}
+
+func TestPaymentProofCannotBeRejectedThroughStatusUpdate(t *testing.T) {
+	db := openTestDB(t)
+
+	user := models.User{Name: "Buyer", Email: "buyer@example.com", Password: "secret", Role: "customer"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	dueAt := time.Now().Add(-time.Hour)
+	transaction := models.Transaction{
+		UserID:          user.ID,
+		Status:          models.TransactionStatusAwaitingVerification,
+		TotalAmount:     10000,
+		ShippingAddress: "Jl. Test 1",
+		PaymentMethod:   models.PaymentMethodBankTransfer,
+		PaymentDueAt:    &dueAt,
+	}
+	if err := db.Create(&transaction).Error; err != nil {
+		t.Fatalf("create transaction: %v", err)
+	}
+
+	_, err := NewTransactionService().UpdateTransactionStatus(transaction.ID, 1, requests.UpdateTransactionStatusRequest{Status: models.TransactionStatusPending})
+	if err == nil {
+		t.Fatal("status update moved an awaiting verification transaction back to pending")
+	}
+
+	var reloaded models.Transaction
+	db.First(&reloaded, transaction.ID)
+	if reloaded.Status != models.TransactionStatusAwaitingVerification {
+		t.Errorf("status = %s, want %s", reloaded.Status, models.TransactionStatusAwaitingVerification)
+	}
+}
+
+func TestStatusUpdateCannotMarkTransactionPaid(t *testing.T) {
+	db := openTestDB(t)
+
+	user := models.User{Name: "Buyer", Email: "buyer@example.com", Password: "secret", Role: "customer"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	service := NewTransactionService()
+	for _, status := range []string{models.TransactionStatusPending, models.TransactionStatusAwaitingVerification} {
+		transaction := models.Transaction{
+			UserID:          user.ID,
+			Status:          status,
+			TotalAmount:     10000,
+			ShippingAddress: "Jl. Test 1",
+			PaymentMethod:   models.PaymentMethodBankTransfer,
+		}
+		if err := db.Create(&transaction).Error; err != nil {
+			t.Fatalf("create transaction: %v", err)
+		}
+
+		if _, err := service.UpdateTransactionStatus(transaction.ID, 1, requests.UpdateTransactionStatusRequest{Status: models.TransactionStatusPaid}); err == nil {
+			t.Errorf("status update marked a %s transaction as paid", status)
+		}
+
+		var reloaded models.Transaction
+		db.First(&reloaded, transaction.ID)
+		if reloaded.Status != status {
+			t.Errorf("status = %s, want %s", reloaded.Status, status)
+		}
+	}
+}
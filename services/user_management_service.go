@@ -86,6 +86,25 @@ func (s *UserManagementService) GetAllUsers(page, limit int) (*responses.UserLis
 	}, nil
 }
 
+// GetAllUsersByCursor mengambil users dengan pagination berbasis cursor
+func (s *UserManagementService) GetAllUsersByCursor(cursor string, limit int) (*responses.UserListResponse, error) {
+	limit = normalizeCursorLimit(limit)
+
+	users, next, err := s.userRepo.GetAllUsersByCursor(cursor, limit)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCursor) {
+			return nil, err
+		}
+		return nil, errors.New("failed to get users")
+	}
+
+	return &responses.UserListResponse{
+		Users:      responses.ConvertUsersToManagementResponse(users),
+		Limit:      limit,
+		NextCursor: next,
+	}, nil
+}
+
 // UpdateUser mengupdate user
 func (s *UserManagementService) UpdateUser(id uint, req requests.UpdateUserRequest) (*responses.UserManagementResponse, error) {
 	// Ambil user yang akan diupdate
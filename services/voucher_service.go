@@ -0,0 +1,278 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"tokogo/config"
+	"tokogo/models"
+	"tokogo/repositories"
+	"tokogo/requests"
+	"tokogo/responses"
+)
+
+type VoucherService struct {
+	voucherRepo  *repositories.VoucherRepository
+	categoryRepo *repositories.CategoryRepository
+	productRepo  *repositories.ProductRepository
+}
+
+// NewVoucherService membuat instance baru VoucherService
+func NewVoucherService() *VoucherService {
+	return &VoucherService{
+		voucherRepo:  repositories.NewVoucherRepository(config.DB),
+		categoryRepo: repositories.NewCategoryRepository(),
+		productRepo:  repositories.NewProductRepository(config.DB),
+	}
+}
+
+// CreateVoucher membuat voucher baru
+func (s *VoucherService) CreateVoucher(req requests.CreateVoucherRequest) (*responses.VoucherResponse, error) {
+	voucher := &models.Voucher{IsActive: true}
+	if err := s.fillVoucher(voucher, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.voucherRepo.Create(voucher); err != nil {
+		return nil, errors.New("failed to create voucher")
+	}
+
+	response := responses.ConvertVoucherToResponse(*voucher)
+	return &response, nil
+}
+
+// GetAllVouchers mengambil semua voucher dengan pagination
+func (s *VoucherService) GetAllVouchers(page, limit int) (*responses.VoucherListResponse, error) {
+	vouchers, total, err := s.voucherRepo.GetAll(page, limit)
+	if err != nil {
+		return nil, errors.New("failed to get vouchers")
+	}
+
+	return &responses.VoucherListResponse{
+		Vouchers: responses.ConvertVouchersToResponse(vouchers),
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	}, nil
+}
+
+// GetVoucherByID mengambil voucher berdasarkan ID
+func (s *VoucherService) GetVoucherByID(id uint) (*responses.VoucherResponse, error) {
+	voucher, err := s.voucherRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("voucher not found")
+	}
+
+	response := responses.ConvertVoucherToResponse(*voucher)
+	return &response, nil
+}
+
+// UpdateVoucher mengupdate voucher berdasarkan ID. Jumlah pemakaian tidak ikut berubah.
+func (s *VoucherService) UpdateVoucher(id uint, req requests.UpdateVoucherRequest) (*responses.VoucherResponse, error) {
+	voucher, err := s.voucherRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("voucher not found")
+	}
+
+	// Field request create dan update identik
+	if err := s.fillVoucher(voucher, requests.CreateVoucherRequest(req)); err != nil {
+		return nil, err
+	}
+
+	if err := s.voucherRepo.Update(voucher); err != nil {
+		return nil, errors.New("failed to update voucher")
+	}
+
+	return s.GetVoucherByID(id)
+}
+
+// DeleteVoucher menghapus voucher. Transaksi yang sudah memakai voucher tidak terpengaruh.
+func (s *VoucherService) DeleteVoucher(id uint) error {
+	if _, err := s.voucherRepo.GetByID(id); err != nil {
+		return errors.New("voucher not found")
+	}
+
+	if err := s.voucherRepo.Delete(id); err != nil {
+		return errors.New("failed to delete voucher")
+	}
+	return nil
+}
+
+// fillVoucher mengisi field voucher dari request dan memastikan kode unik serta
+// category dan product pembatasnya ada
+func (s *VoucherService) fillVoucher(voucher *models.Voucher, req requests.CreateVoucherRequest) error {
+	code := normalizeVoucherCode(req.Code)
+
+	exists, err := s.voucherRepo.CheckCodeExists(code, voucher.ID)
+	if err != nil {
+		return errors.New("failed to check voucher code")
+	}
+	if exists {
+		return errors.New("voucher code already exists")
+	}
+
+	categories := make([]models.Category, 0, len(req.CategoryIDs))
+	for _, categoryID := range req.CategoryIDs {
+		category, err := s.categoryRepo.GetCategoryByID(categoryID)
+		if err != nil {
+			return fmt.Errorf("category with ID %d not found", categoryID)
+		}
+		categories = append(categories, *category)
+	}
+
+	products := make([]models.Product, 0, len(req.ProductIDs))
+	for _, productID := range req.ProductIDs {
+		product, err := s.productRepo.GetByID(productID)
+		if err != nil {
+			return fmt.Errorf("product with ID %d not found", productID)
+		}
+		products = append(products, *product)
+	}
+
+	voucher.Code = code
+	voucher.Description = req.Description
+	voucher.Type = req.Type
+	voucher.Value = req.Value
+	voucher.MinSpend = req.MinSpend
+	voucher.MaxDiscount = req.MaxDiscount
+	voucher.UsageLimit = req.UsageLimit
+	voucher.PerUserLimit = req.PerUserLimit
+	voucher.StartsAt = req.StartsAt
+	voucher.EndsAt = req.EndsAt
+	voucher.Categories = categories
+	voucher.Products = products
+	// Status aktif dipertahankan jika tidak dikirim
+	if req.IsActive != nil {
+		voucher.IsActive = *req.IsActive
+	}
+	if req.Type == models.VoucherTypeFreeShipping {
+		voucher.Value = 0
+	}
+
+	return nil
+}
+
+// normalizeVoucherCode menyamakan format kode voucher agar tidak peka huruf besar kecil
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// quoteVoucher memeriksa apakah voucher boleh dipakai user untuk cart ini dan menghitung
+// diskonnya tanpa memakai kuota. lineDiscounts adalah diskon promosi otomatis setiap item cart.
+func quoteVoucher(voucherRepo *repositories.VoucherRepository, categoryRepo *repositories.CategoryRepository, voucher *models.Voucher, userID uint, carts []models.Cart, lineDiscounts []float64, shippingCost float64, now time.Time) (float64, error) {
+	if !voucher.IsActive {
+		return 0, errors.New("voucher is not active")
+	}
+	if voucher.StartsAt != nil && voucher.StartsAt.After(now) {
+		return 0, errors.New("voucher is not valid yet")
+	}
+	if voucher.EndsAt != nil && !voucher.EndsAt.After(now) {
+		return 0, errors.New("voucher has expired")
+	}
+	if voucher.IsExhausted() {
+		return 0, repositories.ErrVoucherExhausted
+	}
+
+	if voucher.PerUserLimit != nil {
+		used, err := voucherRepo.CountUserRedemptions(voucher.ID, userID)
+		if err != nil {
+			return 0, errors.New("failed to check voucher usage")
+		}
+		if used >= int64(*voucher.PerUserLimit) {
+			return 0, errors.New("you have reached the usage limit for this voucher")
+		}
+	}
+
+	categoryScope, err := voucherCategoryScope(categoryRepo, voucher)
+	if err != nil {
+		return 0, err
+	}
+
+	return calculateVoucherDiscount(*voucher, carts, lineDiscounts, shippingCost, categoryScope, now)
+}
+
+// voucherCategoryScope mengumpulkan category voucher beserta seluruh turunannya
+func voucherCategoryScope(categoryRepo *repositories.CategoryRepository, voucher *models.Voucher) (map[uint]bool, error) {
+	scope := make(map[uint]bool)
+	for _, category := range voucher.Categories {
+		ids, err := categoryRepo.GetDescendantIDs(category.ID)
+		if err != nil {
+			return nil, errors.New("failed to resolve voucher categories")
+		}
+		for _, id := range ids {
+			scope[id] = true
+		}
+	}
+	return scope, nil
+}
+
+// calculateVoucherDiscount menghitung diskon dari item yang memenuhi batasan voucher.
+// Voucher berlaku setelah promosi otomatis, sehingga subtotal item dikurangi lineDiscounts
+// (sejajar dengan carts) dan minimum belanja dibandingkan dengan subtotal setelah promosi tersebut.
+func calculateVoucherDiscount(voucher models.Voucher, carts []models.Cart, lineDiscounts []float64, shippingCost float64, categoryScope map[uint]bool, now time.Time) (float64, error) {
+	products := make(map[uint]bool, len(voucher.Products))
+	for _, product := range voucher.Products {
+		products[product.ID] = true
+	}
+
+	var eligibleSubtotal float64
+	for i, cart := range carts {
+		if voucher.IsRestricted() && !products[cart.ProductID] && !categoryScope[cart.Product.CategoryID] {
+			continue
+		}
+		eligibleSubtotal += float64(cart.Quantity) * cart.UnitPrice(now)
+		if i < len(lineDiscounts) {
+			eligibleSubtotal -= lineDiscounts[i]
+		}
+	}
+
+	if eligibleSubtotal <= 0 {
+		return 0, errors.New("voucher does not apply to any item in your cart")
+	}
+	if eligibleSubtotal < voucher.MinSpend {
+		return 0, fmt.Errorf("minimum spend of %.2f for this voucher has not been reached", voucher.MinSpend)
+	}
+
+	var discount float64
+	switch voucher.Type {
+	case models.VoucherTypePercentage:
+		discount = eligibleSubtotal * voucher.Value / 100
+	case models.VoucherTypeFixed:
+		discount = math.Min(voucher.Value, eligibleSubtotal)
+	case models.VoucherTypeFreeShipping:
+		discount = shippingCost
+	default:
+		return 0, fmt.Errorf("unsupported voucher type %s", voucher.Type)
+	}
+
+	if voucher.MaxDiscount != nil {
+		discount = math.Min(discount, *voucher.MaxDiscount)
+	}
+
+	return math.Round(discount*100) / 100, nil
+}
+
+// redeemVoucher mengunci voucher, menghitung ulang diskonnya, lalu memakai satu kuota.
+// Repository harus terikat pada database transaction yang sama dengan pembuatan order.
+func redeemVoucher(voucherRepo *repositories.VoucherRepository, categoryRepo *repositories.CategoryRepository, code string, userID uint, carts []models.Cart, lineDiscounts []float64, shippingCost float64, now time.Time) (*models.Voucher, float64, error) {
+	voucher, err := voucherRepo.GetByCodeForUpdate(normalizeVoucherCode(code))
+	if err != nil {
+		return nil, 0, errors.New("voucher not found")
+	}
+
+	discount, err := quoteVoucher(voucherRepo, categoryRepo, voucher, userID, carts, lineDiscounts, shippingCost, now)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := voucherRepo.Redeem(voucher.ID); err != nil {
+		if errors.Is(err, repositories.ErrVoucherExhausted) {
+			return nil, 0, err
+		}
+		return nil, 0, errors.New("failed to redeem voucher")
+	}
+
+	return voucher, discount, nil
+}
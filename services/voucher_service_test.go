@@ -0,0 +1,56 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"tokogo/models"
+)
+
+func TestCalculateVoucherDiscount(t *testing.T) {
+	maxDiscount := 5000.0
+	shoes := promotionCartLine(1, 100000, 1)
+	shoes.Product.CategoryID = 10
+	socks := promotionCartLine(2, 20000, 2)
+	socks.Product.CategoryID = 20
+
+	tests := []struct {
+		name          string
+		voucher       models.Voucher
+		carts         []models.Cart
+		lineDiscounts []float64
+		categoryScope map[uint]bool
+		want          float64
+		wantErr       string
+	}{
+		{name: "percentage", voucher: models.Voucher{Type: models.VoucherTypePercentage, Value: 10}, carts: []models.Cart{shoes, socks}, want: 14000},
+		{name: "percentage capped by max discount", voucher: models.Voucher{Type: models.VoucherTypePercentage, Value: 10, MaxDiscount: &maxDiscount}, carts: []models.Cart{shoes, socks}, want: 5000},
+		{name: "fixed never exceeds subtotal", voucher: models.Voucher{Type: models.VoucherTypeFixed, Value: 50000}, carts: []models.Cart{socks}, want: 40000},
+		{name: "free shipping", voucher: models.Voucher{Type: models.VoucherTypeFreeShipping}, carts: []models.Cart{socks}, want: 9000},
+		// Minimum belanja dibandingkan dengan subtotal setelah diskon promosi
+		{name: "min spend after promotions", voucher: models.Voucher{Type: models.VoucherTypeFixed, Value: 1000, MinSpend: 40000}, carts: []models.Cart{socks}, lineDiscounts: []float64{5000}, wantErr: "minimum spend"},
+		{name: "restricted to category", voucher: models.Voucher{Type: models.VoucherTypePercentage, Value: 10, Categories: []models.Category{{ID: 20}}}, carts: []models.Cart{shoes, socks}, categoryScope: map[uint]bool{20: true}, want: 4000},
+		{name: "restricted to product", voucher: models.Voucher{Type: models.VoucherTypePercentage, Value: 10, Products: []models.Product{{ID: 1}}}, carts: []models.Cart{shoes, socks}, want: 10000},
+		{name: "no eligible item", voucher: models.Voucher{Type: models.VoucherTypeFixed, Value: 1000, Products: []models.Product{{ID: 3}}}, carts: []models.Cart{shoes, socks}, wantErr: "does not apply"},
+		{name: "unknown type", voucher: models.Voucher{Type: "cashback", Value: 1000}, carts: []models.Cart{socks}, wantErr: "unsupported voucher type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discount, err := calculateVoucherDiscount(tt.voucher, tt.carts, tt.lineDiscounts, 9000, tt.categoryScope, time.Now())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("discount: %v", err)
+			}
+			if discount != tt.want {
+				t.Errorf("discount = %v, want %v", discount, tt.want)
+			}
+		})
+	}
+}